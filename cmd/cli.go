@@ -0,0 +1,713 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	converter "github.com/yourusername/mysql2pg/internal/converter/postgres"
+)
+
+// 退出码，便于脚本和流水线区分失败的原因
+const (
+	exitOK                 = 0   // 成功
+	exitConversionFailed   = 1   // 转换、生成迁移脚本、导出或校验过程失败
+	exitConfigError        = 2   // 命令行参数或配置错误
+	exitConnectionError    = 3   // 数据库连接失败
+	exitValidationMismatch = 4   // 运行完成，但数据校验发现不一致的表，表结构对比发现差异，或回放发现失败的查询
+	exitFastLoadIncomplete = 5   // 运行失败，快速加载的表仍为UNLOGGED或缺少主键，需要重新运行转换
+	exitInterrupted        = 130 // 收到 SIGINT 或 SIGTERM 后中断运行
+)
+
+// version 程序版本，发布构建时通过 -ldflags "-X main.version=..." 设置
+var version = "dev"
+
+// command 子命令
+type command struct {
+	name         string
+	usage        string
+	configurable bool // 是否读取配置文件并支持覆盖配置的参数
+	run          func(args []string) int
+}
+
+// commands 所有子命令，按帮助信息中的顺序排列
+var commands []command
+
+func init() {
+	commands = []command{
+		{"migrate", "执行转换（默认命令）", true, runMigrateCommand},
+		{"plan", "只生成迁移脚本，不连接PostgreSQL（等同于 migrate --dry-run）", true, runPlanCommand},
+		{"validate", "只校验MySQL和PostgreSQL中的表数据，不转换也不同步", true, runValidateCommand},
+		{"diff", "对比MySQL按转换规则得到的表结构与PostgreSQL中的实际结构", true, runDiffCommand},
+		{"assess", "迁移前评估，生成HTML或JSON格式的评估报告，不连接PostgreSQL", true, runAssessCommand},
+		{"replay", "回放MySQL查询日志，检查转换后的查询能否在PostgreSQL中执行", true, runReplayCommand},
+		{"translate", "将文件或标准输入中的MySQL SQL转换为PostgreSQL语法，不连接数据库", false, runTranslateCommand},
+		{"test-connection", "测试MySQL和PostgreSQL连接并显示版本信息", true, runTestConnectionCommand},
+		{"version", "显示版本信息", false, runVersionCommand},
+		{"completion", "生成shell补全脚本: completion bash|zsh|fish", false, runCompletionCommand},
+		{"help", "显示帮助信息，help [命令] 显示命令的参数", false, runHelpCommand},
+	}
+}
+
+// run 解析子命令并执行，返回退出码
+// 第一个参数不是子命令时按 migrate 处理，兼容 mysql2pg [配置文件路径] 和 mysql2pg -c [配置文件路径] 的用法
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		showHelp()
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+	return runMigrateCommand(args)
+}
+
+// stageOptions --only 可选的阶段及对应的 conversion.options 配置项
+var stageOptions = []struct {
+	name string
+	key  string
+}{
+	{"tables", "tableddl"},
+	{"data", "data"},
+	{"views", "view"},
+	{"indexes", "indexes"},
+	{"functions", "functions"},
+	{"users", "users"},
+	{"grant", "grant"},
+	{"privileges", "table_privileges"},
+}
+
+// configFlags 子命令的命令行参数，显式指定的参数覆盖配置文件中的对应配置
+type configFlags struct {
+	flags         *flag.FlagSet
+	configPath    string
+	tables        string
+	excludeTables string
+	only          string
+	concurrency   int
+	batchSize     int
+	syncMode      string
+	loadMode      string
+	validateMode  string
+	validate      bool
+	truncate      bool
+	dumpFile      string
+	dryRun        bool
+	format        string
+	output        string
+	rate          float64
+	logPath       string
+	prepare       bool
+	sets          setFlags
+}
+
+// setFlags 可重复指定的 --set key=value 参数
+type setFlags []string
+
+func (s *setFlags) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *setFlags) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("格式应为 key=value")
+	}
+	*s = append(*s, value)
+	return nil
+}
+
+// newConfigFlags 创建子命令的参数，migrate 命令额外支持 --dry-run，diff、assess 和 replay 命令额外支持输出格式和输出文件
+func newConfigFlags(name string) *configFlags {
+	f := &configFlags{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+	fs := f.flags
+	fs.SetOutput(os.Stdout)
+	fs.StringVar(&f.configPath, "c", "", "配置文件路径")
+	fs.StringVar(&f.configPath, "config", "", "配置文件路径")
+	fs.StringVar(&f.tables, "tables", "", "只处理这些表，逗号分隔（覆盖 use_table_list 和 table_list）")
+	fs.StringVar(&f.excludeTables, "exclude-tables", "", "跳过这些表，逗号分隔（覆盖 exclude_use_table_list 和 exclude_table_list）")
+	fs.StringVar(&f.only, "only", "", "只执行这些阶段，逗号分隔: "+stageNames())
+	fs.IntVar(&f.concurrency, "concurrency", 0, "并发数（覆盖 limits.concurrency）")
+	fs.IntVar(&f.batchSize, "batch-size", 0, "每个事务同步的行数（覆盖 limits.max_rows_per_batch）")
+	fs.StringVar(&f.syncMode, "sync-mode", "", "数据同步模式: full、incremental 或 repair（覆盖 sync_mode）")
+	fs.StringVar(&f.loadMode, "load-mode", "", "数据写入方式: copy 或 upsert（覆盖 load_mode）")
+	fs.StringVar(&f.validateMode, "validate-mode", "", "数据校验方式: count 或 checksum（覆盖 validate_mode）")
+	fs.BoolVar(&f.validate, "validate", false, "同步后校验数据（覆盖 validate_data）")
+	fs.BoolVar(&f.truncate, "truncate", false, "同步前清空表数据（覆盖 truncate_before_sync）")
+	fs.StringVar(&f.dumpFile, "dump-file", "", "从mysqldump导出文件转换（覆盖 mysql.dump_file）")
+	fs.Var(&f.sets, "set", "覆盖任意配置项，如 --set conversion.limits.batch_insert_size=5000，可重复指定")
+	switch name {
+	case "migrate":
+		fs.BoolVar(&f.dryRun, "dry-run", false, "只生成迁移脚本，不连接PostgreSQL（覆盖 run.dry_run）")
+	case "diff":
+		fs.StringVar(&f.format, "format", "text", "输出格式: text 或 json")
+		fs.StringVar(&f.output, "output", "", "输出到文件，默认输出到控制台")
+	case "assess":
+		fs.StringVar(&f.format, "format", "html", "报告格式: html 或 json")
+		fs.StringVar(&f.output, "output", "", "报告文件路径（默认 assessment.html 或 assessment.json）")
+		fs.Float64Var(&f.rate, "rate", 20, "估算复制时间使用的单表吞吐量（MB/s）")
+	case "replay":
+		fs.StringVar(&f.logPath, "log", "", "MySQL通用查询日志或慢查询日志的路径，.gz 文件自动解压（必填）")
+		fs.BoolVar(&f.prepare, "prepare", false, "只准备语句，不执行 EXPLAIN")
+		fs.StringVar(&f.format, "format", "text", "输出格式: text 或 json")
+		fs.StringVar(&f.output, "output", "", "输出到文件，默认输出到控制台")
+	}
+	fs.Usage = func() {
+		fmt.Printf("用法: mysql2pg %s [配置文件路径] [参数]\n参数:\n", name)
+		fs.PrintDefaults()
+	}
+	return f
+}
+
+// stageNames --only 可选的阶段名
+func stageNames() string {
+	names := make([]string, len(stageOptions))
+	for i, stage := range stageOptions {
+		names[i] = stage.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// parse 解析参数，配置文件路径可以出现在参数之间；返回 false 时应以返回的退出码结束
+func (f *configFlags) parse(args []string) (int, bool) {
+	for {
+		if err := f.flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return exitOK, false
+			}
+			return exitConfigError, false
+		}
+		args = f.flags.Args()
+		if len(args) == 0 {
+			return exitOK, true
+		}
+		if f.configPath != "" {
+			fmt.Printf("多余的参数: %s\n", strings.Join(args, " "))
+			return exitConfigError, false
+		}
+		f.configPath = args[0]
+		args = args[1:]
+	}
+}
+
+// overrides 将显式指定的参数转换为配置项，键为点分隔的配置路径
+func (f *configFlags) overrides() (map[string]interface{}, error) {
+	overrides := make(map[string]interface{})
+	var err error
+	f.flags.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "tables":
+			overrides["conversion.options.use_table_list"] = true
+			overrides["conversion.options.table_list"] = splitList(f.tables)
+		case "exclude-tables":
+			overrides["conversion.options.exclude_use_table_list"] = true
+			overrides["conversion.options.exclude_table_list"] = splitList(f.excludeTables)
+		case "only":
+			selected := make(map[string]bool)
+			for _, name := range splitList(f.only) {
+				known := false
+				for _, stage := range stageOptions {
+					known = known || stage.name == name
+				}
+				if !known {
+					err = fmt.Errorf("--only 不支持的阶段 %s，可选: %s", name, stageNames())
+					return
+				}
+				selected[name] = true
+			}
+			for _, stage := range stageOptions {
+				overrides["conversion.options."+stage.key] = selected[stage.name]
+			}
+		case "concurrency":
+			overrides["conversion.limits.concurrency"] = f.concurrency
+		case "batch-size":
+			overrides["conversion.limits.max_rows_per_batch"] = f.batchSize
+		case "sync-mode":
+			overrides["conversion.options.sync_mode"] = f.syncMode
+		case "load-mode":
+			overrides["conversion.options.load_mode"] = f.loadMode
+		case "validate-mode":
+			overrides["conversion.options.validate_mode"] = f.validateMode
+		case "validate":
+			overrides["conversion.options.validate_data"] = f.validate
+		case "truncate":
+			overrides["conversion.options.truncate_before_sync"] = f.truncate
+		case "dump-file":
+			overrides["mysql.dump_file"] = f.dumpFile
+		case "dry-run":
+			overrides["run.dry_run"] = f.dryRun
+		}
+	})
+	for _, set := range f.sets {
+		key, value, _ := strings.Cut(set, "=")
+		overrides[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return overrides, err
+}
+
+// load 加载配置文件并应用命令行参数，adjust 用于子命令调整配置，失败时返回 nil 和配置错误的退出码
+func (f *configFlags) load(adjust func(cfg *config.Config)) (*config.Config, int) {
+	overrides, err := f.overrides()
+	if err != nil {
+		fmt.Printf("参数错误: %v\n", err)
+		return nil, exitConfigError
+	}
+	cfg, err := config.LoadConfig(f.configPath, overrides)
+	if err != nil {
+		fmt.Printf("加载配置文件失败: %v\n", err)
+		return nil, exitConfigError
+	}
+	if adjust != nil {
+		adjust(cfg)
+	}
+	if err := cfg.ValidateConfig(); err != nil {
+		fmt.Printf("配置验证失败: %v\n", err)
+		return nil, exitConfigError
+	}
+	return cfg, exitOK
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runMigrateCommand 执行转换
+func runMigrateCommand(args []string) int {
+	f := newConfigFlags("migrate")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, code := f.load(nil)
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, nil)
+}
+
+// runPlanCommand 只生成迁移脚本
+func runPlanCommand(args []string) int {
+	f := newConfigFlags("plan")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, code := f.load(func(cfg *config.Config) {
+		cfg.Run.DryRun = true
+		cfg.Dump.Enabled = false
+	})
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, nil)
+}
+
+// runValidateCommand 只校验数据
+func runValidateCommand(args []string) int {
+	f := newConfigFlags("validate")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, code := f.load(func(cfg *config.Config) {
+		cfg.Run.DryRun = false
+		cfg.Dump.Enabled = false
+		cfg.Conversion.Options.ValidateData = true
+	})
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, func(ctx context.Context, manager *converter.Manager) int {
+		return resultCode(manager, manager.Validate(ctx), "校验")
+	})
+}
+
+// runDiffCommand 对比表结构
+func runDiffCommand(args []string) int {
+	f := newConfigFlags("diff")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	if f.format != "text" && f.format != "json" {
+		fmt.Printf("参数错误: --format 只支持 text 或 json\n")
+		return exitConfigError
+	}
+	cfg, code := f.load(func(cfg *config.Config) {
+		cfg.Run.DryRun = false
+		cfg.Dump.Enabled = false
+		// 用户和权限不参与对比
+		cfg.Conversion.Options.Users = false
+		cfg.Conversion.Options.Grant = false
+		cfg.Conversion.Options.TablePrivileges = false
+		// JSON输出到控制台时不显示其他信息
+		if f.format == "json" && f.output == "" {
+			cfg.Run.ShowConsoleLogs = false
+			cfg.Run.ShowLogInConsole = false
+		}
+	})
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, func(ctx context.Context, manager *converter.Manager) int {
+		diff, err := manager.Diff(ctx)
+		if err != nil {
+			fmt.Printf("对比表结构失败: %v\n", err)
+			return failureCode(err)
+		}
+
+		var w io.Writer = os.Stdout
+		if f.output != "" {
+			file, err := os.Create(f.output)
+			if err != nil {
+				fmt.Printf("创建输出文件失败: %v\n", err)
+				return exitConversionFailed
+			}
+			defer file.Close()
+			w = file
+		}
+		if f.format == "json" {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(diff)
+		} else {
+			diff.WriteText(w)
+		}
+		if err != nil {
+			fmt.Printf("写入对比结果失败: %v\n", err)
+			return exitConversionFailed
+		}
+		if f.output != "" {
+			fmt.Printf("对比结果已写入 %s，共 %d 处差异\n", f.output, len(diff.Differences))
+		}
+
+		if len(diff.Differences) > 0 {
+			return exitValidationMismatch
+		}
+		return exitOK
+	})
+}
+
+// runReplayCommand 回放查询日志
+func runReplayCommand(args []string) int {
+	f := newConfigFlags("replay")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	if f.logPath == "" {
+		fmt.Printf("参数错误: 需要通过 --log 指定查询日志\n")
+		return exitConfigError
+	}
+	if f.format != "text" && f.format != "json" {
+		fmt.Printf("参数错误: --format 只支持 text 或 json\n")
+		return exitConfigError
+	}
+	cfg, code := f.load(func(cfg *config.Config) {
+		cfg.Run.DryRun = false
+		cfg.Dump.Enabled = false
+		// JSON输出到控制台时不显示其他信息
+		if f.format == "json" && f.output == "" {
+			cfg.Run.ShowConsoleLogs = false
+			cfg.Run.ShowLogInConsole = false
+		}
+	})
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, func(ctx context.Context, manager *converter.Manager) int {
+		result, err := manager.Replay(ctx, f.logPath, f.prepare)
+		if err != nil {
+			fmt.Printf("回放查询日志失败: %v\n", err)
+			return failureCode(err)
+		}
+
+		var w io.Writer = os.Stdout
+		if f.output != "" {
+			file, err := os.Create(f.output)
+			if err != nil {
+				fmt.Printf("创建输出文件失败: %v\n", err)
+				return exitConversionFailed
+			}
+			defer file.Close()
+			w = file
+		}
+		if f.format == "json" {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(result)
+		} else {
+			result.WriteText(w)
+		}
+		if err != nil {
+			fmt.Printf("写入回放结果失败: %v\n", err)
+			return exitConversionFailed
+		}
+		if f.output != "" {
+			fmt.Printf("回放结果已写入 %s，%d 个查询指纹，%d 个失败\n", f.output, len(result.Queries), result.Failed)
+		}
+
+		if result.Failed > 0 {
+			return exitValidationMismatch
+		}
+		return exitOK
+	})
+}
+
+// runTestConnectionCommand 测试数据库连接
+func runTestConnectionCommand(args []string) int {
+	f := newConfigFlags("test-connection")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, code := f.load(func(cfg *config.Config) {
+		cfg.Run.DryRun = false
+		cfg.Dump.Enabled = false
+		cfg.MySQL.TestOnly = true
+		cfg.PostgreSQL.TestOnly = true
+	})
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, nil)
+}
+
+// runVersionCommand 显示版本信息
+func runVersionCommand(args []string) int {
+	fmt.Printf("mysql2pg %s (%s %s/%s)\n", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return exitOK
+}
+
+// runHelpCommand 显示帮助信息，指定子命令时显示该子命令的参数
+func runHelpCommand(args []string) int {
+	if len(args) > 0 {
+		if fs := commandFlagSet(args[0]); fs != nil {
+			fs.Usage()
+			return exitOK
+		}
+	}
+	showHelp()
+	return exitOK
+}
+
+// commandFlagSet 子命令的参数定义，用于帮助信息和补全，没有参数的子命令返回nil
+func commandFlagSet(name string) *flag.FlagSet {
+	if name == "translate" {
+		return newTranslateFlags().flags
+	}
+	for _, cmd := range commands {
+		if cmd.name == name && cmd.configurable {
+			return newConfigFlags(name).flags
+		}
+	}
+	return nil
+}
+
+// runCompletionCommand 输出shell补全脚本
+func runCompletionCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("用法: mysql2pg completion bash|zsh|fish")
+		return exitConfigError
+	}
+	script, err := completionScript(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return exitConfigError
+	}
+	fmt.Fprint(os.Stdout, script)
+	return exitOK
+}
+
+// runAssessCommand 迁移前评估
+func runAssessCommand(args []string) int {
+	f := newConfigFlags("assess")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	if f.format != "html" && f.format != "json" {
+		fmt.Printf("参数错误: --format 只支持 html 或 json\n")
+		return exitConfigError
+	}
+	if f.rate <= 0 {
+		fmt.Printf("参数错误: --rate 必须大于0\n")
+		return exitConfigError
+	}
+	if f.output == "" {
+		f.output = "assessment." + f.format
+	}
+	cfg, code := f.load(func(cfg *config.Config) {
+		// 不连接PostgreSQL，评估所有表结构、索引、视图和函数的转换
+		cfg.Run.DryRun = true
+		cfg.Dump.Enabled = false
+		cfg.Conversion.Options.TableDDL = true
+		cfg.Conversion.Options.Indexes = true
+		cfg.Conversion.Options.View = true
+		cfg.Conversion.Options.Functions = true
+	})
+	if cfg == nil {
+		return code
+	}
+	return execute(cfg, func(ctx context.Context, manager *converter.Manager) int {
+		assessment, err := manager.Assess(ctx, f.rate)
+		if err != nil {
+			fmt.Printf("评估失败: %v\n", err)
+			return failureCode(err)
+		}
+
+		file, err := os.Create(f.output)
+		if err != nil {
+			fmt.Printf("创建报告文件失败: %v\n", err)
+			return exitConversionFailed
+		}
+		defer file.Close()
+		if f.format == "json" {
+			encoder := json.NewEncoder(file)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(assessment)
+		} else {
+			err = assessment.WriteHTML(file)
+		}
+		if err != nil {
+			fmt.Printf("写入评估报告失败: %v\n", err)
+			return exitConversionFailed
+		}
+
+		assessment.WriteSummary(os.Stdout)
+		fmt.Printf("评估报告已写入 %s\n", f.output)
+		return exitOK
+	})
+}
+
+// translateFlags translate 命令的参数，该命令不读取配置文件
+type translateFlags struct {
+	flags     *flag.FlagSet
+	database  string
+	lowercase bool
+	format    string
+	output    string
+	files     []string
+}
+
+// newTranslateFlags 创建 translate 命令的参数
+func newTranslateFlags() *translateFlags {
+	f := &translateFlags{flags: flag.NewFlagSet("translate", flag.ContinueOnError)}
+	fs := f.flags
+	fs.SetOutput(os.Stdout)
+	fs.StringVar(&f.database, "database", "", "移除SQL中该MySQL数据库名的前缀")
+	fs.BoolVar(&f.lowercase, "lowercase", true, "将字符串常量以外的内容转为小写，与 lowercase_columns 转换的列名一致")
+	fs.StringVar(&f.format, "format", "sql", "输出格式: sql 或 json")
+	fs.StringVar(&f.output, "output", "", "输出到文件，默认输出到控制台")
+	fs.Usage = func() {
+		fmt.Println("用法: mysql2pg translate [文件...] [参数]")
+		fmt.Println("从文件读取MySQL SQL并转换为PostgreSQL语法，没有指定文件或文件为 - 时从标准输入读取")
+		fmt.Println("参数:")
+		fs.PrintDefaults()
+	}
+	return f
+}
+
+// parse 解析参数，文件名可以出现在参数之间；返回 false 时应以返回的退出码结束
+func (f *translateFlags) parse(args []string) (int, bool) {
+	for {
+		if err := f.flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return exitOK, false
+			}
+			return exitConfigError, false
+		}
+		args = f.flags.Args()
+		if len(args) == 0 {
+			return exitOK, true
+		}
+		f.files = append(f.files, args[0])
+		args = args[1:]
+	}
+}
+
+// translateFile 读取并转换一个文件，path 为 - 时从标准输入读取；文件在返回前关闭
+func (f *translateFlags) translateFile(path string) ([]converter.TranslatedStatement, int) {
+	var r io.Reader = os.Stdin
+	name := ""
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "打开文件失败: %v\n", err)
+			return nil, exitConfigError
+		}
+		defer file.Close()
+		r, name = file, path
+	}
+	translated, err := converter.TranslateStatements(r, name, f.database, f.lowercase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "转换失败: %v\n", err)
+		return nil, exitConversionFailed
+	}
+	return translated, exitOK
+}
+
+// runTranslateCommand 将MySQL SQL转换为PostgreSQL语法
+func runTranslateCommand(args []string) int {
+	f := newTranslateFlags()
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	if f.format != "sql" && f.format != "json" {
+		fmt.Printf("参数错误: --format 只支持 sql 或 json\n")
+		return exitConfigError
+	}
+	if len(f.files) == 0 {
+		f.files = []string{"-"}
+	}
+
+	var statements []converter.TranslatedStatement
+	for _, path := range f.files {
+		translated, code := f.translateFile(path)
+		if code != exitOK {
+			return code
+		}
+		statements = append(statements, translated...)
+	}
+
+	var w io.Writer = os.Stdout
+	if f.output != "" {
+		file, err := os.Create(f.output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "创建输出文件失败: %v\n", err)
+			return exitConversionFailed
+		}
+		defer file.Close()
+		w = file
+	}
+	var err error
+	if f.format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(statements)
+	} else {
+		err = converter.WriteTranslatedSQL(w, statements)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "写入转换结果失败: %v\n", err)
+		return exitConversionFailed
+	}
+
+	// 概要输出到标准错误，不影响输出到控制台的SQL
+	warned := 0
+	for _, stmt := range statements {
+		if len(stmt.Warnings) > 0 {
+			warned++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "已转换 %d 条语句，其中 %d 条有警告\n", len(statements), warned)
+	return exitOK
+}
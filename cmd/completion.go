@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// completionFlags 所有子命令支持的参数名（不含前缀），用于补全
+func completionFlags() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, cmd := range commands {
+		fs := commandFlagSet(cmd.name)
+		if fs == nil {
+			continue
+		}
+		fs.VisitAll(func(fl *flag.Flag) {
+			if !seen[fl.Name] {
+				seen[fl.Name] = true
+				names = append(names, fl.Name)
+			}
+		})
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completionScript 生成指定shell的补全脚本
+func completionScript(shell string) (string, error) {
+	var commandNames []string
+	for _, cmd := range commands {
+		commandNames = append(commandNames, cmd.name)
+	}
+	var flags []string
+	for _, name := range completionFlags() {
+		if len(name) == 1 {
+			flags = append(flags, "-"+name)
+		} else {
+			flags = append(flags, "--"+name)
+		}
+	}
+
+	switch shell {
+	case "bash", "zsh":
+		script := fmt.Sprintf(`# mysql2pg %s 补全脚本，使用方法: source <(mysql2pg completion %s)
+_mysql2pg() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur") $(compgen -f -- "$cur"))
+        return
+    fi
+    case "${COMP_WORDS[1]}" in
+        completion) COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur")); return ;;
+        help) COMPREPLY=($(compgen -W "%s" -- "$cur")); return ;;
+        version) return ;;
+    esac
+    case "$prev" in
+        --only) COMPREPLY=($(compgen -W "%s" -- "$cur")); return ;;
+        --sync-mode) COMPREPLY=($(compgen -W "full incremental repair" -- "$cur")); return ;;
+        --load-mode) COMPREPLY=($(compgen -W "copy upsert" -- "$cur")); return ;;
+        --validate-mode) COMPREPLY=($(compgen -W "count checksum" -- "$cur")); return ;;
+        --format) COMPREPLY=($(compgen -W "text json html sql" -- "$cur")); return ;;
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+`, shell, shell, strings.Join(commandNames, " "), strings.Join(commandNames, " "), strings.ReplaceAll(stageNames(), ", ", " "), strings.Join(flags, " "))
+		if shell == "zsh" {
+			// zsh 通过 bashcompinit 使用bash补全函数
+			script += "autoload -U +X bashcompinit && bashcompinit\n"
+		}
+		return script + "complete -o default -F _mysql2pg mysql2pg\n", nil
+
+	case "fish":
+		var b strings.Builder
+		b.WriteString("# mysql2pg fish 补全脚本，使用方法: mysql2pg completion fish > ~/.config/fish/completions/mysql2pg.fish\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(&b, "complete -c mysql2pg -n __fish_use_subcommand -a %s -d '%s'\n", cmd.name, cmd.usage)
+		}
+		b.WriteString("complete -c mysql2pg -n '__fish_seen_subcommand_from completion' -x -a 'bash zsh fish'\n")
+		for _, cmd := range commands {
+			fs := commandFlagSet(cmd.name)
+			if fs == nil {
+				continue
+			}
+			fs.VisitAll(func(fl *flag.Flag) {
+				option := "-l " + fl.Name
+				if len(fl.Name) == 1 {
+					option = "-s " + fl.Name
+				}
+				fmt.Fprintf(&b, "complete -c mysql2pg -n '__fish_seen_subcommand_from %s' %s -d '%s'\n", cmd.name, option, strings.ReplaceAll(fl.Usage, "'", `\'`))
+			})
+		}
+		fmt.Fprintf(&b, "complete -c mysql2pg -l only -x -a '%s'\n", strings.ReplaceAll(stageNames(), ", ", " "))
+		b.WriteString("complete -c mysql2pg -n '__fish_seen_subcommand_from diff replay' -l format -x -a 'text json'\n")
+		b.WriteString("complete -c mysql2pg -n '__fish_seen_subcommand_from assess' -l format -x -a 'html json'\n")
+		b.WriteString("complete -c mysql2pg -n '__fish_seen_subcommand_from translate' -l format -x -a 'sql json'\n")
+		return b.String(), nil
+	}
+	return "", fmt.Errorf("不支持的shell: %s，可选 bash、zsh 或 fish", shell)
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/yourusername/mysql2pg/internal/config"
 	converter "github.com/yourusername/mysql2pg/internal/converter/postgres"
@@ -20,80 +24,112 @@ func main() {
 		http.ListenAndServe("localhost:6060", nil)
 	}()
 
-	// 解析命令行参数
-	var configPath string
-	for i := 1; i < len(os.Args); i++ {
-		if os.Args[i] == "-h" || os.Args[i] == "--help" {
-			showHelp()
-			return
-		} else if os.Args[i] == "-c" && i+1 < len(os.Args) {
-			configPath = os.Args[i+1]
-			i++
-		} else if configPath == "" {
-			configPath = os.Args[i]
-		}
-	}
+	os.Exit(run(os.Args[1:]))
+}
 
-	// 如果没有指定配置文件，显示帮助信息
-	if configPath == "" {
-		showHelp()
-		return
-	}
+// execute 连接数据库并执行转换，返回退出码
+// action 不为nil时连接数据库后执行 action 代替转换，如只校验数据、对比表结构或评估；
+// dry-run 和导出到文件时不连接PostgreSQL，设置了 mysql.dump_file 时不连接MySQL；
+// 收到 SIGINT 或 SIGTERM 时取消 ctx，回滚未提交的批次后退出
+func execute(cfg *config.Config, action func(ctx context.Context, manager *converter.Manager) int) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopSignals := cancelOnSignal(cancel)
+	defer stopSignals()
 
-	cfg, err := config.LoadConfig(configPath)
-	if err != nil {
-		fmt.Printf("加载配置文件失败: %v\n", err)
-		os.Exit(1)
+	// 从mysqldump导出文件转换，不连接MySQL
+	var dumpFile *mysql.DumpFile
+	if cfg.MySQL.DumpFile != "" && cfg.MySQL.TestOnly {
+		// 只测试连接时不解析导出文件
+		if _, err := os.Stat(cfg.MySQL.DumpFile); err != nil {
+			fmt.Printf("导出文件不可用: %v\n", err)
+			return exitConfigError
+		}
+	} else if cfg.MySQL.DumpFile != "" {
+		var err error
+		dumpFile, err = mysql.OpenDumpFile(cfg.MySQL.DumpFile)
+		if err != nil {
+			fmt.Printf("解析导出文件失败: %v\n", err)
+			return exitConfigError
+		}
+		if cfg.Run.ShowConsoleLogs {
+			fmt.Printf("导出文件 %s: %d 个表，%d 个视图，%d 个函数\n", cfg.MySQL.DumpFile, len(dumpFile.Tables), len(dumpFile.Views), len(dumpFile.Functions))
+		}
+		// 视图转换时按数据库名去除定义中的库名前缀
+		if cfg.MySQL.Database == "" {
+			cfg.MySQL.Database = dumpFile.Database
+		}
+	} else if err := mysql.TestConnection(&cfg.MySQL); err != nil {
+		// 测试MySQL连接
+		fmt.Printf("MySQL连接测试失败: %v\n", err)
+		return exitConnectionError
 	}
 
-	// 验证配置
-	if err := cfg.ValidateConfig(); err != nil {
-		fmt.Printf("配置验证失败: %v\n", err)
-		os.Exit(1)
+	// 创建MySQL连接
+	var mysqlConn *mysql.Connection
+	if cfg.MySQL.DumpFile == "" {
+		var err error
+		mysqlConn, err = mysql.NewConnection(&cfg.MySQL)
+		if err != nil {
+			fmt.Printf("创建MySQL连接失败: %v\n", err)
+			return exitConnectionError
+		}
+		defer mysqlConn.Close()
 	}
 
-	// 测试MySQL连接
-	if err := mysql.TestConnection(&cfg.MySQL); err != nil {
-		fmt.Printf("MySQL连接测试失败: %v\n", err)
-		os.Exit(1)
+	// 只生成迁移脚本、导出到文件或评估，不连接PostgreSQL
+	if cfg.Run.DryRun || cfg.Dump.Enabled {
+		manager, err := converter.NewManager(mysqlConn, nil, cfg)
+		if err != nil {
+			fmt.Printf("创建转换管理器失败: %v\n", err)
+			return exitConfigError
+		}
+		defer manager.Close()
+		if dumpFile != nil {
+			manager.SetDumpFile(dumpFile)
+		}
+
+		if action != nil {
+			return action(ctx, manager)
+		}
+		if cfg.Run.DryRun {
+			return resultCode(manager, manager.Plan(ctx), "生成迁移脚本")
+		}
+		return resultCode(manager, manager.Dump(ctx), "导出")
 	}
 
 	// 测试PostgreSQL连接
 	if err := pgconn.TestConnection(&cfg.PostgreSQL); err != nil {
 		fmt.Printf("PostgreSQL连接测试失败: %v\n", err)
-		os.Exit(1)
+		return exitConnectionError
 	}
 
-	// 创建MySQL连接
-	mysqlConn, err := mysql.NewConnection(&cfg.MySQL)
-	if err != nil {
-		fmt.Printf("创建MySQL连接失败: %v\n", err)
-		os.Exit(1)
-	}
-	defer mysqlConn.Close()
-
 	postgresConn, err := pgconn.NewConnection(&cfg.PostgreSQL)
 	if err != nil {
 		fmt.Printf("创建PostgreSQL连接失败: %v\n", err)
-		os.Exit(1)
+		return exitConnectionError
 	}
 	defer postgresConn.Close()
 
-	// 显示数据库版本信息
-	mysqlVersion, err := mysqlConn.GetVersion()
-	if err != nil {
-		fmt.Printf("获取MySQL版本失败: %v\n", err)
-		os.Exit(1)
+	// 显示数据库版本信息，数据源为导出文件时显示文件路径
+	mysqlVersion := "导出文件 " + cfg.MySQL.DumpFile
+	if mysqlConn != nil {
+		mysqlVersion, err = mysqlConn.GetVersion(ctx)
+		if err != nil {
+			fmt.Printf("获取MySQL版本失败: %v\n", err)
+			return exitConnectionError
+		}
 	}
 
-	postgresVersion, err := postgresConn.GetVersion()
+	postgresVersion, err := postgresConn.GetVersion(ctx)
 	if err != nil {
 		fmt.Printf("获取PostgreSQL版本失败: %v\n", err)
-		os.Exit(1)
+		return exitConnectionError
 	}
 
 	// 显示测试连接成功信息
-	if cfg.MySQL.TestOnly || cfg.PostgreSQL.TestOnly {
+	testOnly := cfg.MySQL.TestOnly || cfg.PostgreSQL.TestOnly
+	if testOnly {
 		fmt.Println("\n+-------------------------------------------------------------+")
 		if cfg.MySQL.TestOnly {
 			fmt.Println("1. MySQL连接测试完成，版本信息已显示，退出程序。")
@@ -103,7 +139,66 @@ func main() {
 		}
 	}
 
-	// 使用表格形式显示版本信息
+	// 使用表格形式显示版本信息，关闭控制台日志时只在测试连接时显示
+	if cfg.Run.ShowConsoleLogs || testOnly {
+		printVersions(mysqlVersion, postgresVersion)
+	}
+
+	// 如果仅测试MySQL连接，退出
+	if cfg.MySQL.TestOnly {
+		return exitOK
+	}
+
+	// 如果仅测试PostgreSQL连接，退出
+	if cfg.PostgreSQL.TestOnly {
+		return exitOK
+	}
+
+	// 创建转换管理器并运行转换
+	manager, err := converter.NewManager(mysqlConn, postgresConn, cfg)
+	if err != nil {
+		fmt.Printf("创建转换管理器失败: %v\n", err)
+		return exitConfigError
+	}
+	defer manager.Close()
+	if dumpFile != nil {
+		manager.SetDumpFile(dumpFile)
+	}
+
+	if action != nil {
+		return action(ctx, manager)
+	}
+
+	// 显式开启时注册限速调整接口，运行期间可通过 http://localhost:6060/throttle 查看吞吐量，POST 请求调整限速
+	if cfg.Conversion.Limits.ThrottleEndpoint {
+		http.Handle("/throttle", manager.Limiter())
+	}
+
+	return resultCode(manager, manager.Run(ctx), "转换")
+}
+
+// cancelOnSignal 收到 SIGINT 或 SIGTERM 时调用 cancel，之后恢复默认的信号处理，再次收到信号时立即退出；
+// 返回的函数停止监听信号
+func cancelOnSignal(cancel context.CancelFunc) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig, ok := <-signals
+		if !ok {
+			return
+		}
+		signal.Stop(signals)
+		fmt.Printf("\n收到信号 %v，正在回滚未提交的批次并保存进度，再次发送信号立即退出\n", sig)
+		cancel()
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+// printVersions 使用表格形式显示数据库版本信息
+func printVersions(mysqlVersion, postgresVersion string) {
 	fmt.Println("+-------------------------------------------------------------+")
 	fmt.Println("| 数据库版本信息:                                             |")
 	fmt.Println("+--------------+----------------------------------------------+")
@@ -132,39 +227,63 @@ func main() {
 
 	fmt.Println("+--------------+----------------------------------------------+")
 	fmt.Println()
+}
 
-	// 如果仅测试MySQL连接，退出
-	if cfg.MySQL.TestOnly {
-		return
+// resultCode 根据运行结果确定退出码：运行失败、被中断、数据校验不一致或成功
+func resultCode(manager *converter.Manager, err error, action string) int {
+	if err != nil {
+		fmt.Printf("%s失败: %v\n", action, err)
+		return failureCode(err)
 	}
-
-	// 如果仅测试PostgreSQL连接，退出
-	if cfg.PostgreSQL.TestOnly {
-		return
+	if len(manager.InconsistentTables()) > 0 {
+		return exitValidationMismatch
 	}
+	return exitOK
+}
 
-	// 创建转换管理器并运行转换
-	manager, err := converter.NewManager(mysqlConn, postgresConn, cfg)
-	if err != nil {
-		fmt.Printf("创建转换管理器失败: %v\n", err)
-		os.Exit(1)
+// failureCode 运行失败时的退出码，收到信号中断时返回 exitInterrupted，
+// 快速加载的表未完成收尾时返回 exitFastLoadIncomplete
+func failureCode(err error) int {
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted
 	}
-	defer manager.Close()
-
-	if err := manager.Run(); err != nil {
-		fmt.Printf("转换失败: %v\n", err)
-		os.Exit(1)
+	if errors.Is(err, converter.ErrFastLoadIncomplete) {
+		return exitFastLoadIncomplete
 	}
+	return exitConversionFailed
 }
 
 // showHelp 显示帮助信息
 func showHelp() {
 	fmt.Println("MySQL2PG - 高性能MySQL到PostgreSQL转换工具")
 	fmt.Println("使用方法:")
-	fmt.Println("  mysql2pg [配置文件路径]")
+	fmt.Println("  mysql2pg <命令> [配置文件路径] [参数]")
+	fmt.Println("  mysql2pg [配置文件路径]  等同于 mysql2pg migrate [配置文件路径]")
 	fmt.Println("  mysql2pg -c [配置文件路径]")
+	fmt.Println("  mysql2pg translate [文件...] [参数]  转换SQL，不需要配置文件")
 	fmt.Println("  mysql2pg -h|--help 显示帮助信息")
 	fmt.Println()
+	fmt.Println("命令:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-16s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Println()
+	fmt.Println("常用参数（显式指定时覆盖配置文件，mysql2pg help <命令> 查看全部参数）:")
+	fmt.Println("  -c, --config <路径>      配置文件路径")
+	fmt.Println("  --tables a,b             只处理这些表")
+	fmt.Println("  --exclude-tables a,b     跳过这些表")
+	fmt.Println("  --only data,indexes      只执行这些阶段: " + stageNames())
+	fmt.Println("  --concurrency <n>        并发数")
+	fmt.Println("  --batch-size <n>         每个事务同步的行数")
+	fmt.Println("  --set key=value          覆盖任意配置项，可重复指定")
+	fmt.Println("  --dry-run                只生成迁移脚本，不连接PostgreSQL（migrate）")
+	fmt.Println("  --format <格式>          输出格式，diff、replay: text|json，assess: html|json，translate: sql|json")
+	fmt.Println("  --output <路径>          对比结果、评估报告、回放结果或转换结果的输出文件（diff、assess、replay、translate）")
+	fmt.Println("  --log <路径>             要回放的MySQL通用查询日志或慢查询日志（replay）")
+	fmt.Println()
+	fmt.Println("退出码:")
+	fmt.Println("  0 成功；1 转换或校验过程失败；2 参数或配置错误；3 数据库连接失败；4 数据校验发现不一致的表、表结构对比发现差异或回放发现失败的查询；5 快速加载的表未完成收尾；130 收到 SIGINT 或 SIGTERM 中断")
+	fmt.Println()
 	fmt.Println("配置文件说明:")
 	fmt.Println("  配置文件为YAML格式，包含MySQL连接信息、PostgreSQL连接信息、转换选项等")
 	fmt.Println("  可参考config.example.yml创建配置文件")
@@ -180,6 +299,7 @@ func showHelp() {
 	fmt.Println("  max_idle_conns: 连接池配置的最大空闲连接数 (默认: 50)")
 	fmt.Println("  conn_max_lifetime: 连接池配置的最大生命周期（秒） (默认: 3600)")
 	fmt.Println("  connection_params: MySQL连接参数 (默认: charset=utf8mb4&parseTime=false&interpolateParams=true)")
+	fmt.Println("  dump_file: mysqldump导出文件路径，支持 .gz，设置后从文件读取表结构和数据，不连接MySQL (默认: 空)")
 	fmt.Println()
 	fmt.Println("PostgreSQL连接配置:")
 	fmt.Println("  host: PostgreSQL主机地址 (默认: localhost)")
@@ -207,17 +327,56 @@ func showHelp() {
 	fmt.Println("    exclude_table_list: 要跳过的表列表 (当exclude_use_table_list为true时生效)")
 	fmt.Println("    lowercase_columns: 控制表字段是否需要转小写 (默认: false)")
 	fmt.Println("    validate_data: 同步数据后验证数据一致性 (默认: true)")
+	fmt.Println("    validate_mode: 数据校验方式，count 比较行数，checksum 按主键分块比较行内容校验和 (默认: count)")
 	fmt.Println("    truncate_before_sync: 同步前是否清空表数据 (默认: true)")
+	fmt.Println("    sync_mode: 数据同步模式，full 全量同步，incremental 按水位列增量同步，repair 只修复内容校验和不一致的主键范围 (默认: full)")
+	fmt.Println("    load_mode: 数据写入方式，copy 直接COPY写入，upsert 经临时中转表按主键合并写入，可重复执行 (默认: copy)")
+	fmt.Println("    reject_mode: 写入失败行的处理方式，off 整表失败，file 隔离到 reject_file，table 隔离到 mysql2pg_rejects 表 (默认: off)")
+	fmt.Println("    reject_file: reject_mode 为 file 时隔离行的保存路径 (默认: ./rejects.jsonl)")
+	fmt.Println("    json_mode: MySQL json 列的目标类型，json 或 jsonb (默认: json)")
+	fmt.Println("    fast_load: 快速加载，UNLOGGED建表并延后主键、索引和约束，数据完成后创建并SET LOGGED、ANALYZE (默认: false)")
+	fmt.Println("    estimate_row_counts: 使用information_schema.TABLES的估计行数显示进度，代替COUNT(*) (默认: false)")
+	fmt.Println()
+	fmt.Println("  增量同步配置 (sync_mode 为 incremental 时生效):")
+	fmt.Println("    state_file: 水位状态文件路径 (默认: ./incremental_state.json)")
+	fmt.Println("    tables: 需要增量同步的表及水位列，格式为 [{table: 表名, watermark_column: 水位列}]")
+	fmt.Println("  单表同步配置 (conversion.tables):")
+	fmt.Println("    table: MySQL表名")
+	fmt.Println("    where: 数据过滤条件（MySQL语法，不含WHERE），同时用于行数校验")
+	fmt.Println("    include_columns / exclude_columns: 只同步指定的列 / 不同步的列，不能排除主键列")
+	fmt.Println("    target_table: PostgreSQL中的目标表名 (默认: 与MySQL表名相同)")
+	fmt.Println("  列数据脱敏配置 (conversion.masking):")
+	fmt.Println("    salt: 哈希和随机化使用的盐值，相同盐值下脱敏结果确定")
+	fmt.Println("    rules: 脱敏规则，格式为 [{table: 表名或*, column: 列名, action: hash|constant|null|format|truncate, value: 常量, length: 截断长度}]")
+	fmt.Println()
+	fmt.Println("  无效数据处理策略 (conversion.invalid_data):")
+	fmt.Println("    nul_bytes: 文本中的\\x00，error|null|replace|strip (默认: error)")
+	fmt.Println("    invalid_utf8: 无效的UTF-8字节序列，error|null|replace|strip (默认: error)")
+	fmt.Println("    zero_date: 0000-00-00 零值日期，error|null|clamp (默认: null)")
+	fmt.Println("    invalid_date: 不存在的日期（如 2023-02-30），error|null|clamp (默认: error)")
+	fmt.Println("    time_overflow: 超出 00:00:00~24:00:00 的TIME值，error|null|clamp (默认: error)")
+	fmt.Println("    invalid_json: 写入json/jsonb列的无效JSON文档，error|null (默认: error)")
+	fmt.Println()
+	fmt.Println("  UUID列配置 (conversion.uuid，只对 BINARY(16) 列生效):")
+	fmt.Println("    detect: 按列名自动识别UUID列 (默认: false)")
+	fmt.Println("    name_pattern: 自动识别使用的列名正则 (默认: (?i)(uuid|guid)$)")
+	fmt.Println("    swap_flag: 自动识别的列是否以 UUID_TO_BIN(uuid, 1) 的字节顺序存储 (默认: false)")
+	fmt.Println("    columns: 指定的UUID列，格式为 [{table: 表名或*, column: 列名, swap_flag: true|false}]")
+	fmt.Println("  JSON文本列配置 (conversion.json_columns):")
+	fmt.Println("    转换为 jsonb 的文本列，格式为 [{table: 表名或*, column: 列名}]")
 	fmt.Println()
 	fmt.Println("  限制配置:")
 	fmt.Println("    concurrency: 并发数限制 (默认: 10)")
-	fmt.Println("    bandwidth_mbps: 网络带宽限制(Mbps) (默认: 100)")
+	fmt.Println("    bandwidth_mbps: 所有表合计从MySQL读取数据的带宽限制(Mbps)，0表示不限制 (默认: 0)")
+	fmt.Println("    rows_per_second: 所有表合计每秒读取的行数限制，0表示不限制 (默认: 0)")
+	fmt.Println("    throttle_endpoint: 在 localhost:6060/throttle 注册限速调整接口，GET 查看吞吐量，POST 调整限速 (默认: false)")
 	fmt.Println("    max_ddl_per_batch: 一次性转换DDL的个数限制 (默认: 10)")
 	fmt.Println("    max_functions_per_batch: 一次性转换function的个数限制 (默认: 5)")
 	fmt.Println("    max_indexes_per_batch: 一次性转换index的个数限制 (默认: 20)")
 	fmt.Println("    max_users_per_batch: 一次性转换用户的个数限制 (默认: 10)")
 	fmt.Println("    max_rows_per_batch: 一次性同步数据的行数限制 (默认: 10000)")
 	fmt.Println("    batch_insert_size: 批量插入的大小 (默认: 10000)")
+	fmt.Println("    checksum_chunk_size: 校验和比较时每个主键分块的行数 (默认: 100000)")
 	fmt.Println()
 	fmt.Println("运行配置:")
 	fmt.Println("  show_progress: 显示任务进度 (默认: true)")
@@ -226,6 +385,17 @@ func showHelp() {
 	fmt.Println("  log_file_path: 日志文件保存路径 (默认: ./conversion.log)")
 	fmt.Println("  show_console_logs: 是否在控制台显示日志信息 (默认: true)")
 	fmt.Println("  show_log_in_console: 是否在控制台显示Log日志输出 (默认: false)")
+	fmt.Println("  dry_run: 只生成迁移脚本，不连接PostgreSQL (默认: false)")
+	fmt.Println("  plan_dir: 迁移脚本的输出目录 (默认: ./plan)")
+	fmt.Println("  report_dir: 运行报告的输出目录 (默认: ./report)")
+	fmt.Println("  report_formats: 运行报告的格式，json、junit、html (默认: 全部)")
+	fmt.Println()
+	fmt.Println("导出到文件配置 (dump):")
+	fmt.Println("  enabled: 导出表结构和数据到SQL文件，不连接PostgreSQL (默认: false)")
+	fmt.Println("  dir: 输出目录 (默认: ./dump)")
+	fmt.Println("  per_table: 每个表的数据写入单独的文件 (默认: false)")
+	fmt.Println("  compress: 使用gzip压缩输出文件 (默认: false)")
+	fmt.Println("  split_size_mb: 单个文件超过该大小时拆分，0为不拆分 (默认: 0)")
 	fmt.Println()
 	fmt.Println("重要功能说明:")
 	fmt.Println("  1. test_only模式: 仅测试数据库连接，不执行转换，连接测试响应时间<1秒")
@@ -235,4 +405,28 @@ func showHelp() {
 	fmt.Println("  5. 零数据处理: 支持零数据行表的完整同步流程，包括进度显示和验证")
 	fmt.Println("  6. 完整的资源清理: 所有数据库连接和资源都会被正确释放，避免资源泄漏")
 	fmt.Println("  7. 完善的性能分析: 监听本地 6060 端口，用于性能分析")
+	fmt.Println("  8. 增量同步: 每次只同步水位列大于上次记录值的数据，按主键upsert写入PostgreSQL")
+	fmt.Println("  9. upsert写入: load_mode为upsert时经临时中转表按主键合并写入，重复执行不产生重复数据")
+	fmt.Println("  10. 内容校验: validate_mode为checksum时按主键分块比较两端行内容的校验和，输出不一致的主键范围和示例行")
+	fmt.Println("  11. 修复模式: sync_mode为repair时只执行数据阶段，删除并重新复制校验和不一致的主键范围，然后重新校验")
+	fmt.Println("  12. 单表同步配置: 按表配置数据过滤条件、同步的列和目标表名，同时作用于表结构、数据同步和数据校验")
+	fmt.Println("  13. 列数据脱敏: 写入PostgreSQL前按规则对敏感列进行哈希、替换、置空、保留格式随机化或截断，并输出脱敏列审计")
+	fmt.Println("  14. 数据同步限速: 所有同步协程共享带宽和行数限制，throttle_endpoint为true时运行期间可通过 POST http://localhost:6060/throttle?bandwidth_mbps=50&rows_per_second=20000 调整，汇总中显示实际吞吐量")
+	fmt.Println("  15. 流式数据同步: 每个表的读取端和写入端并行运行，MySQL读取和PostgreSQL COPY相互重叠，没有单列主键的表不再使用OFFSET分页")
+	fmt.Println("  16. 坏行隔离: reject_mode为file或table时，写入失败的批次按保存点二分定位出错的行并隔离，其余行正常写入，汇总中显示各表隔离行数")
+	fmt.Println("  17. 无效数据处理: 按问题类型配置 \\x00、无效UTF-8、零值日期、不存在的日期和超范围TIME值的处理策略，汇总中显示各表的处理数量")
+	fmt.Println("  18. BIT和UUID列: BIT(1)转换为BOOLEAN，BIT(n)转换为BIT VARYING(n)；配置的BINARY(16)列转换为uuid，支持UUID_TO_BIN的swap_flag字节顺序")
+	fmt.Println("  19. JSONB转换: json_mode为jsonb时json列转换为JSONB，json_columns中的文本列也转换为JSONB，写入前校验JSON文档，无效文档按invalid_json策略隔离或置为NULL")
+	fmt.Println("  20. 快速加载: fast_load为true时UNLOGGED建表，以session_replication_role=replica写入，数据同步后并行创建主键和索引（upsert时主键随表创建）、补建CHECK约束、SET LOGGED并ANALYZE")
+	fmt.Println("  21. 按表大小调度: 根据information_schema.TABLES的统计信息，最大的表最先开始同步，整体进度和预计剩余时间按数据量计算")
+	fmt.Println("  22. 迁移脚本: dry_run为true或使用--dry-run时，执行所有转换并按阶段写入编号的 .sql 文件到plan_dir，转换警告以SQL注释写入，不连接PostgreSQL")
+	fmt.Println("  23. 导出到文件: dump.enabled为true时将表结构和COPY格式的数据写入编号的SQL文件，可按表拆分、gzip压缩和按大小拆分，使用psql加载")
+	fmt.Println("  24. 从mysqldump文件转换: 设置mysql.dump_file后解析导出文件中的表、视图、函数和INSERT数据，经相同的转换器COPY写入PostgreSQL，不需要MySQL连接")
+	fmt.Println("  25. 命令行: migrate、plan、validate、test-connection 等子命令，命令行参数覆盖配置项，区分失败原因的退出码，支持bash/zsh/fish补全")
+	fmt.Println("  26. 表结构对比: diff 命令按转换规则对比MySQL与PostgreSQL的表、列类型、是否允许NULL、默认值、主键、索引、视图和函数，以文本或JSON输出差异")
+	fmt.Println("  27. 迁移评估: assess 命令不连接PostgreSQL，以分析方式执行所有转换，生成包含对象清单、各表数据量、有损类型、需手工处理的视图和函数、无主键表、排序规则、分区和估计复制时间的HTML/JSON报告")
+	fmt.Println("  28. SQL转换: translate 命令从文件或标准输入读取任意MySQL SQL，使用视图转换规则转换为PostgreSQL语法，并以注释逐条给出无法自动转换的写法；Go代码可调用 TranslateSQL 和 TranslateStatements")
+	fmt.Println("  29. 查询日志回放: replay 命令读取MySQL通用查询日志或慢查询日志，按指纹去重后转换，在PostgreSQL中以 EXPLAIN 或 PREPARE 检查，按出现次数报告每个指纹能否执行")
+	fmt.Println("  30. 运行报告: 每次转换结束（包括失败）后在report_dir写入 mysql2pg-report.json、.xml（JUnit）和 .html，包含各阶段耗时、每个对象的状态、错误和生成的SQL、每个表的行数、吞吐量和校验结果")
+	fmt.Println("  31. 优雅中断: Ctrl-C 或 SIGTERM 时不再开始新的表，回滚正在写入的批次，保存已完成表的增量同步水位，显示已完成部分的汇总并以退出码130退出，再次发送信号立即退出")
 }
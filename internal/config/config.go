@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,6 +18,7 @@ type Config struct {
 	PostgreSQL PostgreSQLConfig `mapstructure:"postgresql"`
 	Conversion ConversionConfig `mapstructure:"conversion"`
 	Run        RunConfig        `mapstructure:"run"`
+	Dump       DumpConfig       `mapstructure:"dump"`
 }
 
 // MySQLConfig MySQL连接配置
@@ -29,6 +33,7 @@ type MySQLConfig struct {
 	MaxIdleConns     int           `mapstructure:"max_idle_conns"`    // 最大空闲连接数
 	ConnMaxLifetime  time.Duration `mapstructure:"conn_max_lifetime"` // 连接最大生命周期（秒）
 	ConnectionParams string        `mapstructure:"connection_params"` // MySQL连接参数
+	DumpFile         string        `mapstructure:"dump_file"`         // mysqldump导出文件，设置后从该文件读取表结构和数据，不连接MySQL
 }
 
 type PostgreSQLConfig struct {
@@ -44,8 +49,248 @@ type PostgreSQLConfig struct {
 
 // ConversionConfig 转换配置
 type ConversionConfig struct {
-	Options OptionsConfig `mapstructure:"options"`
-	Limits  LimitsConfig  `mapstructure:"limits"`
+	Options     OptionsConfig     `mapstructure:"options"`
+	Limits      LimitsConfig      `mapstructure:"limits"`
+	Incremental IncrementalConfig `mapstructure:"incremental"`
+	Tables      []TableConfig     `mapstructure:"tables"`       // 单表同步配置（数据过滤、列裁剪、目标表名）
+	Masking     MaskingConfig     `mapstructure:"masking"`      // 列数据脱敏配置
+	InvalidData InvalidDataConfig `mapstructure:"invalid_data"` // MySQL中PostgreSQL不接受的数据的处理策略
+	UUID        UUIDConfig        `mapstructure:"uuid"`         // BINARY(16) 列转换为 uuid 的规则
+	JSONColumns []JSONColumn      `mapstructure:"json_columns"` // 存储JSON文档、需要转换为 jsonb 的文本列
+}
+
+// JSONColumn 转换为 jsonb 的文本列
+type JSONColumn struct {
+	Table  string `mapstructure:"table"`  // MySQL表名，* 表示所有表
+	Column string `mapstructure:"column"` // 列名（不区分大小写）
+}
+
+// IsJSONColumn 判断文本列是否配置为转换为 jsonb
+func (c *ConversionConfig) IsJSONColumn(tableName, column string) bool {
+	for _, rule := range c.JSONColumns {
+		if strings.EqualFold(rule.Column, column) && (rule.Table == tableName || rule.Table == "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// UUIDConfig BINARY(16) 列转换为 uuid 的规则，只对类型为 BINARY(16) 的列生效
+type UUIDConfig struct {
+	Detect      bool         `mapstructure:"detect"`       // 按列名自动识别UUID列
+	NamePattern string       `mapstructure:"name_pattern"` // 自动识别使用的列名正则 (默认: (?i)(uuid|guid)$)
+	SwapFlag    bool         `mapstructure:"swap_flag"`    // 自动识别的列是否以 UUID_TO_BIN(uuid, 1) 的字节顺序存储
+	Columns     []UUIDColumn `mapstructure:"columns"`      // 指定的UUID列，优先于自动识别
+}
+
+// UUIDColumn 指定的UUID列
+type UUIDColumn struct {
+	Table    string `mapstructure:"table"`     // MySQL表名，* 表示所有表
+	Column   string `mapstructure:"column"`    // 列名（不区分大小写）
+	SwapFlag bool   `mapstructure:"swap_flag"` // 是否以 UUID_TO_BIN(uuid, 1) 的字节顺序存储
+}
+
+// FindColumn 判断 BINARY(16) 列是否转换为 uuid，返回是否交换了时间字段的字节顺序
+// 精确匹配表名的规则优先于 * 规则，都未匹配时按列名自动识别
+func (c *UUIDConfig) FindColumn(tableName, column, columnType string) (swapFlag bool, ok bool) {
+	if !strings.EqualFold(strings.TrimSpace(columnType), "binary(16)") {
+		return false, false
+	}
+
+	var wildcard *UUIDColumn
+	for i := range c.Columns {
+		rule := &c.Columns[i]
+		if !strings.EqualFold(rule.Column, column) {
+			continue
+		}
+		if rule.Table == tableName {
+			return rule.SwapFlag, true
+		}
+		if rule.Table == "*" && wildcard == nil {
+			wildcard = rule
+		}
+	}
+	if wildcard != nil {
+		return wildcard.SwapFlag, true
+	}
+
+	if c.Detect {
+		if re, err := regexp.Compile(c.NamePattern); err == nil && re.MatchString(column) {
+			return c.SwapFlag, true
+		}
+	}
+	return false, false
+}
+
+// InvalidDataConfig 各类无效数据的处理策略
+type InvalidDataConfig struct {
+	NulBytes     string `mapstructure:"nul_bytes"`     // 文本中的 \x00：error、null、replace、strip (默认: error)
+	InvalidUTF8  string `mapstructure:"invalid_utf8"`  // 无效的UTF-8字节序列：error、null、replace、strip (默认: error)
+	ZeroDate     string `mapstructure:"zero_date"`     // 0000-00-00 零值日期：error、null、clamp (默认: null)
+	InvalidDate  string `mapstructure:"invalid_date"`  // 不存在的日期，如 2023-02-30：error、null、clamp (默认: error)
+	TimeOverflow string `mapstructure:"time_overflow"` // 超出 00:00:00~24:00:00 的TIME值：error、null、clamp (默认: error)
+	InvalidJSON  string `mapstructure:"invalid_json"`  // 写入json/jsonb列的无效JSON文档：error、null (默认: error)
+}
+
+// 无效数据的处理策略
+const (
+	InvalidDataError   = "error"   // 原样写入，由PostgreSQL报错（可配合 reject_mode 隔离）
+	InvalidDataNull    = "null"    // 置为NULL
+	InvalidDataReplace = "replace" // 替换为 U+FFFD
+	InvalidDataClamp   = "clamp"   // 调整到最接近的有效值
+	InvalidDataStrip   = "strip"   // 删除无效的字符
+)
+
+// MaskingConfig 列数据脱敏配置
+type MaskingConfig struct {
+	Salt  string        `mapstructure:"salt"`  // 哈希和随机化使用的盐值，相同盐值下结果确定
+	Rules []MaskingRule `mapstructure:"rules"` // 脱敏规则
+}
+
+// MaskingRule 单列脱敏规则
+type MaskingRule struct {
+	Table  string `mapstructure:"table"`  // MySQL表名，* 表示所有表
+	Column string `mapstructure:"column"` // 列名（不区分大小写）
+	Action string `mapstructure:"action"` // 脱敏方式：hash、constant、null、format、truncate
+	Value  string `mapstructure:"value"`  // action 为 constant 时替换的值
+	Length int    `mapstructure:"length"` // action 为 truncate 时保留的字符数
+}
+
+// 脱敏方式
+const (
+	MaskActionHash     = "hash"     // 加盐哈希
+	MaskActionConstant = "constant" // 替换为常量
+	MaskActionNull     = "null"     // 置为NULL
+	MaskActionFormat   = "format"   // 保留格式的随机化（手机号、邮箱等）
+	MaskActionTruncate = "truncate" // 截断
+)
+
+// CheckColumnType 检查脱敏规则能否用于该MySQL列类型
+// 脱敏在类型转换前对MySQL的原始值进行，结果仍按列类型转换后写入：hash 只用于字符、整数和二进制列，
+// format 和 truncate 只用于字符列，constant 的值需能按列类型解析，null 可用于任意列
+func (r *MaskingRule) CheckColumnType(columnType string) error {
+	lowerType := strings.ToLower(strings.TrimSpace(columnType))
+	baseType := lowerType
+	if idx := strings.IndexAny(baseType, "( "); idx > 0 {
+		baseType = baseType[:idx]
+	}
+
+	var character, integer, binary, number bool
+	switch baseType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		character = true
+	case "tinyint":
+		// tinyint(1) 转换为 BOOLEAN，只能按数值处理
+		integer = !strings.HasPrefix(lowerType, "tinyint(1)")
+		number = true
+	case "smallint", "mediumint", "int", "integer", "bigint":
+		integer, number = true, true
+	case "decimal", "numeric", "float", "double", "real":
+		number = true
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		binary = true
+	}
+
+	supported := true
+	switch r.Action {
+	case MaskActionHash:
+		supported = character || integer || binary
+	case MaskActionFormat, MaskActionTruncate:
+		supported = character
+	case MaskActionConstant:
+		if number {
+			if _, err := strconv.ParseFloat(r.Value, 64); err != nil {
+				return fmt.Errorf("表 %s 列 %s 的类型为 %s，脱敏常量 %q 不是数值", r.Table, r.Column, columnType, r.Value)
+			}
+			if integer && !isIntegerText(r.Value) {
+				return fmt.Errorf("表 %s 列 %s 的类型为 %s，脱敏常量 %q 不是整数", r.Table, r.Column, columnType, r.Value)
+			}
+		}
+	}
+	if !supported {
+		return fmt.Errorf("表 %s 列 %s 的类型为 %s，不支持 %s 脱敏，可使用 constant 或 null", r.Table, r.Column, columnType, r.Action)
+	}
+	return nil
+}
+
+// isIntegerText 是否为十进制整数文本（可超出int64范围，如 BIGINT UNSIGNED 的值）
+func isIntegerText(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FindRule 获取表中指定列的脱敏规则，精确匹配表名的规则优先于 * 规则
+func (c *MaskingConfig) FindRule(tableName, column string) *MaskingRule {
+	var wildcard *MaskingRule
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if !strings.EqualFold(rule.Column, column) {
+			continue
+		}
+		if rule.Table == tableName {
+			return rule
+		}
+		if rule.Table == "*" && wildcard == nil {
+			wildcard = rule
+		}
+	}
+	return wildcard
+}
+
+// TableConfig 单表同步配置
+type TableConfig struct {
+	Table          string   `mapstructure:"table"`           // MySQL表名
+	Where          string   `mapstructure:"where"`           // 数据过滤条件（MySQL语法，不含WHERE关键字）
+	IncludeColumns []string `mapstructure:"include_columns"` // 只同步这些列，为空时同步全部列
+	ExcludeColumns []string `mapstructure:"exclude_columns"` // 不同步的列
+	TargetTable    string   `mapstructure:"target_table"`    // PostgreSQL中的目标表名，为空时与MySQL表名相同
+}
+
+// FindTable 获取指定表的单表同步配置，未配置时返回nil
+func (c *ConversionConfig) FindTable(tableName string) *TableConfig {
+	for i := range c.Tables {
+		if c.Tables[i].Table == tableName {
+			return &c.Tables[i]
+		}
+	}
+	return nil
+}
+
+// Filter 获取数据过滤条件，未配置时返回空字符串
+func (t *TableConfig) Filter() string {
+	if t == nil {
+		return ""
+	}
+	return strings.TrimSpace(t.Where)
+}
+
+// IncludesColumn 判断列是否需要同步（列名不区分大小写）
+func (t *TableConfig) IncludesColumn(column string) bool {
+	if t == nil {
+		return true
+	}
+	if len(t.IncludeColumns) > 0 && !containsFold(t.IncludeColumns, column) {
+		return false
+	}
+	return !containsFold(t.ExcludeColumns, column)
+}
+
+// containsFold 判断列表中是否包含指定字符串（不区分大小写）
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
 }
 
 // OptionsConfig 转换选项配置
@@ -66,32 +311,142 @@ type OptionsConfig struct {
 	ValidateData       bool     `mapstructure:"validate_data"`          // 同步后验证数据一致性
 	LowercaseColumns   bool     `mapstructure:"lowercase_columns"`      // 表字段是否转小写，true代表转小写，默认，false代表与mysql一致
 	TruncateBeforeSync bool     `mapstructure:"truncate_before_sync"`   // 同步前是否清空表数据
+	SyncMode           string   `mapstructure:"sync_mode"`              // 数据同步模式：full（全量，默认）、incremental（按水位列增量）或 repair（修复校验和不一致的范围）
+	LoadMode           string   `mapstructure:"load_mode"`              // 数据写入方式：copy（直接COPY，默认）或 upsert（COPY到中转表后按主键合并）
+	ValidateMode       string   `mapstructure:"validate_mode"`          // 数据校验方式：count（比较行数，默认）或 checksum（按主键分块比较行内容校验和）
+	RejectMode         string   `mapstructure:"reject_mode"`            // 写入失败行的处理方式：off（整表失败，默认）、file（隔离到文件）或 table（隔离到 mysql2pg_rejects 表）
+	RejectFile         string   `mapstructure:"reject_file"`            // reject_mode 为 file 时隔离行的保存路径
+	JSONMode           string   `mapstructure:"json_mode"`              // MySQL json 列的目标类型：json（默认）或 jsonb
+	FastLoad           bool     `mapstructure:"fast_load"`              // 快速加载：UNLOGGED建表、以replica角色写入，数据同步后再建索引和约束、SET LOGGED并ANALYZE
+	EstimateRowCounts  bool     `mapstructure:"estimate_row_counts"`    // 使用information_schema.TABLES中的估计行数显示进度，代替COUNT(*)
 }
 
 // LimitsConfig 限制配置
 type LimitsConfig struct {
-	Concurrency          int `mapstructure:"concurrency"`
-	BandwidthMbps        int `mapstructure:"bandwidth_mbps"`
-	MaxDDLPerBatch       int `mapstructure:"max_ddl_per_batch"`
-	MaxFunctionsPerBatch int `mapstructure:"max_functions_per_batch"`
-	MaxIndexesPerBatch   int `mapstructure:"max_indexes_per_batch"`
-	MaxUsersPerBatch     int `mapstructure:"max_users_per_batch"`
-	MaxRowsPerBatch      int `mapstructure:"max_rows_per_batch"` // 一次性同步数据的行数限制
-	BatchInsertSize      int `mapstructure:"batch_insert_size"`  // 批量插入的大小
+	Concurrency          int  `mapstructure:"concurrency"`
+	BandwidthMbps        int  `mapstructure:"bandwidth_mbps"` // 所有表合计从MySQL读取数据的带宽上限，0表示不限制
+	MaxDDLPerBatch       int  `mapstructure:"max_ddl_per_batch"`
+	MaxFunctionsPerBatch int  `mapstructure:"max_functions_per_batch"`
+	MaxIndexesPerBatch   int  `mapstructure:"max_indexes_per_batch"`
+	MaxUsersPerBatch     int  `mapstructure:"max_users_per_batch"`
+	MaxRowsPerBatch      int  `mapstructure:"max_rows_per_batch"`  // 一次性同步数据的行数限制
+	BatchInsertSize      int  `mapstructure:"batch_insert_size"`   // 批量插入的大小
+	ChecksumChunkSize    int  `mapstructure:"checksum_chunk_size"` // 校验和比较时每个主键分块的行数
+	RowsPerSecond        int  `mapstructure:"rows_per_second"`     // 所有表合计每秒读取的行数上限，0表示不限制
+	ThrottleEndpoint     bool `mapstructure:"throttle_endpoint"`   // 在本地 6060 端口注册 /throttle 接口，运行期间查看吞吐量并调整限速
+}
+
+// IsIncremental 是否为按水位列增量同步模式
+func (o *OptionsConfig) IsIncremental() bool {
+	return o.SyncMode == SyncModeIncremental
+}
+
+// IsRepair 是否为修复模式：只比较内容校验和并重新复制不一致的主键范围
+func (o *OptionsConfig) IsRepair() bool {
+	return o.SyncMode == SyncModeRepair
+}
+
+// IsUpsertLoad 是否以upsert方式写入数据，增量同步始终使用upsert
+func (o *OptionsConfig) IsUpsertLoad() bool {
+	return o.LoadMode == LoadModeUpsert || o.IsIncremental()
+}
+
+// IsChecksumValidation 是否按行内容校验和校验数据
+func (o *OptionsConfig) IsChecksumValidation() bool {
+	return o.ValidateData && o.ValidateMode == ValidateModeChecksum
+}
+
+// MySQL json 列的目标类型
+const (
+	JSONModeJSON  = "json"  // 转换为 JSON，保留原始文本
+	JSONModeJSONB = "jsonb" // 转换为 JSONB，支持GIN索引
+)
+
+// IsRejectEnabled 是否隔离写入失败的行，而不是使整表同步失败
+func (o *OptionsConfig) IsRejectEnabled() bool {
+	return o.RejectMode == RejectModeFile || o.RejectMode == RejectModeTable
+}
+
+// IncrementalConfig 增量同步配置
+type IncrementalConfig struct {
+	StateFile string                   `mapstructure:"state_file"` // 水位状态文件路径
+	Tables    []IncrementalTableConfig `mapstructure:"tables"`     // 各表的水位列配置
+}
+
+// IncrementalTableConfig 单表增量同步配置
+type IncrementalTableConfig struct {
+	Table           string `mapstructure:"table"`            // MySQL表名
+	WatermarkColumn string `mapstructure:"watermark_column"` // 水位列，如 updated_at 或自增id
+}
+
+// WatermarkColumn 获取指定表配置的水位列，未配置时返回空字符串
+func (c *IncrementalConfig) WatermarkColumn(tableName string) string {
+	for _, t := range c.Tables {
+		if t.Table == tableName {
+			return t.WatermarkColumn
+		}
+	}
+	return ""
 }
 
+// 数据同步模式
+const (
+	SyncModeFull        = "full"
+	SyncModeIncremental = "incremental"
+	SyncModeRepair      = "repair"
+)
+
+// 数据写入方式
+const (
+	LoadModeCopy   = "copy"
+	LoadModeUpsert = "upsert"
+)
+
+// 数据校验方式
+const (
+	ValidateModeCount    = "count"
+	ValidateModeChecksum = "checksum"
+)
+
+// 写入失败行的处理方式
+const (
+	RejectModeOff   = "off"
+	RejectModeFile  = "file"
+	RejectModeTable = "table"
+)
+
+// 运行报告的格式
+const (
+	ReportFormatJSON  = "json"
+	ReportFormatJUnit = "junit"
+	ReportFormatHTML  = "html"
+)
+
 // RunConfig 运行配置
 type RunConfig struct {
-	ShowProgress      bool   `mapstructure:"show_progress"`
-	ErrorLogPath      string `mapstructure:"error_log_path"`
-	EnableFileLogging bool   `mapstructure:"enable_file_logging"`
-	LogFilePath       string `mapstructure:"log_file_path"`
-	ShowConsoleLogs   bool   `mapstructure:"show_console_logs"`
-	ShowLogInConsole  bool   `mapstructure:"show_log_in_console"`
+	ShowProgress      bool     `mapstructure:"show_progress"`
+	ErrorLogPath      string   `mapstructure:"error_log_path"`
+	EnableFileLogging bool     `mapstructure:"enable_file_logging"`
+	LogFilePath       string   `mapstructure:"log_file_path"`
+	ShowConsoleLogs   bool     `mapstructure:"show_console_logs"`
+	ShowLogInConsole  bool     `mapstructure:"show_log_in_console"`
+	DryRun            bool     `mapstructure:"dry_run"`        // 只生成迁移脚本，不连接PostgreSQL
+	PlanDir           string   `mapstructure:"plan_dir"`       // dry_run 时迁移脚本的输出目录
+	ReportDir         string   `mapstructure:"report_dir"`     // 运行报告的输出目录
+	ReportFormats     []string `mapstructure:"report_formats"` // 运行报告的格式：json、junit、html，默认全部生成
 }
 
-// LoadConfig 加载配置文件
-func LoadConfig(configPath string) (*Config, error) {
+// DumpConfig 导出到文件的配置，启用时不连接PostgreSQL，将表结构和数据写入可由psql加载的SQL文件
+type DumpConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	Dir         string `mapstructure:"dir"`           // 输出目录
+	PerTable    bool   `mapstructure:"per_table"`     // 每个表的数据写入单独的文件
+	Compress    bool   `mapstructure:"compress"`      // 使用gzip压缩输出文件
+	SplitSizeMB int    `mapstructure:"split_size_mb"` // 单个文件超过该大小（压缩前）时拆分为多个文件，0为不拆分
+}
+
+// LoadConfig 加载配置文件，overrides 中的值（来自命令行参数）覆盖配置文件中的同名配置
+func LoadConfig(configPath string, overrides map[string]interface{}) (*Config, error) {
 	// 如果没有指定配置文件路径，尝试在当前目录查找
 	if configPath == "" {
 		currentDir, err := os.Getwd()
@@ -126,6 +481,11 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
+	// 命令行参数覆盖配置文件中的值，键为点分隔的配置路径，如 conversion.limits.concurrency
+	for key, value := range overrides {
+		viper.Set(key, value)
+	}
+
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
@@ -136,15 +496,24 @@ func LoadConfig(configPath string) (*Config, error) {
 
 // ValidateConfig 验证配置是否有效
 func (c *Config) ValidateConfig() error {
-	// 验证MySQL配置
-	if c.MySQL.Host == "" {
-		return fmt.Errorf("MySQL主机地址不能为空")
-	}
-	if c.MySQL.Username == "" {
-		return fmt.Errorf("MySQL用户名不能为空")
-	}
-	if c.MySQL.Database == "" {
-		return fmt.Errorf("MySQL数据库名不能为空")
+	// 验证MySQL配置，从mysqldump导出文件转换时不连接MySQL
+	if c.MySQL.DumpFile != "" {
+		if c.Dump.Enabled {
+			return fmt.Errorf("从mysqldump导出文件转换时不支持 dump.enabled")
+		}
+		if c.Conversion.Options.IsIncremental() || c.Conversion.Options.IsRepair() {
+			return fmt.Errorf("从mysqldump导出文件转换时不支持增量同步和修复模式")
+		}
+	} else {
+		if c.MySQL.Host == "" {
+			return fmt.Errorf("MySQL主机地址不能为空")
+		}
+		if c.MySQL.Username == "" {
+			return fmt.Errorf("MySQL用户名不能为空")
+		}
+		if c.MySQL.Database == "" {
+			return fmt.Errorf("MySQL数据库名不能为空")
+		}
 	}
 	// MySQL连接池默认值
 	if c.MySQL.MaxOpenConns <= 0 {
@@ -157,15 +526,27 @@ func (c *Config) ValidateConfig() error {
 		c.MySQL.ConnMaxLifetime = 3600 // 默认值（秒）
 	}
 
-	// 验证PostgreSQL配置
-	if c.PostgreSQL.Host == "" {
-		return fmt.Errorf("PostgreSQL主机地址不能为空")
-	}
-	if c.PostgreSQL.Username == "" {
-		return fmt.Errorf("PostgreSQL用户名不能为空")
-	}
-	if c.PostgreSQL.Database == "" {
-		return fmt.Errorf("PostgreSQL数据库名不能为空")
+	// 验证PostgreSQL配置，只生成迁移脚本或导出到文件时不连接PostgreSQL
+	if c.Run.DryRun || c.Dump.Enabled {
+		if c.Run.PlanDir == "" {
+			c.Run.PlanDir = "./plan" // 默认值
+		}
+		if c.Dump.Dir == "" {
+			c.Dump.Dir = "./dump" // 默认值
+		}
+		if c.Dump.SplitSizeMB < 0 {
+			return fmt.Errorf("dump.split_size_mb 不能小于0")
+		}
+	} else {
+		if c.PostgreSQL.Host == "" {
+			return fmt.Errorf("PostgreSQL主机地址不能为空")
+		}
+		if c.PostgreSQL.Username == "" {
+			return fmt.Errorf("PostgreSQL用户名不能为空")
+		}
+		if c.PostgreSQL.Database == "" {
+			return fmt.Errorf("PostgreSQL数据库名不能为空")
+		}
 	}
 	// PostgreSQL连接池默认值
 	if c.PostgreSQL.MaxConns <= 0 {
@@ -191,6 +572,171 @@ func (c *Config) ValidateConfig() error {
 	if c.Conversion.Limits.MaxRowsPerBatch <= 0 {
 		c.Conversion.Limits.MaxRowsPerBatch = 1000 // 默认值
 	}
+	if c.Conversion.Limits.BandwidthMbps < 0 || c.Conversion.Limits.RowsPerSecond < 0 {
+		return fmt.Errorf("bandwidth_mbps 和 rows_per_second 不能为负数，0表示不限制")
+	}
+
+	// 验证数据同步模式
+	switch c.Conversion.Options.SyncMode {
+	case "":
+		c.Conversion.Options.SyncMode = SyncModeFull // 默认值
+	case SyncModeFull, SyncModeIncremental, SyncModeRepair:
+	default:
+		return fmt.Errorf("不支持的数据同步模式: %s，可选值为 full、incremental 或 repair", c.Conversion.Options.SyncMode)
+	}
+	// 验证数据写入方式
+	switch c.Conversion.Options.LoadMode {
+	case "":
+		c.Conversion.Options.LoadMode = LoadModeCopy // 默认值
+	case LoadModeCopy, LoadModeUpsert:
+	default:
+		return fmt.Errorf("不支持的数据写入方式: %s，可选值为 copy 或 upsert", c.Conversion.Options.LoadMode)
+	}
+
+	// 验证数据校验方式
+	switch c.Conversion.Options.ValidateMode {
+	case "":
+		c.Conversion.Options.ValidateMode = ValidateModeCount // 默认值
+	case ValidateModeCount, ValidateModeChecksum:
+	default:
+		return fmt.Errorf("不支持的数据校验方式: %s，可选值为 count 或 checksum", c.Conversion.Options.ValidateMode)
+	}
+	// 验证写入失败行的处理方式
+	switch c.Conversion.Options.RejectMode {
+	case "":
+		c.Conversion.Options.RejectMode = RejectModeOff // 默认值
+	case RejectModeOff, RejectModeTable:
+	case RejectModeFile:
+		if c.Conversion.Options.RejectFile == "" {
+			c.Conversion.Options.RejectFile = "./rejects.jsonl" // 默认值
+		}
+	default:
+		return fmt.Errorf("不支持的写入失败行处理方式: %s，可选值为 off、file 或 table", c.Conversion.Options.RejectMode)
+	}
+
+	// 验证运行报告配置
+	if c.Run.ReportDir == "" {
+		c.Run.ReportDir = "./report" // 默认值
+	}
+	if len(c.Run.ReportFormats) == 0 {
+		c.Run.ReportFormats = []string{ReportFormatJSON, ReportFormatJUnit, ReportFormatHTML} // 默认值
+	}
+	for _, format := range c.Run.ReportFormats {
+		switch format {
+		case ReportFormatJSON, ReportFormatJUnit, ReportFormatHTML:
+		default:
+			return fmt.Errorf("不支持的运行报告格式: %s，可选值为 json、junit 或 html", format)
+		}
+	}
+
+	// 验证json列的目标类型
+	switch c.Conversion.Options.JSONMode {
+	case "":
+		c.Conversion.Options.JSONMode = JSONModeJSON // 默认值
+	case JSONModeJSON, JSONModeJSONB:
+	default:
+		return fmt.Errorf("不支持的json列目标类型: %s，可选值为 json 或 jsonb", c.Conversion.Options.JSONMode)
+	}
+	for _, rule := range c.Conversion.JSONColumns {
+		if rule.Table == "" || rule.Column == "" {
+			return fmt.Errorf("json_columns 的 table 和 column 不能为空")
+		}
+	}
+
+	// 验证单表同步配置
+	for _, t := range c.Conversion.Tables {
+		if t.Table == "" {
+			return fmt.Errorf("单表同步配置 conversion.tables 中的 table 不能为空")
+		}
+	}
+
+	// 验证无效数据的处理策略
+	invalidData := &c.Conversion.InvalidData
+	textPolicies := []string{InvalidDataError, InvalidDataNull, InvalidDataReplace, InvalidDataStrip}
+	timePolicies := []string{InvalidDataError, InvalidDataNull, InvalidDataClamp}
+	for _, item := range []struct {
+		name     string
+		policy   *string
+		def      string
+		policies []string
+	}{
+		{"nul_bytes", &invalidData.NulBytes, InvalidDataError, textPolicies},
+		{"invalid_utf8", &invalidData.InvalidUTF8, InvalidDataError, textPolicies},
+		{"zero_date", &invalidData.ZeroDate, InvalidDataNull, timePolicies},
+		{"invalid_date", &invalidData.InvalidDate, InvalidDataError, timePolicies},
+		{"time_overflow", &invalidData.TimeOverflow, InvalidDataError, timePolicies},
+		{"invalid_json", &invalidData.InvalidJSON, InvalidDataError, []string{InvalidDataError, InvalidDataNull}},
+	} {
+		if *item.policy == "" {
+			*item.policy = item.def // 默认值
+		} else if !containsFold(item.policies, *item.policy) {
+			return fmt.Errorf("invalid_data.%s 不支持的处理策略: %s，可选值为 %s", item.name, *item.policy, strings.Join(item.policies, "、"))
+		}
+		*item.policy = strings.ToLower(*item.policy)
+	}
+
+	// 验证UUID列规则
+	if c.Conversion.UUID.NamePattern == "" {
+		c.Conversion.UUID.NamePattern = `(?i)(uuid|guid)$` // 默认值
+	}
+	if _, err := regexp.Compile(c.Conversion.UUID.NamePattern); err != nil {
+		return fmt.Errorf("uuid.name_pattern 不是有效的正则表达式: %w", err)
+	}
+	for _, rule := range c.Conversion.UUID.Columns {
+		if rule.Table == "" || rule.Column == "" {
+			return fmt.Errorf("UUID列规则的 table 和 column 不能为空")
+		}
+	}
+
+	// 验证脱敏规则
+	for _, rule := range c.Conversion.Masking.Rules {
+		if rule.Table == "" || rule.Column == "" {
+			return fmt.Errorf("脱敏规则的 table 和 column 不能为空")
+		}
+		switch rule.Action {
+		case MaskActionHash, MaskActionConstant, MaskActionNull, MaskActionFormat:
+		case MaskActionTruncate:
+			if rule.Length <= 0 {
+				return fmt.Errorf("表 %s 列 %s 的脱敏方式为 truncate 时 length 必须大于0", rule.Table, rule.Column)
+			}
+		default:
+			return fmt.Errorf("表 %s 列 %s 不支持的脱敏方式: %s，可选值为 hash、constant、null、format、truncate", rule.Table, rule.Column, rule.Action)
+		}
+		// 列类型在读取表结构后才能确定，创建脱敏处理器时再按 CheckColumnType 检查
+	}
+
+	// 修复模式只执行数据阶段，避免重建表结构导致已同步的数据丢失
+	if c.Conversion.Options.SyncMode == SyncModeRepair {
+		opts := &c.Conversion.Options
+		opts.Data = true
+		opts.TableDDL = false
+		opts.View = false
+		opts.Functions = false
+		opts.Indexes = false
+		opts.Users = false
+		opts.Grant = false
+		opts.TablePrivileges = false
+	}
+
+	if c.Conversion.Options.SyncMode == SyncModeIncremental {
+		if c.Conversion.Incremental.StateFile == "" {
+			c.Conversion.Incremental.StateFile = "./incremental_state.json" // 默认值
+		}
+		if len(c.Conversion.Incremental.Tables) == 0 {
+			return fmt.Errorf("增量同步模式下必须在 incremental.tables 中至少配置一个表的水位列")
+		}
+		for _, t := range c.Conversion.Incremental.Tables {
+			if t.Table == "" || t.WatermarkColumn == "" {
+				return fmt.Errorf("增量同步配置无效: table 和 watermark_column 均不能为空")
+			}
+		}
+		// 重建表会删除已同步的数据，增量同步只能补建不存在的表
+		if c.Conversion.Options.TableDDL && !c.Conversion.Options.SkipExistingTables {
+			return fmt.Errorf("增量同步模式下转换表结构会删除已同步的数据，请设置 skip_existing_tables: true 或 table_ddl: false")
+		}
+		// 增量同步只写入水位范围内的数据，不能清空表
+		c.Conversion.Options.TruncateBeforeSync = false
+	}
 
 	return nil
 }
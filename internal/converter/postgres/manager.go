@@ -1,8 +1,11 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -10,6 +13,7 @@ import (
 	"github.com/yourusername/mysql2pg/internal/config"
 	"github.com/yourusername/mysql2pg/internal/mysql"
 	"github.com/yourusername/mysql2pg/internal/postgres"
+	"github.com/yourusername/mysql2pg/internal/throttle"
 )
 
 // Manager 转换管理器
@@ -28,6 +32,24 @@ type Manager struct {
 	inconsistentTables []TableDataInconsistency
 	// 存储表名到列名映射的映射
 	tableColumnNamesMap map[string]map[string]string // 键：表名，值：(键：原始列名，值：转换后的列名)
+	// 增量同步水位状态（仅增量同步模式下加载）
+	incrementalState *IncrementalState
+	// 列数据脱敏审计信息
+	maskingAudit *MaskingAudit
+	// 按无效数据处理策略处理的值的统计
+	dataFixes *DataFixAudit
+	// 数据同步限速器，所有表的同步协程共享
+	limiter *throttle.Limiter
+	// 写入失败被隔离的行，未启用坏行隔离时为nil
+	rejects *RejectLog
+	// 快速加载模式下创建的表，数据同步后补建约束并转换为普通表
+	fastLoadTables []fastLoadTable
+	// 已执行过快速加载收尾
+	fastLoadFinished bool
+	// mysqldump导出文件数据源，设置后不从MySQL读取元数据和数据
+	dumpFile *mysql.DumpFile
+	// 本次运行的结果，运行结束后写入报告文件
+	report *RunReport
 }
 
 // ConversionStageStat 转换阶段统计信息
@@ -56,6 +78,15 @@ func NewManager(mysqlConn *mysql.Connection, postgresConn *postgres.Connection,
 		}
 	}
 
+	// 增量同步模式下加载上次运行记录的水位
+	var incrementalState *IncrementalState
+	if config.Conversion.Options.IsIncremental() {
+		incrementalState, err = LoadIncrementalState(config.Conversion.Incremental.StateFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Manager{
 		mysqlConn:           mysqlConn,
 		postgresConn:        postgresConn,
@@ -63,9 +94,25 @@ func NewManager(mysqlConn *mysql.Connection, postgresConn *postgres.Connection,
 		errorLogFile:        errorLogFile,
 		logFile:             logFile,
 		tableColumnNamesMap: make(map[string]map[string]string),
+		incrementalState:    incrementalState,
+		maskingAudit:        &MaskingAudit{},
+		dataFixes:           &DataFixAudit{},
+		limiter:             throttle.NewLimiter(config.Conversion.Limits.BandwidthMbps, config.Conversion.Limits.RowsPerSecond),
+		rejects:             NewRejectLog(config),
+		report:              NewRunReport(config),
 	}, nil
 }
 
+// Limiter 获取数据同步限速器，用于运行期间调整限速
+func (m *Manager) Limiter() *throttle.Limiter {
+	return m.limiter
+}
+
+// InconsistentTables 获取数据校验不一致的表，用于确定程序的退出码
+func (m *Manager) InconsistentTables() []TableDataInconsistency {
+	return m.inconsistentTables
+}
+
 // Close 关闭转换管理器
 // 关闭打开的日志文件
 func (m *Manager) Close() error {
@@ -80,12 +127,35 @@ func (m *Manager) Close() error {
 		err = closeErr
 	}
 
+	if closeErr := m.rejects.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
 	return err
 }
 
 // Run 执行完整的转换流程
-// 根据配置执行表DDL、数据、索引、函数、用户和权限的转换
-func (m *Manager) Run() error {
+// 根据配置执行表DDL、数据、索引、函数、用户和权限的转换，结束后写入运行报告
+// ctx 被取消时停止开始新的对象和批次，正在写入的批次回滚，显示已完成部分的汇总
+func (m *Manager) Run(ctx context.Context) error {
+	err := m.run(ctx)
+	if err != nil {
+		// 失败和中断时同样处理快速加载的表，不能静默地保持UNLOGGED
+		err = m.recoverFastLoad(ctx, err)
+	}
+	if err != nil && ctx.Err() != nil {
+		// 驱动返回的错误不一定包装了 ctx 的错误，统一包装以便调用方判断是否被中断
+		if !errors.Is(err, ctx.Err()) {
+			err = fmt.Errorf("转换已中断: %w (%v)", ctx.Err(), err)
+		}
+		m.displayInterrupted()
+	}
+	m.writeReport(err)
+	return err
+}
+
+// run 执行转换流程
+func (m *Manager) run(ctx context.Context) error {
 	m.Log("表MySQL 的DDL、数据、view、索引、函数、用户和权限的转换到 PostgreSQL ...")
 
 	// 检查是否启用了表列表功能
@@ -93,7 +163,7 @@ func (m *Manager) Run() error {
 		m.Log("启用了表列表功能，只同步指定的表")
 
 		// 获取MySQL元数据（只需要表信息）
-		allTables, _, indexes, _, _, _, err := m.getMetadata()
+		allTables, _, indexes, _, _, _, err := m.getMetadata(ctx)
 		if err != nil {
 			return err
 		}
@@ -136,7 +206,7 @@ func (m *Manager) Run() error {
 			// 记录DDL转换开始时间
 			startTime := time.Now()
 			semaphore := make(chan struct{}, m.config.Conversion.Limits.Concurrency)
-			if err := m.convertTables(filteredTables, semaphore); err != nil {
+			if err := m.convertTables(ctx, filteredTables, semaphore); err != nil {
 				return err
 			}
 			// 记录DDL转换结束时间并添加到转换统计中
@@ -151,13 +221,16 @@ func (m *Manager) Run() error {
 
 		// 执行数据同步（如果启用）
 		if m.config.Conversion.Options.Data {
+			if m.config.Conversion.Options.FastLoad {
+				m.prepareFastLoad(ctx)
+			}
 			if m.config.Run.ShowConsoleLogs {
 				fmt.Println("\n2. 同步表数据...")
 			}
 			// 记录数据同步开始时间
 			startTime := time.Now()
 			semaphore := make(chan struct{}, m.config.Conversion.Limits.Concurrency)
-			if err := m.syncTableData(filteredTables, semaphore); err != nil {
+			if err := m.syncTableData(ctx, filteredTables, semaphore); err != nil {
 				return err
 			}
 			// 记录数据同步结束时间并添加到转换统计中
@@ -210,7 +283,7 @@ func (m *Manager) Run() error {
 				wg.Add(1)
 				go func(batch []mysql.IndexInfo) {
 					defer wg.Done()
-					if err := m.convertIndexes(batch, semaphore); err != nil {
+					if err := m.convertIndexes(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -235,8 +308,19 @@ func (m *Manager) Run() error {
 			}
 		}
 
+		// 快速加载收尾：创建主键和索引、补建约束并转换为普通表
+		if err := m.finishFastLoad(ctx, make(chan struct{}, m.config.Conversion.Limits.Concurrency)); err != nil {
+			return err
+		}
+
 		// 显示数据不一致表的统计信息
 		m.displayInconsistentTables()
+		// 显示脱敏列审计信息
+		m.displayMaskingAudit()
+		// 显示被隔离的行数
+		m.displayRejects()
+		// 显示无效数据的处理统计
+		m.displayDataFixes()
 
 		// 生成汇总表格
 		m.generateSummaryTable()
@@ -247,7 +331,7 @@ func (m *Manager) Run() error {
 
 	// 正常转换流程
 	// 1. 获取MySQL元数据
-	tables, functions, indexes, views, users, tablePrivileges, err := m.getMetadata()
+	tables, functions, indexes, views, users, tablePrivileges, err := m.getMetadata(ctx)
 	if err != nil {
 		return err
 	}
@@ -256,12 +340,18 @@ func (m *Manager) Run() error {
 	m.calculateTotalTasks(tables, functions, indexes, views, users, tablePrivileges)
 
 	// 3. 执行转换
-	if err := m.executeConversion(tables, functions, indexes, views, users, tablePrivileges); err != nil {
+	if err := m.executeConversion(ctx, tables, functions, indexes, views, users, tablePrivileges); err != nil {
 		return err
 	}
 
 	// 显示数据不一致表的统计信息
 	m.displayInconsistentTables()
+	// 显示脱敏列审计信息
+	m.displayMaskingAudit()
+	// 显示被隔离的行数
+	m.displayRejects()
+	// 显示无效数据的处理统计
+	m.displayDataFixes()
 
 	m.Log("转换完成!")
 	return nil
@@ -269,7 +359,11 @@ func (m *Manager) Run() error {
 
 // getMetadata 获取MySQL数据库的元数据信息
 // 返回表、函数、索引、用户和表权限信息
-func (m *Manager) getMetadata() ([]mysql.TableInfo, []mysql.FunctionInfo, []mysql.IndexInfo, []mysql.ViewInfo, []mysql.UserInfo, []mysql.TablePrivInfo, error) {
+func (m *Manager) getMetadata(ctx context.Context) ([]mysql.TableInfo, []mysql.FunctionInfo, []mysql.IndexInfo, []mysql.ViewInfo, []mysql.UserInfo, []mysql.TablePrivInfo, error) {
+	if m.dumpFile != nil {
+		return m.dumpFileMetadata()
+	}
+
 	var tables []mysql.TableInfo
 	var functions []mysql.FunctionInfo
 	var indexes []mysql.IndexInfo
@@ -280,6 +374,7 @@ func (m *Manager) getMetadata() ([]mysql.TableInfo, []mysql.FunctionInfo, []mysq
 
 	if m.config.Conversion.Options.TableDDL || m.config.Conversion.Options.Indexes || m.config.Conversion.Options.Data || m.config.Conversion.Options.Grant {
 		tables, err = m.mysqlConn.GetTables(
+			ctx,
 			m.config.Conversion.Options.SkipUseTableList,
 			m.config.Conversion.Options.SkipTableList,
 			m.config.Conversion.Options.UseTableList,
@@ -303,27 +398,27 @@ func (m *Manager) getMetadata() ([]mysql.TableInfo, []mysql.FunctionInfo, []mysq
 	}
 
 	// 获取视图信息
-	views, err = m.mysqlConn.GetViews(m.config.MySQL.Database)
+	views, err = m.mysqlConn.GetViews(ctx, m.config.MySQL.Database)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, fmt.Errorf("获取视图信息失败: %w", err)
 	}
 
 	if m.config.Conversion.Options.Functions {
-		functions, err = m.mysqlConn.GetFunctions()
+		functions, err = m.mysqlConn.GetFunctions(ctx)
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, fmt.Errorf("获取函数信息失败: %w", err)
 		}
 	}
 
 	if m.config.Conversion.Options.Users || m.config.Conversion.Options.Grant {
-		users, err = m.mysqlConn.GetUsers()
+		users, err = m.mysqlConn.GetUsers(ctx)
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, fmt.Errorf("获取用户信息失败: %w", err)
 		}
 	}
 
 	if m.config.Conversion.Options.Grant || m.config.Conversion.Options.TablePrivileges {
-		tablePrivileges, err = m.mysqlConn.GetTablePrivileges()
+		tablePrivileges, err = m.mysqlConn.GetTablePrivileges(ctx)
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, fmt.Errorf("获取表权限失败: %w", err)
 		}
@@ -366,7 +461,7 @@ func (m *Manager) calculateTotalTasks(tables []mysql.TableInfo, functions []mysq
 
 // executeConversion 执行完整的转换流程
 // 按照配置的顺序执行表DDL、数据、索引、函数、视图、用户和权限的转换
-func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.FunctionInfo, indexes []mysql.IndexInfo, views []mysql.ViewInfo, users []mysql.UserInfo, tablePrivileges []mysql.TablePrivInfo) error {
+func (m *Manager) executeConversion(ctx context.Context, tables []mysql.TableInfo, functions []mysql.FunctionInfo, indexes []mysql.IndexInfo, views []mysql.ViewInfo, users []mysql.UserInfo, tablePrivileges []mysql.TablePrivInfo) error {
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, m.config.Conversion.Limits.Concurrency)
 	errorChan := make(chan error, 1)
@@ -400,6 +495,13 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 		filteredTables = tables
 	}
 
+	// 快速加载模式：索引阶段只记录快速加载表的索引，全部阶段完成后由 finishFastLoad 按
+	// 创建主键和索引、补建约束、转换为普通表的顺序收尾，与执行顺序和启用的选项无关
+	fastLoad := m.config.Conversion.Options.FastLoad
+	if fastLoad && m.config.Conversion.Options.Data {
+		m.prepareFastLoad(ctx)
+	}
+
 	// 检查是否所有选项都打开
 	allOptionsEnabled := m.config.Conversion.Options.TableDDL &&
 		m.config.Conversion.Options.Data &&
@@ -429,7 +531,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				wg.Add(1)
 				go func(batch []mysql.TableInfo) {
 					defer wg.Done()
-					if err := m.convertTables(batch, semaphore); err != nil {
+					if err := m.convertTables(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -469,7 +571,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				wg.Add(1)
 				go func(batch []mysql.ViewInfo) {
 					defer wg.Done()
-					if err := m.convertViews(batch, semaphore); err != nil {
+					if err := m.convertViews(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -501,26 +603,13 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 			}
 			// 记录开始时间
 			startTime := time.Now()
-			batchSize := m.config.Conversion.Limits.MaxDDLPerBatch
-			for i := 0; i < len(filteredTables); i += batchSize {
-				end := i + batchSize
-				if end > len(filteredTables) {
-					end = len(filteredTables)
+			// 所有表一起调度，按数据大小从大到小开始同步
+			if err := m.syncTableData(ctx, filteredTables, semaphore); err != nil {
+				select {
+				case errorChan <- err:
+				default:
 				}
-
-				batch := filteredTables[i:end]
-				wg.Add(1)
-				go func(batch []mysql.TableInfo) {
-					defer wg.Done()
-					if err := m.syncTableData(batch, semaphore); err != nil {
-						select {
-						case errorChan <- err:
-						default:
-						}
-					}
-				}(batch)
 			}
-			wg.Wait() // 等待表数据同步完成
 			// 记录结束时间和对象数量
 			m.conversionStats = append(m.conversionStats, ConversionStageStat{
 				StageName:   "同步表数据",
@@ -561,7 +650,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				wg.Add(1)
 				go func(batch []mysql.IndexInfo) {
 					defer wg.Done()
-					if err := m.convertIndexes(batch, semaphore); err != nil {
+					if err := m.convertIndexes(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -605,7 +694,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 					wg.Add(1)
 					go func(batch []mysql.FunctionInfo) {
 						defer wg.Done()
-						if err := m.convertFunctions(batch, semaphore); err != nil {
+						if err := m.convertFunctions(ctx, batch, semaphore); err != nil {
 							select {
 							case errorChan <- err:
 							default:
@@ -657,7 +746,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 					wg.Add(1)
 					go func(batch []mysql.UserInfo) {
 						defer wg.Done()
-						if err := m.convertUsers(batch, semaphore); err != nil {
+						if err := m.convertUsers(ctx, batch, semaphore); err != nil {
 							select {
 							case errorChan <- err:
 							default:
@@ -743,7 +832,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 					// 记录开始时间
 					startTime := time.Now()
 					// 串行处理表权限转换，避免并发更新冲突
-					if err := m.convertTablePrivilegesNew(tablePrivileges, semaphore); err != nil {
+					if err := m.convertTablePrivilegesNew(ctx, tablePrivileges, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -797,7 +886,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				wg.Add(1)
 				go func(batch []mysql.TableInfo) {
 					defer wg.Done()
-					if err := m.convertTables(batch, semaphore); err != nil {
+					if err := m.convertTables(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -829,26 +918,13 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 			}
 			// 记录开始时间
 			startTime := time.Now()
-			batchSize := m.config.Conversion.Limits.MaxDDLPerBatch
-			for i := 0; i < len(tables); i += batchSize {
-				end := i + batchSize
-				if end > len(tables) {
-					end = len(tables)
+			// 所有表一起调度，按数据大小从大到小开始同步
+			if err := m.syncTableData(ctx, tables, semaphore); err != nil {
+				select {
+				case errorChan <- err:
+				default:
 				}
-
-				batch := tables[i:end]
-				wg.Add(1)
-				go func(batch []mysql.TableInfo) {
-					defer wg.Done()
-					if err := m.syncTableData(batch, semaphore); err != nil {
-						select {
-						case errorChan <- err:
-						default:
-						}
-					}
-				}(batch)
 			}
-			wg.Wait() // 等待表数据同步完成
 			// 记录结束时间和对象数量
 			m.conversionStats = append(m.conversionStats, ConversionStageStat{
 				StageName:   "同步表数据",
@@ -883,7 +959,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				wg.Add(1)
 				go func(batch []mysql.ViewInfo) {
 					defer wg.Done()
-					if err := m.convertViews(batch, semaphore); err != nil {
+					if err := m.convertViews(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -926,7 +1002,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				wg.Add(1)
 				go func(batch []mysql.IndexInfo) {
 					defer wg.Done()
-					if err := m.convertIndexes(batch, semaphore); err != nil {
+					if err := m.convertIndexes(ctx, batch, semaphore); err != nil {
 						select {
 						case errorChan <- err:
 						default:
@@ -970,7 +1046,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 					wg.Add(1)
 					go func(batch []mysql.FunctionInfo) {
 						defer wg.Done()
-						if err := m.convertFunctions(batch, semaphore); err != nil {
+						if err := m.convertFunctions(ctx, batch, semaphore); err != nil {
 							select {
 							case errorChan <- err:
 							default:
@@ -1022,7 +1098,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 					wg.Add(1)
 					go func(batch []mysql.UserInfo) {
 						defer wg.Done()
-						if err := m.convertUsers(batch, semaphore); err != nil {
+						if err := m.convertUsers(ctx, batch, semaphore); err != nil {
 							select {
 							case errorChan <- err:
 							default:
@@ -1106,7 +1182,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 				// 记录开始时间
 				startTime := time.Now()
 				// 串行处理表权限转换，避免并发更新冲突
-				if err := m.convertTablePrivilegesNew(tablePrivileges, semaphore); err != nil {
+				if err := m.convertTablePrivilegesNew(ctx, tablePrivileges, semaphore); err != nil {
 					select {
 					case errorChan <- err:
 					default:
@@ -1137,6 +1213,12 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 		}
 	}
 
+	if fastLoad {
+		if err := m.finishFastLoad(ctx, semaphore); err != nil {
+			return err
+		}
+	}
+
 	// 生成汇总表格
 	m.generateSummaryTable()
 
@@ -1145,7 +1227,7 @@ func (m *Manager) executeConversion(tables []mysql.TableInfo, functions []mysql.
 
 // convertViews 转换表视图DDL
 // 将MySQL视图定义转换为PostgreSQL视图定义并执行
-func (m *Manager) convertViews(views []mysql.ViewInfo, semaphore chan struct{}) error {
+func (m *Manager) convertViews(ctx context.Context, views []mysql.ViewInfo, semaphore chan struct{}) error {
 	currentViewIndex := 0
 
 	for _, view := range views {
@@ -1160,15 +1242,17 @@ func (m *Manager) convertViews(views []mysql.ViewInfo, semaphore chan struct{})
 			m.Log("转换表视图 %s 失败，PostgreSQL 定义: %s", view.ViewName, pgViewDDL)
 			errMsg := fmt.Sprintf("转换表视图 %s 失败: %v", view.ViewName, err)
 			m.logError(errMsg)
+			m.report.RecordObject("view", view.ViewName, ReportFailed, pgViewDDL, err)
 			<-semaphore
 			m.updateProgress()
 			return err
 		}
 
 		// 执行创建视图的SQL语句
-		if err := m.postgresConn.ExecuteDDL(pgViewDDL); err != nil {
+		if err := m.postgresConn.ExecuteDDL(ctx, pgViewDDL); err != nil {
 			errMsg := fmt.Sprintf("创建表视图 %s 失败: %v", view.ViewName, err)
 			m.logError(errMsg)
+			m.report.RecordObject("view", view.ViewName, ReportFailed, pgViewDDL, err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1188,27 +1272,48 @@ func (m *Manager) convertViews(views []mysql.ViewInfo, semaphore chan struct{})
 		}
 
 		m.Log("转换表视图 %s 完成", view.ViewName)
+		m.report.RecordObject("view", view.ViewName, ReportConverted, pgViewDDL, nil)
 		<-semaphore
 	}
 
 	return nil
 }
 
+// buildTableDDL 按单表同步配置和列类型转换配置预处理MySQL表结构，并转换为PostgreSQL建表DDL
+// 返回裁剪后的表信息，用于添加列注释
+func (m *Manager) buildTableDDL(table mysql.TableInfo) (mysql.TableInfo, *ConvertTableDDLResult, error) {
+	if err := checkProjectedPrimaryKey(m.config.Conversion.FindTable(table.Name), table.Name, primaryKeyColumnsFromDDL(table.DDL)); err != nil {
+		return table, nil, err
+	}
+	// 按单表同步配置裁剪列并替换目标表名
+	projected := projectTableInfo(m.config, table)
+	// 配置为UUID的 BINARY(16) 列转换为 uuid 类型
+	projected.DDL = applyUUIDColumns(projected.DDL, tableUUIDColumns(m.config, table))
+	// json_mode 为 jsonb 时的 json 列和配置的JSON文本列转换为 jsonb 类型
+	projected.DDL = applyJSONBColumns(projected.DDL, tableJSONBColumns(m.config, table))
+
+	pgResult, err := ConvertTableDDL(projected.DDL, m.config.Conversion.Options.LowercaseColumns)
+	return projected, pgResult, err
+}
+
 // convertTables 转换表DDL
 // 将MySQL表结构转换为PostgreSQL表结构并执行
-func (m *Manager) convertTables(tables []mysql.TableInfo, semaphore chan struct{}) error {
+func (m *Manager) convertTables(ctx context.Context, tables []mysql.TableInfo, semaphore chan struct{}) error {
 	currentTableIndex := 0
 
 	for _, table := range tables {
 		semaphore <- struct{}{}
 		currentTableIndex++
 
-		pgResult, err := ConvertTableDDL(table.DDL, m.config.Conversion.Options.LowercaseColumns)
+		projected, pgResult, err := m.buildTableDDL(table)
 		if err != nil {
+			m.report.RecordObject("table", table.Name, ReportFailed, "", err)
 			// 记录转换失败的 MySQL 表的部分转换结果
 			m.Log("转换表 %s，MySQL DDL: %s", table.Name, table.DDL)
 			// 记录转换失败的 PostgreSQL 表的部分转换结果
-			m.Log("转换表 %s 失败，PostgreSQL DDL: %s", table.Name, pgResult.DDL)
+			if pgResult != nil {
+				m.Log("转换表 %s 失败，PostgreSQL DDL: %s", table.Name, pgResult.DDL)
+			}
 			errMsg := fmt.Sprintf("转换表 %s 失败: %v", table.Name, err)
 			m.logError(errMsg)
 			<-semaphore
@@ -1219,17 +1324,15 @@ func (m *Manager) convertTables(tables []mysql.TableInfo, semaphore chan struct{
 		// 存储列名映射，用于后续索引转换
 		m.tableColumnNamesMap[table.Name] = pgResult.ColumnNames
 
-		// 根据配置决定是否将表名转换为小写
-		pgTableName := table.Name
-		if m.config.Conversion.Options.LowercaseColumns {
-			pgTableName = strings.ToLower(pgTableName)
-		}
+		// 获取PostgreSQL中的目标表名
+		pgTableName := targetTableName(m.config, table.Name)
 
 		// 先检查表是否存在
-		tableExists, err := m.postgresConn.TableExists(pgTableName)
+		tableExists, err := m.postgresConn.TableExists(ctx, pgTableName)
 		if err != nil {
 			errMsg := fmt.Sprintf("检查表 %s 是否存在失败: %v", table.Name, err)
 			m.logError(errMsg)
+			m.report.RecordObject("table", table.Name, ReportFailed, pgResult.DDL, err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1251,25 +1354,27 @@ func (m *Manager) convertTables(tables []mysql.TableInfo, semaphore chan struct{
 				}
 
 				m.Log("表 %s 已存在，跳过创建", table.Name)
+				m.report.SkipObject("table", table.Name, "表已存在")
 
 				// 即使表已存在，也添加表注释和列注释
 				if pgResult.TableComment != "" {
 					processedComment := m.processComment(pgResult.TableComment)
 					tableCommentSQL := fmt.Sprintf("COMMENT ON TABLE \"%s\" IS '%s';",
 						pgTableName, processedComment)
-					if err := m.postgresConn.ExecuteDDL(tableCommentSQL); err != nil {
+					if err := m.postgresConn.ExecuteDDL(ctx, tableCommentSQL); err != nil {
 						m.logError(fmt.Sprintf("为表 %s 添加表注释失败: %v", table.Name, err))
 					}
 				}
-				m.addColumnComments(table, pgResult.ColumnNames)
+				m.addColumnComments(ctx, projected, pgTableName, pgResult.ColumnNames)
 
 				<-semaphore
 				continue
 			} else {
 				dropTableSQL := fmt.Sprintf("DROP TABLE IF EXISTS \"%s\" CASCADE", pgTableName)
-				if err := m.postgresConn.ExecuteDDL(dropTableSQL); err != nil {
+				if err := m.postgresConn.ExecuteDDL(ctx, dropTableSQL); err != nil {
 					errMsg := fmt.Sprintf("删除表 %s 失败: %v", table.Name, err)
 					m.logError(errMsg)
+					m.report.RecordObject("table", table.Name, ReportFailed, dropTableSQL, err)
 					<-semaphore
 					m.updateProgress()
 					return err
@@ -1277,26 +1382,32 @@ func (m *Manager) convertTables(tables []mysql.TableInfo, semaphore chan struct{
 			}
 		}
 
-		if err := m.postgresConn.ExecuteDDL(pgResult.DDL); err != nil {
+		// 快速加载模式下创建UNLOGGED表，主键、索引和CHECK约束在数据同步后添加
+		ddl := pgResult.DDL
+		if m.config.Conversion.Options.FastLoad {
+			ddl = m.createFastLoadTableDDL(pgTableName, pgResult)
+		}
+
+		if err := m.postgresConn.ExecuteDDL(ctx, ddl); err != nil {
 			errMsg := fmt.Sprintf("执行表 %s DDL失败: %v", table.Name, err)
 			m.logError(errMsg)
+			m.report.RecordObject("table", table.Name, ReportFailed, ddl, err)
 			<-semaphore
 			m.updateProgress()
 			return err
 		}
-
 		// 添加表注释
 		if pgResult.TableComment != "" {
 			processedComment := m.processComment(pgResult.TableComment)
 			tableCommentSQL := fmt.Sprintf("COMMENT ON TABLE \"%s\" IS '%s';",
-				table.Name, processedComment)
-			if err := m.postgresConn.ExecuteDDL(tableCommentSQL); err != nil {
+				pgTableName, processedComment)
+			if err := m.postgresConn.ExecuteDDL(ctx, tableCommentSQL); err != nil {
 				m.logError(fmt.Sprintf("为表 %s 添加表注释失败: %v", table.Name, err))
 			}
 		}
 
 		// 为每个列添加注释
-		m.addColumnComments(table, pgResult.ColumnNames)
+		m.addColumnComments(ctx, projected, pgTableName, pgResult.ColumnNames)
 
 		// 更新进度
 		m.mutex.Lock()
@@ -1312,6 +1423,7 @@ func (m *Manager) convertTables(tables []mysql.TableInfo, semaphore chan struct{
 		}
 
 		m.Log("转换表 %s 成功", table.Name)
+		m.report.RecordObject("table", table.Name, ReportConverted, ddl, nil)
 		<-semaphore
 	}
 	return nil
@@ -1334,7 +1446,7 @@ func (m *Manager) processComment(comment string) string {
 }
 
 // addColumnComments 为表的列添加注释
-func (m *Manager) addColumnComments(table mysql.TableInfo, columnNameMap map[string]string) {
+func (m *Manager) addColumnComments(ctx context.Context, table mysql.TableInfo, pgTableName string, columnNameMap map[string]string) {
 	for _, column := range table.Columns {
 		if column.Comment != "" {
 
@@ -1365,14 +1477,14 @@ func (m *Manager) addColumnComments(table mysql.TableInfo, columnNameMap map[str
 				if strings.HasPrefix(colName, `"`) && strings.HasSuffix(colName, `"`) {
 					// 列名已经包含双引号，直接使用
 					commentSQL = fmt.Sprintf("COMMENT ON COLUMN \"%s\".%s IS '%s';",
-						table.Name, colName, processedComment)
+						pgTableName, colName, processedComment)
 				} else {
 					// 列名不包含双引号，添加双引号
 					commentSQL = fmt.Sprintf("COMMENT ON COLUMN \"%s\".\"%s\" IS '%s';",
-						table.Name, colName, processedComment)
+						pgTableName, colName, processedComment)
 				}
 
-				if err := m.postgresConn.ExecuteDDL(commentSQL); err != nil {
+				if err := m.postgresConn.ExecuteDDL(ctx, commentSQL); err != nil {
 					// 记录尝试失败的信息，包括具体的SQL语句和错误信息
 					m.Log("为表 %s 的列 %s 使用列名 %s 添加注释失败: %v，SQL语句: %s",
 						table.Name, column.Name, colName, err, commentSQL)
@@ -1383,9 +1495,9 @@ func (m *Manager) addColumnComments(table mysql.TableInfo, columnNameMap map[str
 						rawColName := colName[1 : len(colName)-1]
 						// 尝试不带双引号的列名（PostgreSQL默认不区分大小写）
 						commentSQL = fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s';",
-							table.Name, rawColName, processedComment)
+							pgTableName, rawColName, processedComment)
 
-						if err := m.postgresConn.ExecuteDDL(commentSQL); err != nil {
+						if err := m.postgresConn.ExecuteDDL(ctx, commentSQL); err != nil {
 							// 记录尝试失败的信息
 							m.Log("为表 %s 的列 %s 使用列名 %s 添加注释失败: %v，SQL语句: %s",
 								table.Name, column.Name, rawColName, err, commentSQL)
@@ -1414,7 +1526,7 @@ func (m *Manager) addColumnComments(table mysql.TableInfo, columnNameMap map[str
 }
 
 // convertFunctions 转换函数
-func (m *Manager) convertFunctions(functions []mysql.FunctionInfo, semaphore chan struct{}) error {
+func (m *Manager) convertFunctions(ctx context.Context, functions []mysql.FunctionInfo, semaphore chan struct{}) error {
 	for _, function := range functions {
 		semaphore <- struct{}{}
 
@@ -1422,14 +1534,16 @@ func (m *Manager) convertFunctions(functions []mysql.FunctionInfo, semaphore cha
 		if err != nil {
 			errMsg := fmt.Sprintf("转换函数 %s 失败: %v", function.Name, err)
 			m.logError(errMsg)
+			m.report.RecordObject("function", function.Name, ReportFailed, pgDDL, err)
 			<-semaphore
 			m.updateProgress()
 			return err
 		}
 
-		if err := m.postgresConn.ExecuteDDL(pgDDL); err != nil {
+		if err := m.postgresConn.ExecuteDDL(ctx, pgDDL); err != nil {
 			errMsg := fmt.Sprintf("执行函数 %s DDL失败: %v", function.Name, err)
 			m.logError(errMsg)
+			m.report.RecordObject("function", function.Name, ReportFailed, pgDDL, err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1447,6 +1561,7 @@ func (m *Manager) convertFunctions(functions []mysql.FunctionInfo, semaphore cha
 			fmt.Printf("进度: %.2f%% (%d/%d) : 转换库函数 %s 成功\n", progress, m.completedTasks, m.totalTasks, function.Name)
 			m.mutex.Unlock()
 		}
+		m.report.RecordObject("function", function.Name, ReportConverted, pgDDL, nil)
 
 		<-semaphore
 	}
@@ -1455,7 +1570,7 @@ func (m *Manager) convertFunctions(functions []mysql.FunctionInfo, semaphore cha
 
 // convertIndexes 转换索引
 // 将MySQL索引转换为PostgreSQL索引并执行
-func (m *Manager) convertIndexes(indexes []mysql.IndexInfo, semaphore chan struct{}) error {
+func (m *Manager) convertIndexes(ctx context.Context, indexes []mysql.IndexInfo, semaphore chan struct{}) error {
 	for _, index := range indexes {
 		semaphore <- struct{}{}
 
@@ -1463,10 +1578,25 @@ func (m *Manager) convertIndexes(indexes []mysql.IndexInfo, semaphore chan struc
 		lowercaseIndexName := strings.ToLower(index.Name)
 		// 获取该表的列名映射
 		columnNamesMap := m.tableColumnNamesMap[index.Table]
+
+		// 按单表同步配置转换目标表名，索引引用了不同步的列时跳过
+		index, ok := projectIndex(m.config, index)
+		if !ok {
+			m.Log("索引 %s 引用了不同步的列，跳过创建", lowercaseIndexName)
+			m.report.SkipObject("index", lowercaseIndexName, "引用了不同步的列")
+			m.mutex.Lock()
+			m.completedTasks++
+			m.mutex.Unlock()
+			<-semaphore
+			m.updateProgress()
+			continue
+		}
+
 		pgDDL, err := ConvertIndexDDL(index.Table, index, m.config.Conversion.Options.LowercaseColumns, columnNamesMap)
 		if err != nil {
 			errMsg := fmt.Sprintf("转换索引 %s 失败: %v", lowercaseIndexName, err)
 			m.logError(errMsg)
+			m.report.RecordObject("index", lowercaseIndexName, ReportFailed, pgDDL, err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1474,6 +1604,7 @@ func (m *Manager) convertIndexes(indexes []mysql.IndexInfo, semaphore chan struc
 
 		// 如果没有生成DDL语句（比如只包含pri_key的索引），则跳过
 		if pgDDL == "" {
+			m.report.SkipObject("index", lowercaseIndexName, "主键索引已随表创建")
 			// 更新进度
 			m.mutex.Lock()
 			m.completedTasks++
@@ -1483,19 +1614,34 @@ func (m *Manager) convertIndexes(indexes []mysql.IndexInfo, semaphore chan struc
 			continue
 		}
 
+		// 快速加载模式下创建的表在数据同步后由 finishFastLoad 与主键一起并行创建索引
+		if m.config.Conversion.Options.FastLoad && m.deferFastLoadIndex(index.Table, lowercaseIndexName, pgDDL) {
+			m.Log("快速加载: 索引 %s 将在数据同步后创建", lowercaseIndexName)
+			m.mutex.Lock()
+			m.completedTasks++
+			m.mutex.Unlock()
+			<-semaphore
+			m.updateProgress()
+			continue
+		}
+
 		// 执行DDL语句
-		if err := m.postgresConn.ExecuteDDL(pgDDL); err != nil {
+		if err := m.postgresConn.ExecuteDDL(ctx, pgDDL); err != nil {
 			// 检查是否是索引已存在的错误
 			if strings.Contains(err.Error(), "duplicate key value violates unique constraint") ||
 				strings.Contains(err.Error(), "already exists") {
 				m.Log("索引 %s 已存在，跳过创建", lowercaseIndexName)
+				m.report.SkipObject("index", lowercaseIndexName, "索引已存在")
 			} else {
 				errMsg := fmt.Sprintf("执行索引 %s DDL失败: %v", lowercaseIndexName, err)
 				m.logError(errMsg)
+				m.report.RecordObject("index", lowercaseIndexName, ReportFailed, pgDDL, err)
 				<-semaphore
 				m.updateProgress()
 				return err
 			}
+		} else {
+			m.report.RecordObject("index", lowercaseIndexName, ReportConverted, pgDDL, nil)
 		}
 
 		// 更新进度
@@ -1517,7 +1663,7 @@ func (m *Manager) convertIndexes(indexes []mysql.IndexInfo, semaphore chan struc
 }
 
 // convertUsers 转换用户及权限
-func (m *Manager) convertUsers(users []mysql.UserInfo, semaphore chan struct{}) error {
+func (m *Manager) convertUsers(ctx context.Context, users []mysql.UserInfo, semaphore chan struct{}) error {
 	for _, user := range users {
 		semaphore <- struct{}{}
 
@@ -1525,6 +1671,7 @@ func (m *Manager) convertUsers(users []mysql.UserInfo, semaphore chan struct{})
 		if err != nil {
 			errMsg := fmt.Sprintf("转换用户 %s 失败: %v", user.Name, err)
 			m.logError(errMsg)
+			m.report.RecordObject("user", user.Name, ReportFailed, "", err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1532,9 +1679,10 @@ func (m *Manager) convertUsers(users []mysql.UserInfo, semaphore chan struct{})
 
 		// 执行每个DDL语句
 		for _, ddl := range pgDDLs {
-			if err := m.postgresConn.ExecuteDDL(ddl); err != nil {
+			if err := m.postgresConn.ExecuteDDL(ctx, ddl); err != nil {
 				errMsg := fmt.Sprintf("执行用户 %s 权限语句失败: %v", user.Name, err)
 				m.logError(errMsg)
+				m.report.RecordObject("user", user.Name, ReportFailed, strings.Join(pgDDLs, "\n"), err)
 				<-semaphore
 				m.updateProgress()
 				return err
@@ -1553,28 +1701,36 @@ func (m *Manager) convertUsers(users []mysql.UserInfo, semaphore chan struct{})
 			fmt.Printf("进度: %.2f%% (%d/%d) : 转换用户 %s 的权限成功\n", progress, m.completedTasks, m.totalTasks, user.Name)
 			m.mutex.Unlock()
 		}
+		m.report.RecordObject("user", user.Name, ReportConverted, strings.Join(pgDDLs, "\n"), nil)
 
 		<-semaphore
 	}
 	return nil
 }
 
-// syncTableData 同步表数据
-func (m *Manager) syncTableData(tables []mysql.TableInfo, semaphore chan struct{}) error {
-	return SyncTableData(
-		m.mysqlConn,
-		m.postgresConn,
-		m.config,
-		m.Log,
-		m.logError,
-		m.updateProgress,
-		&m.mutex,
-		&m.completedTasks,
-		m.totalTasks,
-		&m.inconsistentTables,
-		tables,
-		semaphore,
-	)
+// syncTableData 同步表数据，最大的表最先开始同步，整体进度按数据量统计
+// 数据源为导出文件时按文件顺序逐表同步
+func (m *Manager) syncTableData(ctx context.Context, tables []mysql.TableInfo, semaphore chan struct{}) error {
+	if m.dumpFile != nil {
+		return m.loadDumpFileData(ctx, tables)
+	}
+	tables = sortTablesBySize(tables)
+	return SyncTableData(ctx, m.mysqlConn, m.postgresConn, m.config, &DataSyncRun{
+		Log:                m.Log,
+		LogError:           m.logError,
+		UpdateProgress:     m.updateProgress,
+		Mutex:              &m.mutex,
+		CompletedTasks:     &m.completedTasks,
+		TotalTasks:         m.totalTasks,
+		InconsistentTables: &m.inconsistentTables,
+		IncrementalState:   m.incrementalState,
+		MaskingAudit:       m.maskingAudit,
+		DataFixes:          m.dataFixes,
+		Limiter:            m.limiter,
+		Rejects:            m.rejects,
+		Report:             m.report,
+		Progress:           NewDataProgress(tables),
+	}, tables, semaphore)
 }
 
 // convertTablePrivileges 转换表权限
@@ -1606,9 +1762,11 @@ func (m *Manager) convertTablePrivileges(tables []mysql.TableInfo, semaphore cha
 }
 
 // convertTablePrivilegesNew 转换表权限（新的table_privileges选项）
-func (m *Manager) convertTablePrivilegesNew(tablePrivileges []mysql.TablePrivInfo, semaphore chan struct{}) error {
+func (m *Manager) convertTablePrivilegesNew(ctx context.Context, tablePrivileges []mysql.TablePrivInfo, semaphore chan struct{}) error {
 	for _, tablePriv := range tablePrivileges {
 		semaphore <- struct{}{}
+		// 运行报告中的对象名称
+		privName := fmt.Sprintf("%s ON %s", tablePriv.User, tablePriv.TableName)
 
 		// 提取用户名（处理带主机和不带主机的情况）
 		var userName string
@@ -1620,16 +1778,18 @@ func (m *Manager) convertTablePrivilegesNew(tablePrivileges []mysql.TablePrivInf
 			userName = userParts[0]
 		} else {
 			m.Log("无效的用户名格式: %s，跳过权限授予", tablePriv.User)
+			m.report.SkipObject("privilege", privName, "无效的用户名格式")
 			<-semaphore
 			m.updateProgress()
 			continue
 		}
 
 		// 检查PostgreSQL中是否存在该表
-		tableExists, err := m.postgresConn.TableExists(tablePriv.TableName)
+		tableExists, err := m.postgresConn.TableExists(ctx, tablePriv.TableName)
 		if err != nil {
 			errMsg := fmt.Sprintf("检查表 %s 是否存在失败: %v", tablePriv.TableName, err)
 			m.logError(errMsg)
+			m.report.RecordObject("privilege", privName, ReportFailed, "", err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1637,6 +1797,7 @@ func (m *Manager) convertTablePrivilegesNew(tablePrivileges []mysql.TablePrivInf
 
 		if !tableExists {
 			m.Log("表 %s 在PostgreSQL中不存在，跳过权限授予", tablePriv.TableName)
+			m.report.SkipObject("privilege", privName, "表在PostgreSQL中不存在")
 			<-semaphore
 			m.updateProgress()
 			continue
@@ -1647,6 +1808,7 @@ func (m *Manager) convertTablePrivilegesNew(tablePrivileges []mysql.TablePrivInf
 		if err != nil {
 			errMsg := fmt.Sprintf("转换表权限失败: %v", err)
 			m.logError(errMsg)
+			m.report.RecordObject("privilege", privName, ReportFailed, "", err)
 			<-semaphore
 			m.updateProgress()
 			return err
@@ -1658,14 +1820,17 @@ func (m *Manager) convertTablePrivilegesNew(tablePrivileges []mysql.TablePrivInf
 		}
 
 		// 执行每个DDL语句
+		roleMissing := false
 		for _, ddl := range pgDDLs {
-			if err := m.postgresConn.ExecuteDDL(ddl); err != nil {
+			if err := m.postgresConn.ExecuteDDL(ctx, ddl); err != nil {
 				// 检查是否是用户不存在的错误
 				if strings.Contains(err.Error(), "role ") && strings.Contains(err.Error(), " does not exist") {
 					m.Log("用户 %s 在PostgreSQL中不存在，跳过权限授予", userName)
+					roleMissing = true
 				} else {
 					errMsg := fmt.Sprintf("执行表权限语句失败: %v", err)
 					m.logError(errMsg)
+					m.report.RecordObject("privilege", privName, ReportFailed, ddl, err)
 					<-semaphore
 					m.updateProgress()
 					return err
@@ -1673,6 +1838,12 @@ func (m *Manager) convertTablePrivilegesNew(tablePrivileges []mysql.TablePrivInf
 			}
 		}
 
+		if roleMissing {
+			m.report.SkipObject("privilege", privName, "用户在PostgreSQL中不存在")
+		} else {
+			m.report.RecordObject("privilege", privName, ReportConverted, strings.Join(pgDDLs, "\n"), nil)
+		}
+
 		// 更新进度
 		m.mutex.Lock()
 		m.completedTasks++
@@ -1768,6 +1939,33 @@ func (m *Manager) generateSummaryTable() {
 		fmt.Printf("| %-22s | %-14s | %-21.2f |\n", "总耗时", "", totalDuration)
 		fmt.Println("+--------------------------+----------------+-----------------------+")
 	}
+
+	// 数据同步的实际吞吐量
+	if stats := m.limiter.Stats(); stats.Rows > 0 {
+		summary := fmt.Sprintf("数据同步吞吐量: 读取 %d 行，%.2f MB，平均 %.2f Mbps，%.0f 行/秒",
+			stats.Rows, float64(stats.Bytes)/1000/1000, stats.EffectiveMbps, stats.EffectiveRowsS)
+		if stats.BandwidthMbps > 0 || stats.RowsPerSecond > 0 {
+			summary += fmt.Sprintf("（限速: %d Mbps，%d 行/秒，0表示不限制）", stats.BandwidthMbps, stats.RowsPerSecond)
+		}
+		if m.config.Run.ShowConsoleLogs {
+			fmt.Println(summary)
+		}
+		m.Log("%s", summary)
+	}
+}
+
+// displayInterrupted 运行被中断时显示已完成部分的汇总
+func (m *Manager) displayInterrupted() {
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n转换已中断：已提交的批次和增量同步水位已保存，未提交的批次已回滚。已完成部分的汇总如下:")
+	}
+	m.Log("转换已中断，已提交的批次和增量同步水位已保存，未提交的批次已回滚")
+
+	m.displayInconsistentTables()
+	m.displayMaskingAudit()
+	m.displayRejects()
+	m.displayDataFixes()
+	m.generateSummaryTable()
 }
 
 // centerText 居中文本
@@ -1795,6 +1993,129 @@ func (m *Manager) displayInconsistentTables() {
 			}
 			fmt.Println("+------------------+----------------+------------------+")
 		}
+		m.displayChecksumMismatches()
 		m.Log("共发现 %d 个表数据校验不一致", len(m.inconsistentTables))
 	}
 }
+
+// displayMaskingAudit 显示本次运行中进行了脱敏处理的列
+func (m *Manager) displayMaskingAudit() {
+	if len(m.maskingAudit.Entries) == 0 {
+		return
+	}
+
+	sort.Slice(m.maskingAudit.Entries, func(i, j int) bool {
+		a, b := m.maskingAudit.Entries[i], m.maskingAudit.Entries[j]
+		if a.TableName != b.TableName {
+			return a.TableName < b.TableName
+		}
+		return a.Column < b.Column
+	})
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n+------------------+------------------+------------+----------------+")
+		fmt.Println("| 脱敏列审计:                                                          |")
+		fmt.Println("+------------------+------------------+------------+----------------+")
+		fmt.Println("| 表名             | 列名             | 脱敏方式   | 脱敏值数量     |")
+		fmt.Println("+------------------+------------------+------------+----------------+")
+		for _, entry := range m.maskingAudit.Entries {
+			fmt.Printf("| %-16s | %-16s | %-10s | %-14d |\n", entry.TableName, entry.Column, entry.Action, entry.Values)
+		}
+		fmt.Println("+------------------+------------------+------------+----------------+")
+	}
+	for _, entry := range m.maskingAudit.Entries {
+		m.Log("脱敏审计: 表 %s 列 %s 使用 %s 方式脱敏 %d 个值", entry.TableName, entry.Column, entry.Action, entry.Values)
+	}
+}
+
+// displayRejects 显示各表写入失败被隔离的行数
+func (m *Manager) displayRejects() {
+	if m.rejects == nil || len(m.rejects.Counts) == 0 {
+		return
+	}
+
+	tableNames := make([]string, 0, len(m.rejects.Counts))
+	var total int64
+	for tableName, count := range m.rejects.Counts {
+		tableNames = append(tableNames, tableName)
+		total += count
+	}
+	sort.Strings(tableNames)
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n+----------------------------------+----------------+")
+		fmt.Println("| 写入失败被隔离的行:                               |")
+		fmt.Println("+----------------------------------+----------------+")
+		fmt.Println("| 表名                             | 隔离行数       |")
+		fmt.Println("+----------------------------------+----------------+")
+		for _, tableName := range tableNames {
+			fmt.Printf("| %-32s | %-14d |\n", tableName, m.rejects.Counts[tableName])
+		}
+		fmt.Println("+----------------------------------+----------------+")
+		fmt.Printf("隔离行已保存到 %s\n", m.rejects.Location())
+	}
+	for _, tableName := range tableNames {
+		m.Log("表 %s 有 %d 行写入失败，已隔离到 %s", tableName, m.rejects.Counts[tableName], m.rejects.Location())
+	}
+	m.Log("共隔离 %d 行", total)
+}
+
+// displayDataFixes 显示各表按无效数据处理策略处理的值的数量
+func (m *Manager) displayDataFixes() {
+	if len(m.dataFixes.Entries) == 0 {
+		return
+	}
+
+	sort.Slice(m.dataFixes.Entries, func(i, j int) bool {
+		a, b := m.dataFixes.Entries[i], m.dataFixes.Entries[j]
+		if a.TableName != b.TableName {
+			return a.TableName < b.TableName
+		}
+		return a.Problem < b.Problem
+	})
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n+------------------+----------------+------------+----------------+")
+		fmt.Println("| 无效数据处理统计:                                                |")
+		fmt.Println("+------------------+----------------+------------+----------------+")
+		fmt.Println("| 表名             | 问题类型       | 处理策略   | 值数量         |")
+		fmt.Println("+------------------+----------------+------------+----------------+")
+		for _, entry := range m.dataFixes.Entries {
+			fmt.Printf("| %-16s | %-14s | %-10s | %-14d |\n", entry.TableName, entry.Problem, entry.Action, entry.Values)
+		}
+		fmt.Println("+------------------+----------------+------------+----------------+")
+	}
+	for _, entry := range m.dataFixes.Entries {
+		m.Log("无效数据: 表 %s 发现 %d 个 %s，处理策略 %s", entry.TableName, entry.Values, entry.Problem, entry.Action)
+	}
+}
+
+// displayChecksumMismatches 显示校验和不一致的主键范围和行示例
+func (m *Manager) displayChecksumMismatches() {
+	for _, table := range m.inconsistentTables {
+		if table.Checksum == nil || !table.Checksum.Mismatched {
+			continue
+		}
+
+		var lines []string
+		if table.Checksum.WholeTable {
+			lines = append(lines, fmt.Sprintf("表 %s 内容校验和不一致（%s，仅整表比较）", table.TableName, table.Checksum.WholeTableReason))
+		} else {
+			lines = append(lines, fmt.Sprintf("表 %s 内容校验和不一致，主键 %s，共 %d 个分块，不一致范围 %d 个:",
+				table.TableName, table.Checksum.PrimaryKey, table.Checksum.ChunkCount, len(table.Checksum.MismatchedRanges)))
+			for _, r := range table.Checksum.MismatchedRanges {
+				lines = append(lines, fmt.Sprintf("  范围 %s", r.String()))
+			}
+			for _, row := range table.Checksum.SampleRows {
+				lines = append(lines, fmt.Sprintf("  示例行 %s = %s: %s", table.Checksum.PrimaryKey, row.PrimaryKey, row.Reason))
+			}
+		}
+
+		for _, line := range lines {
+			if m.config.Run.ShowConsoleLogs {
+				fmt.Println(line)
+			}
+			m.Log("%s", line)
+		}
+	}
+}
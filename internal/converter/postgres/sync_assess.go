@@ -0,0 +1,431 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+// 评估报告中列类型问题的严重程度
+const (
+	AssessUnsupported = "unsupported" // PostgreSQL中没有对应的类型，按其他类型保存
+	AssessLossy       = "lossy"       // 转换后可能丢失数据或取值约束
+	AssessChanged     = "changed"     // 转换后类型语义改变，数据不丢失
+)
+
+// 评估报告中视图和函数的转换状态
+const (
+	AssessObjectOK     = "ok"     // 转换成功，没有发现需要手工处理的写法
+	AssessObjectManual = "manual" // 转换成功，但包含需要手工检查或改写的写法
+	AssessObjectFailed = "failed" // 转换失败
+)
+
+var (
+	reAssessUnsignedInt = regexp.MustCompile(`(?i)^(bigint|int|integer|smallint)\b.*\bunsigned\b`)
+	reAssessSpatial     = regexp.MustCompile(`(?i)^(geometry|linestring|polygon|multipoint|multilinestring|multipolygon|geometrycollection|geomcollection)\b`)
+	reAssessBitN        = regexp.MustCompile(`(?i)^bit\((\d+)\)`)
+	reAssessCharset     = regexp.MustCompile(`(?i)\bDEFAULT\s+(?:CHARSET|CHARACTER\s+SET)\s*=\s*(\w+)`)
+	reAssessCollate     = regexp.MustCompile(`(?i)\bCOLLATE\s*=\s*(\w+)`)
+	reAssessColCollate  = regexp.MustCompile(`(?i)\bCOLLATE\s+(\w+)`)
+	reAssessPartition   = regexp.MustCompile(`(?i)\bPARTITION\s+BY\s+(?:LINEAR\s+)?(\w+)`)
+	reAssessPartitions  = regexp.MustCompile(`(?i)\bPARTITION\s+\x60?\w+\x60?\s+VALUES\b|\bPARTITIONS\s+(\d+)`)
+)
+
+// assessConstruct 需要手工处理的SQL写法
+type assessConstruct struct {
+	pattern *regexp.Regexp
+	name    string
+}
+
+// viewManualConstructs 视图转换后仍保留的MySQL特有写法，在转换后的定义中检查
+var viewManualConstructs = []assessConstruct{
+	{regexp.MustCompile(`(?i)\bgroup_concat\s*\(`), "GROUP_CONCAT（未能转换的写法）"},
+	{regexp.MustCompile(`(?i)\bif\s*\(`), "IF() 函数（嵌套或复杂参数未转换）"},
+	{regexp.MustCompile(`(?i)\bfind_in_set\s*\(`), "FIND_IN_SET"},
+	{regexp.MustCompile(`(?i)\bfield\s*\(`), "FIELD"},
+	{regexp.MustCompile(`(?i)\bmatch\s*\(.*?\)\s*against\b`), "MATCH ... AGAINST 全文检索"},
+	{regexp.MustCompile(`(?i)\bsql_calc_found_rows\b|\bfound_rows\s*\(`), "SQL_CALC_FOUND_ROWS / FOUND_ROWS"},
+	{regexp.MustCompile(`(?i)\bstraight_join\b`), "STRAIGHT_JOIN"},
+	{regexp.MustCompile(`(?i)\b(regexp|rlike)\b`), "REGEXP / RLIKE"},
+	{regexp.MustCompile(`<=>`), "<=> 空值安全比较"},
+	{regexp.MustCompile(`(?i)\bconvert\s*\([^)]*\busing\b`), "CONVERT ... USING"},
+}
+
+// functionManualConstructs 函数转换时会被移除或无法转换的MySQL写法，在MySQL定义中检查
+var functionManualConstructs = []assessConstruct{
+	{regexp.MustCompile(`(?i)\bDECLARE\s+(CONTINUE|EXIT|UNDO)\s+HANDLER\b`), "DECLARE ... HANDLER（转换时移除，需改写为 EXCEPTION 块）"},
+	{regexp.MustCompile(`(?i)\b(RE)?SIGNAL\s+SQLSTATE\b`), "SIGNAL / RESIGNAL（需改写为 RAISE）"},
+	{regexp.MustCompile(`(?i)\bPREPARE\s+\w+\s+FROM\b`), "PREPARE 动态SQL（需改写为 EXECUTE ... USING）"},
+	{regexp.MustCompile(`(?i)\bCALL\s+\w+`), "CALL 存储过程（存储过程不转换）"},
+	{regexp.MustCompile(`(?i)\blast_insert_id\s*\(`), "LAST_INSERT_ID（需改用 RETURNING 或 currval）"},
+	{regexp.MustCompile(`(?i)\bfound_rows\s*\(`), "FOUND_ROWS"},
+	{regexp.MustCompile(`(?i)\bfind_in_set\s*\(`), "FIND_IN_SET"},
+	{regexp.MustCompile(`(?i)\bget_lock\s*\(|\brelease_lock\s*\(`), "GET_LOCK / RELEASE_LOCK（可改用 pg_advisory_lock）"},
+	{regexp.MustCompile(`@@\w+`), "系统变量"},
+	{regexp.MustCompile(`(?i)\bLOCK\s+TABLES\b`), "LOCK TABLES"},
+}
+
+// Assessment 迁移前的评估报告
+type Assessment struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Source      string            `json:"source"`
+	Inventory   AssessInventory   `json:"inventory"`
+	TotalRows   int64             `json:"total_rows"`
+	TotalBytes  int64             `json:"total_bytes"`
+	CopyTime    AssessCopyTime    `json:"copy_time"`
+	Tables      []AssessedTable   `json:"tables"`
+	Columns     []AssessedColumn  `json:"columns"`
+	Views       []AssessedObject  `json:"views"`
+	Functions   []AssessedObject  `json:"functions"`
+	Collations  []AssessCollation `json:"collations"`
+	Skipped     []string          `json:"skipped"` // 没有对应转换的对象，如存储过程和触发器
+}
+
+// AssessInventory 对象数量
+type AssessInventory struct {
+	Tables     int `json:"tables"`
+	Views      int `json:"views"`
+	Indexes    int `json:"indexes"`
+	Functions  int `json:"functions"`
+	Procedures int `json:"procedures"`
+	Triggers   int `json:"triggers"`
+	Users      int `json:"users"`
+}
+
+// AssessCopyTime 估算的数据复制时间
+type AssessCopyTime struct {
+	Seconds       float64 `json:"seconds"`
+	StreamMBps    float64 `json:"stream_mbps"`    // 估算使用的单表吞吐量（MB/s）
+	Concurrency   int     `json:"concurrency"`    // 估算使用的并发数
+	BandwidthMbps int     `json:"bandwidth_mbps"` // 带宽上限，0表示不限制
+}
+
+// AssessedTable 表的评估结果
+type AssessedTable struct {
+	Name          string   `json:"name"`
+	TargetName    string   `json:"target_name"`
+	Rows          int64    `json:"rows"`
+	Bytes         int64    `json:"bytes"`
+	Charset       string   `json:"charset,omitempty"`
+	Collation     string   `json:"collation,omitempty"`
+	HasPrimaryKey bool     `json:"has_primary_key"`
+	Partitioning  string   `json:"partitioning,omitempty"` // 分区方式和分区数，如 RANGE (12)
+	Issues        []string `json:"issues,omitempty"`
+}
+
+// AssessedColumn 转换时有问题的列
+type AssessedColumn struct {
+	Table        string `json:"table"`
+	Column       string `json:"column"`
+	MySQLType    string `json:"mysql_type"`
+	PostgresType string `json:"postgres_type"`
+	Severity     string `json:"severity"`
+	Note         string `json:"note"`
+}
+
+// AssessedObject 视图或函数的评估结果
+type AssessedObject struct {
+	Name       string   `json:"name"`
+	Status     string   `json:"status"`
+	Error      string   `json:"error,omitempty"`
+	Constructs []string `json:"constructs,omitempty"` // 需要手工处理的写法
+}
+
+// AssessCollation 排序规则的使用情况
+type AssessCollation struct {
+	Collation string `json:"collation"`
+	Tables    int    `json:"tables"`
+	Columns   int    `json:"columns"`
+	Note      string `json:"note,omitempty"`
+}
+
+// Assess 迁移前评估：读取MySQL元数据并以分析方式执行所有转换，不连接PostgreSQL也不写入任何数据
+// streamMBps 为估算复制时间使用的单表吞吐量（MB/s）
+func (m *Manager) Assess(ctx context.Context, streamMBps float64) (*Assessment, error) {
+	m.Log("评估迁移，不连接PostgreSQL ...")
+
+	tables, functions, indexes, views, users, _, err := m.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var procedures, triggers []string
+	if m.dumpFile != nil {
+		procedures, triggers = m.dumpFile.Procedures, m.dumpFile.Triggers
+	} else {
+		if procedures, err = m.mysqlConn.GetProcedureNames(ctx); err != nil {
+			return nil, err
+		}
+		if triggers, err = m.mysqlConn.GetTriggerNames(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	a := &Assessment{
+		GeneratedAt: time.Now(),
+		Source:      m.assessSource(),
+		Inventory: AssessInventory{
+			Tables:     len(tables),
+			Views:      len(views),
+			Indexes:    len(indexes),
+			Functions:  len(functions),
+			Procedures: len(procedures),
+			Triggers:   len(triggers),
+			Users:      len(users),
+		},
+		Tables:     []AssessedTable{},
+		Columns:    []AssessedColumn{},
+		Views:      []AssessedObject{},
+		Functions:  []AssessedObject{},
+		Collations: []AssessCollation{},
+		Skipped:    []string{},
+	}
+	for _, name := range procedures {
+		a.Skipped = append(a.Skipped, "存储过程 "+name)
+	}
+	for _, name := range triggers {
+		a.Skipped = append(a.Skipped, "触发器 "+name)
+	}
+
+	collations := make(map[string]*AssessCollation)
+	useCollation := func(name string, table bool) {
+		if name == "" {
+			return
+		}
+		c := collations[name]
+		if c == nil {
+			c = &AssessCollation{Collation: name}
+			if strings.HasSuffix(strings.ToLower(name), "_ci") {
+				c.Note = "不区分大小写：PostgreSQL默认区分大小写，比较、排序和唯一约束的结果可能不同，需要 citext 或非确定性排序规则"
+			}
+			collations[name] = c
+		}
+		if table {
+			c.Tables++
+		} else {
+			c.Columns++
+		}
+	}
+
+	var sizes []int64
+	for _, table := range sortTablesBySize(tables) {
+		assessed := m.assessTable(a, table, useCollation)
+		a.Tables = append(a.Tables, assessed)
+		a.TotalRows += assessed.Rows
+		a.TotalBytes += assessed.Bytes
+		sizes = append(sizes, assessed.Bytes)
+	}
+
+	for _, view := range views {
+		object := AssessedObject{Name: view.ViewName, Status: AssessObjectOK}
+		pgDDL, err := ConvertViewDDL(view.ViewName, view.ViewDefinition, m.config.MySQL.Database)
+		if err != nil {
+			object.Status, object.Error = AssessObjectFailed, err.Error()
+		} else if object.Constructs = findConstructs(viewManualConstructs, pgDDL); len(object.Constructs) > 0 {
+			object.Status = AssessObjectManual
+		}
+		a.Views = append(a.Views, object)
+	}
+
+	for _, function := range functions {
+		object := AssessedObject{Name: function.Name, Status: AssessObjectOK}
+		object.Constructs = findConstructs(functionManualConstructs, function.DDL)
+		if _, err := ConvertFunctionDDL(function); err != nil {
+			object.Status, object.Error = AssessObjectFailed, err.Error()
+		} else if len(object.Constructs) > 0 {
+			object.Status = AssessObjectManual
+		}
+		a.Functions = append(a.Functions, object)
+	}
+
+	for _, c := range collations {
+		a.Collations = append(a.Collations, *c)
+	}
+	sort.Slice(a.Collations, func(i, j int) bool { return a.Collations[i].Collation < a.Collations[j].Collation })
+
+	limits := m.config.Conversion.Limits
+	a.CopyTime = AssessCopyTime{
+		Seconds:       estimateCopySeconds(sizes, limits.Concurrency, streamMBps, limits.BandwidthMbps),
+		StreamMBps:    streamMBps,
+		Concurrency:   limits.Concurrency,
+		BandwidthMbps: limits.BandwidthMbps,
+	}
+
+	m.Log("评估完成，%d 个表，%d 个列类型问题，%d 个视图和 %d 个函数需要处理", len(a.Tables), len(a.Columns), a.countObjects(a.Views), a.countObjects(a.Functions))
+	return a, nil
+}
+
+// assessSource 数据源说明
+func (m *Manager) assessSource() string {
+	if m.dumpFile != nil {
+		return "mysqldump 导出文件 " + m.config.MySQL.DumpFile
+	}
+	return fmt.Sprintf("MySQL %s:%d/%s", m.config.MySQL.Host, m.config.MySQL.Port, m.config.MySQL.Database)
+}
+
+// assessTable 评估一个表：数据量、主键、字符集和排序规则、分区、列类型以及建表DDL的转换
+func (m *Manager) assessTable(a *Assessment, table mysql.TableInfo, useCollation func(name string, table bool)) AssessedTable {
+	assessed := AssessedTable{
+		Name:       table.Name,
+		TargetName: targetTableName(m.config, table.Name),
+		Rows:       table.Size.Rows,
+		Bytes:      table.Size.DataLength,
+	}
+	for _, index := range table.Indexes {
+		if index.Name == "PRIMARY" {
+			assessed.HasPrimaryKey = true
+		}
+	}
+	if !assessed.HasPrimaryKey {
+		assessed.Issues = append(assessed.Issues, "没有主键：无法使用 upsert 写入、增量同步和按主键分块校验")
+	}
+
+	// 表选项在列定义结束的 ")" 之后的一行
+	var options string
+	for _, line := range strings.Split(table.DDL, "\n") {
+		if strings.HasPrefix(line, ")") {
+			options = line
+			break
+		}
+	}
+	if match := reAssessCharset.FindStringSubmatch(options); match != nil {
+		assessed.Charset = match[1]
+	}
+	if match := reAssessCollate.FindStringSubmatch(options); match != nil {
+		assessed.Collation = match[1]
+	}
+	useCollation(assessed.Collation, true)
+
+	if match := reAssessPartition.FindStringSubmatch(table.DDL); match != nil {
+		assessed.Partitioning = strings.ToUpper(match[1])
+		count := 0
+		for _, p := range reAssessPartitions.FindAllStringSubmatch(table.DDL, -1) {
+			if p[1] != "" {
+				fmt.Sscanf(p[1], "%d", &count)
+				break
+			}
+			count++
+		}
+		if count > 0 {
+			assessed.Partitioning += fmt.Sprintf(" (%d)", count)
+		}
+	}
+
+	for _, column := range table.Columns {
+		if note, pgType, severity := assessColumnType(column.Type); note != "" {
+			a.Columns = append(a.Columns, AssessedColumn{
+				Table:        table.Name,
+				Column:       column.Name,
+				MySQLType:    column.Type,
+				PostgresType: pgType,
+				Severity:     severity,
+				Note:         note,
+			})
+		}
+	}
+	for _, line := range strings.Split(table.DDL, "\n") {
+		line = strings.TrimSpace(line)
+		if reColumnDefinitionLine.MatchString(line) {
+			if match := reAssessColCollate.FindStringSubmatch(line); match != nil {
+				useCollation(match[1], false)
+			}
+		}
+	}
+
+	// 以分析方式转换建表DDL，收集转换失败和转换时移除的定义
+	projected, _, err := m.buildTableDDL(table)
+	if err != nil {
+		assessed.Issues = append(assessed.Issues, fmt.Sprintf("转换表结构失败: %v", err))
+	} else {
+		assessed.Issues = append(assessed.Issues, tableConversionWarnings(projected.DDL)...)
+	}
+	return assessed
+}
+
+// assessColumnType 检查MySQL列类型转换到PostgreSQL时的问题，没有问题时 note 为空
+func assessColumnType(mysqlType string) (note, pgType, severity string) {
+	lower := strings.ToLower(strings.TrimSpace(mysqlType))
+	pgType, _, _ = convertDataType(lower)
+	switch {
+	case reAssessUnsignedInt.MatchString(lower):
+		limits := map[string]string{"bigint": "9223372036854775807", "int": "2147483647", "integer": "2147483647", "smallint": "32767"}
+		base := strings.ToLower(reAssessUnsignedInt.FindStringSubmatch(lower)[1])
+		return fmt.Sprintf("UNSIGNED 取值超过 %s 的值无法写入 %s", limits[base], pgType), pgType, AssessLossy
+	case reTinyInt1.MatchString(lower):
+		return "0 和 1 以外的值转换为 BOOLEAN 后无法还原", pgType, AssessLossy
+	case strings.HasPrefix(lower, "enum(") || strings.HasPrefix(lower, "set("):
+		return "允许取值的约束丢失，转换为普通字符串", pgType, AssessLossy
+	case strings.HasPrefix(lower, "time") && !strings.HasPrefix(lower, "timestamp"):
+		return "MySQL TIME 可以超过 24 小时或为负值，PostgreSQL TIME 不支持", pgType, AssessLossy
+	case reAssessSpatial.MatchString(lower):
+		return "空间类型以 WKB 字节保存为 BYTEA，需要空间计算时需改用 PostGIS", pgType, AssessUnsupported
+	case strings.HasPrefix(lower, "year"):
+		return "YEAR 转换为整数", pgType, AssessChanged
+	case reAssessBitN.MatchString(lower) && reAssessBitN.FindStringSubmatch(lower)[1] != "1":
+		return "BIT(n) 转换为 BIT VARYING，按位串而不是整数比较", pgType, AssessChanged
+	}
+	return "", pgType, ""
+}
+
+// findConstructs 查找SQL中需要手工处理的写法
+func findConstructs(constructs []assessConstruct, sql string) []string {
+	var found []string
+	for _, c := range constructs {
+		if c.pattern.MatchString(sql) {
+			found = append(found, c.name)
+		}
+	}
+	return found
+}
+
+// countObjects 统计需要手工处理或转换失败的视图或函数数量
+func (a *Assessment) countObjects(objects []AssessedObject) int {
+	count := 0
+	for _, object := range objects {
+		if object.Status != AssessObjectOK {
+			count++
+		}
+	}
+	return count
+}
+
+// estimateCopySeconds 按从大到小的调度顺序估算数据复制时间（秒）
+// 每个表一个同步流，吞吐量为 streamMBps，最多 concurrency 个表同时同步；设置了带宽上限时不少于总量按带宽上限复制的时间
+func estimateCopySeconds(sizes []int64, concurrency int, streamMBps float64, bandwidthMbps int) float64 {
+	if streamMBps <= 0 || len(sizes) == 0 {
+		return 0
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	// 按大小降序将每个表分配给当前负载最小的同步流
+	loads := make([]int64, concurrency)
+	var total int64
+	for _, size := range sizes {
+		least := 0
+		for i := range loads {
+			if loads[i] < loads[least] {
+				least = i
+			}
+		}
+		loads[least] += size
+		total += size
+	}
+	var longest int64
+	for _, load := range loads {
+		if load > longest {
+			longest = load
+		}
+	}
+	seconds := float64(longest) / (streamMBps * 1000 * 1000)
+	if bandwidthMbps > 0 {
+		if limited := float64(total) / (float64(bandwidthMbps) * 1000 * 1000 / 8); limited > seconds {
+			seconds = limited
+		}
+	}
+	return seconds
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// assessReportTemplate 评估报告的HTML模板，样式内嵌，单个文件即可查看
+var assessReportTemplate = template.Must(template.New("assess").Funcs(template.FuncMap{
+	"bytes":    formatAssessBytes,
+	"duration": formatAssessDuration,
+	"pending":  func(a *Assessment, objects []AssessedObject) int { return a.countObjects(objects) },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>MySQL2PG 迁移评估报告</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.25em; margin-top: 2em; border-bottom: 1px solid #ddd; padding-bottom: .3em; }
+table { border-collapse: collapse; margin: .5em 0; font-size: .9em; }
+th, td { border: 1px solid #ddd; padding: .35em .7em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; } td.num { text-align: right; }
+.ok { color: #2e7d32; } .manual, .changed { color: #ef6c00; } .failed, .lossy, .unsupported { color: #c62828; }
+.summary td { min-width: 8em; } ul { margin: 0; padding-left: 1.2em; } .muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>MySQL2PG 迁移评估报告</h1>
+<p class="muted">数据源: {{.Source}}，生成时间: {{.GeneratedAt.Format "2006-01-02 15:04:05"}}</p>
+
+<h2>概览</h2>
+<table class="summary">
+<tr><th>表</th><td class="num">{{.Inventory.Tables}}</td><th>数据量</th><td class="num">{{bytes .TotalBytes}}</td></tr>
+<tr><th>视图</th><td class="num">{{.Inventory.Views}}</td><th>估计行数</th><td class="num">{{.TotalRows}}</td></tr>
+<tr><th>索引</th><td class="num">{{.Inventory.Indexes}}</td><th>估计复制时间</th><td class="num">{{duration .CopyTime.Seconds}}</td></tr>
+<tr><th>函数</th><td class="num">{{.Inventory.Functions}}</td><th>列类型问题</th><td class="num">{{len .Columns}}</td></tr>
+<tr><th>存储过程</th><td class="num">{{.Inventory.Procedures}}</td><th>需处理的视图</th><td class="num">{{pending . .Views}}</td></tr>
+<tr><th>触发器</th><td class="num">{{.Inventory.Triggers}}</td><th>需处理的函数</th><td class="num">{{pending . .Functions}}</td></tr>
+<tr><th>用户</th><td class="num">{{.Inventory.Users}}</td><th></th><td></td></tr>
+</table>
+<p class="muted">复制时间按每个表 {{.CopyTime.StreamMBps}} MB/s、并发 {{.CopyTime.Concurrency}} 个表从大到小调度估算{{if .CopyTime.BandwidthMbps}}，带宽上限 {{.CopyTime.BandwidthMbps}} Mbps{{end}}。</p>
+
+<h2>表</h2>
+<table>
+<tr><th>表</th><th>目标表</th><th>估计行数</th><th>数据量</th><th>主键</th><th>字符集</th><th>排序规则</th><th>分区</th><th>问题</th></tr>
+{{range .Tables}}<tr>
+<td>{{.Name}}</td><td>{{.TargetName}}</td><td class="num">{{.Rows}}</td><td class="num">{{bytes .Bytes}}</td>
+<td>{{if .HasPrimaryKey}}<span class="ok">有</span>{{else}}<span class="failed">无</span>{{end}}</td>
+<td>{{.Charset}}</td><td>{{.Collation}}</td><td>{{.Partitioning}}</td>
+<td>{{if .Issues}}<ul>{{range .Issues}}<li>{{.}}</li>{{end}}</ul>{{end}}</td>
+</tr>
+{{end}}</table>
+
+<h2>列类型</h2>
+{{if .Columns}}<table>
+<tr><th>表</th><th>列</th><th>MySQL类型</th><th>PostgreSQL类型</th><th>级别</th><th>说明</th></tr>
+{{range .Columns}}<tr><td>{{.Table}}</td><td>{{.Column}}</td><td>{{.MySQLType}}</td><td>{{.PostgresType}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Note}}</td></tr>
+{{end}}</table>{{else}}<p class="ok">没有不支持或有损转换的列类型</p>{{end}}
+
+<h2>视图</h2>
+{{template "objects" .Views}}
+
+<h2>函数</h2>
+{{template "objects" .Functions}}
+
+<h2>排序规则</h2>
+{{if .Collations}}<table>
+<tr><th>排序规则</th><th>表</th><th>列</th><th>说明</th></tr>
+{{range .Collations}}<tr><td>{{.Collation}}</td><td class="num">{{.Tables}}</td><td class="num">{{.Columns}}</td><td>{{.Note}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">表定义中没有显式的排序规则</p>{{end}}
+
+{{if .Skipped}}<h2>不转换的对象</h2>
+<ul>{{range .Skipped}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+{{define "objects"}}{{if .}}<table>
+<tr><th>名称</th><th>状态</th><th>需要手工处理的写法 / 错误</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td class="{{.Status}}">{{.Status}}</td>
+<td>{{if .Error}}{{.Error}}{{end}}{{if .Constructs}}<ul>{{range .Constructs}}<li>{{.}}</li>{{end}}</ul>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">无</p>{{end}}{{end}}
+`))
+
+// WriteHTML 以HTML格式输出评估报告
+func (a *Assessment) WriteHTML(w io.Writer) error {
+	if err := assessReportTemplate.Execute(w, a); err != nil {
+		return fmt.Errorf("生成评估报告失败: %w", err)
+	}
+	return nil
+}
+
+// formatAssessBytes 以合适的单位显示字节数
+func formatAssessBytes(bytes int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(bytes)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+// formatAssessDuration 显示估算的时间，精确到秒
+func formatAssessDuration(seconds float64) string {
+	return (time.Duration(seconds * float64(time.Second))).Round(time.Second).String()
+}
+
+// WriteSummary 以文本形式输出评估报告的概要
+func (a *Assessment) WriteSummary(w io.Writer) {
+	noPrimaryKey := 0
+	for _, table := range a.Tables {
+		if !table.HasPrimaryKey {
+			noPrimaryKey++
+		}
+	}
+	fmt.Fprintf(w, "对象: %d 个表，%d 个视图，%d 个索引，%d 个函数，%d 个存储过程，%d 个触发器，%d 个用户\n",
+		a.Inventory.Tables, a.Inventory.Views, a.Inventory.Indexes, a.Inventory.Functions, a.Inventory.Procedures, a.Inventory.Triggers, a.Inventory.Users)
+	fmt.Fprintf(w, "数据: 估计 %d 行，%s，估计复制时间 %s\n", a.TotalRows, formatAssessBytes(a.TotalBytes), formatAssessDuration(a.CopyTime.Seconds))
+	fmt.Fprintf(w, "问题: %d 个表没有主键，%d 个列类型问题，%d 个视图和 %d 个函数需要处理\n", noPrimaryKey, len(a.Columns), a.countObjects(a.Views), a.countObjects(a.Functions))
+}
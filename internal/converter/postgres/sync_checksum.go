@@ -0,0 +1,501 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+)
+
+const (
+	// checksumLeafRows 分块下钻到不超过该行数时停止拆分，逐行比较
+	checksumLeafRows = 1000
+	// checksumMaxSampleRows 每个表最多记录的不一致行示例数
+	checksumMaxSampleRows = 10
+	// checksumNullMarker 行内容规范化时NULL值的占位文本
+	checksumNullMarker = "#NULL#"
+)
+
+// 整数主键类型，只有整数主键才能按主键范围分块
+var checksumIntegerKeyPattern = regexp.MustCompile(`(?i)^(tiny|small|medium|big)?int(eger)?\b`)
+
+// 无符号整数主键，BIGINT UNSIGNED 的值可能超出int64范围，按uint64处理
+var checksumUnsignedKeyPattern = regexp.MustCompile(`(?i)\bunsigned\b`)
+
+// checksumSignBit 有符号整数翻转符号位后按uint64比较，顺序与原值一致
+const checksumSignBit = uint64(1) << 63
+
+// ChecksumKey 分块使用的整数主键值
+// 有符号和无符号主键统一保序映射到uint64后划分范围，BIGINT UNSIGNED 超出int64范围的值也可以分块
+type ChecksumKey struct {
+	ordinal  uint64
+	unsigned bool
+}
+
+// parseChecksumKey 解析十进制文本形式的主键值
+func parseChecksumKey(text string, unsigned bool) (ChecksumKey, error) {
+	if unsigned {
+		v, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return ChecksumKey{}, fmt.Errorf("无效的主键值 %q: %w", text, err)
+		}
+		return ChecksumKey{ordinal: v, unsigned: true}, nil
+	}
+	v, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return ChecksumKey{}, fmt.Errorf("无效的主键值 %q: %w", text, err)
+	}
+	return ChecksumKey{ordinal: uint64(v) ^ checksumSignBit}, nil
+}
+
+// Arg MySQL查询参数，保持整数类型，避免MySQL按浮点数比较大整数
+func (k ChecksumKey) Arg() interface{} {
+	if k.unsigned {
+		return k.ordinal
+	}
+	return int64(k.ordinal ^ checksumSignBit)
+}
+
+// String 主键值的十进制文本，PostgreSQL查询参数以文本格式传递并由服务端转换为列类型
+func (k ChecksumKey) String() string {
+	if k.unsigned {
+		return strconv.FormatUint(k.ordinal, 10)
+	}
+	return strconv.FormatInt(int64(k.ordinal^checksumSignBit), 10)
+}
+
+// postgresKeyBounds PostgreSQL端主键范围的查询参数
+// BIGINT UNSIGNED 在PostgreSQL中转换为BIGINT，超出int64范围的边界截断到int64最大值，
+// 范围整体超出时返回空范围 [1, 0]，避免参数超出列类型范围导致查询失败
+func postgresKeyBounds(start, end ChecksumKey) (lower, upper string) {
+	const maxInt64 = uint64(math.MaxInt64)
+	if !start.unsigned {
+		return start.String(), end.String()
+	}
+	if start.ordinal > maxInt64 {
+		return "1", "0"
+	}
+	if end.ordinal > maxInt64 {
+		end.ordinal = maxInt64
+	}
+	return start.String(), end.String()
+}
+
+// ChecksumRange 校验和不一致的主键范围（闭区间）
+type ChecksumRange struct {
+	Start        ChecksumKey // 主键范围起始值（含）
+	End          ChecksumKey // 主键范围结束值（含）
+	MySQLRows    int64       // 范围内MySQL行数
+	PostgresRows int64       // 范围内PostgreSQL行数
+}
+
+// RowMismatch 不一致行示例
+type RowMismatch struct {
+	PrimaryKey string // 主键值
+	Reason     string // 不一致原因
+}
+
+// ChecksumResult 单表校验和比较结果
+type ChecksumResult struct {
+	PrimaryKey       string          // 分块使用的主键列，整表比较时为空
+	ChunkCount       int             // 比较的分块数
+	WholeTable       bool            // 无可分块的单列整数主键，只进行了整表比较
+	WholeTableReason string          // 整表比较的原因
+	Mismatched       bool            // 是否存在不一致
+	MismatchedRanges []ChecksumRange // 不一致的主键范围
+	SampleRows       []RowMismatch   // 不一致行示例
+	SkippedColumns   []string        // 未参与校验的列（空间类型、BIT等）
+}
+
+// String 主键范围的文本表示
+func (r ChecksumRange) String() string {
+	return fmt.Sprintf("[%s, %s] (MySQL %d 行, PostgreSQL %d 行)", r.Start, r.End, r.MySQLRows, r.PostgresRows)
+}
+
+// checksumExpressions 构建MySQL和PostgreSQL两端的行内容规范化表达式
+// 两端按MySQL列类型将每列转换为相同的文本表示，再以 '|' 连接；uuidColumns 中的列按转换后的uuid比较
+// jsonbColumns 中的列在PostgreSQL中已规范化（键排序、去除空白），无法与MySQL的原始文本比较，不参与比较
+func checksumExpressions(columns []string, columnTypes map[string]string, uuidColumns, jsonbColumns map[string]bool) (mysqlExpr, pgExpr string, skipped []string) {
+	var mysqlParts, pgParts []string
+	for _, col := range columns {
+		lowerType := strings.ToLower(strings.TrimSpace(columnTypes[col]))
+		baseType := lowerType
+		if idx := strings.IndexAny(baseType, "( "); idx > 0 {
+			baseType = baseType[:idx]
+		}
+
+		my := fmt.Sprintf("`%s`", col)
+		pg := fmt.Sprintf(`"%s"`, strings.ToLower(col))
+
+		if jsonbColumns[strings.ToLower(col)] {
+			skipped = append(skipped, col)
+			continue
+		}
+
+		var myExpr, pgExprPart string
+		if swapFlag, ok := uuidColumns[strings.ToLower(col)]; ok {
+			// 按标准顺序的十六进制比较，UUID_TO_BIN(uuid, 1) 存储的值先还原时间字段的顺序
+			hex := fmt.Sprintf("HEX(%s)", my)
+			if swapFlag {
+				hex = fmt.Sprintf("CONCAT(SUBSTR(%s, 9, 8), SUBSTR(%s, 5, 4), SUBSTR(%s, 1, 4), SUBSTR(%s, 17))", hex, hex, hex, hex)
+			}
+			mysqlParts = append(mysqlParts, fmt.Sprintf("COALESCE(LOWER(%s), '%s')", hex, checksumNullMarker))
+			pgParts = append(pgParts, fmt.Sprintf("COALESCE(REPLACE(%s::TEXT, '-', ''), '%s')", pg, checksumNullMarker))
+			continue
+		}
+
+		switch baseType {
+		case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+			skipped = append(skipped, col)
+			continue
+		case "bit":
+			if match := reBit.FindStringSubmatch(lowerType); match != nil && bitType(match[1]) != "BOOLEAN" {
+				// bit(n) 转换为 BIT VARYING(n)，比较补齐前导0的二进制文本
+				myExpr = fmt.Sprintf("LPAD(BIN(%s + 0), %s, '0')", my, match[1])
+				pgExprPart = fmt.Sprintf("%s::TEXT", pg)
+			} else {
+				// bit(1) 转换为 BOOLEAN
+				myExpr = fmt.Sprintf("CAST(%s + 0 AS CHAR)", my)
+				pgExprPart = fmt.Sprintf("CASE WHEN %s IS NULL THEN NULL WHEN %s THEN '1' ELSE '0' END", pg, pg)
+			}
+		case "tinyint":
+			if strings.HasPrefix(lowerType, "tinyint(1)") {
+				// tinyint(1) 转换为 BOOLEAN
+				myExpr = fmt.Sprintf("CASE WHEN %s IS NULL THEN NULL WHEN %s <> 0 THEN '1' ELSE '0' END", my, my)
+				pgExprPart = fmt.Sprintf("CASE WHEN %s IS NULL THEN NULL WHEN %s THEN '1' ELSE '0' END", pg, pg)
+			} else {
+				myExpr = fmt.Sprintf("CAST(%s AS CHAR)", my)
+				pgExprPart = fmt.Sprintf("%s::TEXT", pg)
+			}
+		case "smallint", "mediumint", "int", "integer", "bigint", "decimal", "numeric":
+			myExpr = fmt.Sprintf("CAST(%s AS CHAR)", my)
+			pgExprPart = fmt.Sprintf("%s::TEXT", pg)
+		case "year":
+			myExpr = fmt.Sprintf("CAST(%s + 0 AS CHAR)", my)
+			pgExprPart = fmt.Sprintf("%s::TEXT", pg)
+		case "float", "double", "real":
+			// 浮点数统一保留6位小数比较
+			myExpr = fmt.Sprintf("CAST(CAST(%s AS DECIMAL(65,6)) AS CHAR)", my)
+			pgExprPart = fmt.Sprintf("CAST(%s AS NUMERIC(65,6))::TEXT", pg)
+		case "datetime", "timestamp":
+			// 零值日期同步后为NULL
+			myExpr = fmt.Sprintf("NULLIF(DATE_FORMAT(%s, '%%Y-%%m-%%d %%H:%%i:%%s.%%f'), '0000-00-00 00:00:00.000000')", my)
+			pgExprPart = fmt.Sprintf("TO_CHAR(%s, 'YYYY-MM-DD HH24:MI:SS.US')", pg)
+		case "date":
+			myExpr = fmt.Sprintf("NULLIF(DATE_FORMAT(%s, '%%Y-%%m-%%d'), '0000-00-00')", my)
+			pgExprPart = fmt.Sprintf("TO_CHAR(%s, 'YYYY-MM-DD')", pg)
+		case "time":
+			myExpr = fmt.Sprintf("TIME_FORMAT(%s, '%%H:%%i:%%s.%%f')", my)
+			pgExprPart = fmt.Sprintf("TO_CHAR(%s, 'HH24:MI:SS.US')", pg)
+		case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+			myExpr = fmt.Sprintf("LOWER(HEX(%s))", my)
+			pgExprPart = fmt.Sprintf("ENCODE(%s, 'hex')", pg)
+		case "json":
+			myExpr = fmt.Sprintf("CAST(%s AS CHAR)", my)
+			pgExprPart = fmt.Sprintf("%s::TEXT", pg)
+		default:
+			// 字符类型统一转换为utf8mb4，与PostgreSQL的UTF8编码保持一致
+			myExpr = fmt.Sprintf("CONVERT(%s USING utf8mb4)", my)
+			pgExprPart = fmt.Sprintf("%s::TEXT", pg)
+		}
+
+		mysqlParts = append(mysqlParts, fmt.Sprintf("COALESCE(%s, '%s')", myExpr, checksumNullMarker))
+		pgParts = append(pgParts, fmt.Sprintf("COALESCE(%s, '%s')", pgExprPart, checksumNullMarker))
+	}
+
+	if len(mysqlParts) == 0 {
+		return "", "", skipped
+	}
+
+	return fmt.Sprintf("CONCAT_WS('|', %s)", strings.Join(mysqlParts, ", ")),
+		fmt.Sprintf("CONCAT_WS('|', %s)", strings.Join(pgParts, ", ")),
+		skipped
+}
+
+// checksumValidator 单表校验和比较
+type checksumValidator struct {
+	mysqlConn    *mysql.Connection
+	postgresConn *postgres.Connection
+	tableName    string
+	pgTableName  string
+	mysqlFilter  string
+	primaryKey   string
+	pgPrimaryKey string
+	unsigned     bool // 主键为无符号整数
+	mysqlExpr    string
+	pgExpr       string
+	result       *ChecksumResult
+}
+
+// ValidateTableChecksum 按主键分块比较MySQL和PostgreSQL表内容的校验和
+// 有单列整数主键时按 chunkSize 行分块，不一致的分块继续二分下钻到不超过 checksumLeafRows 行，
+// 并逐行比较以给出不一致行示例；否则只进行整表比较
+// filter 为MySQL端的数据过滤条件，PostgreSQL端只包含过滤后的数据
+func ValidateTableChecksum(ctx context.Context, mysqlConn *mysql.Connection, postgresConn *postgres.Connection, tableName, pgTableName string, columns []string, columnTypes map[string]string, uuidColumns, jsonbColumns map[string]bool, filter string, chunkSize int) (*ChecksumResult, error) {
+	if chunkSize <= 0 {
+		chunkSize = 100000 // 默认值
+	}
+
+	mysqlExpr, pgExpr, skipped := checksumExpressions(columns, columnTypes, uuidColumns, jsonbColumns)
+	result := &ChecksumResult{SkippedColumns: skipped}
+	if mysqlExpr == "" {
+		return result, nil
+	}
+
+	v := &checksumValidator{
+		mysqlConn:    mysqlConn,
+		postgresConn: postgresConn,
+		tableName:    tableName,
+		pgTableName:  pgTableName,
+		mysqlFilter:  filter,
+		mysqlExpr:    mysqlExpr,
+		pgExpr:       pgExpr,
+		result:       result,
+	}
+
+	primaryKeys, err := mysqlConn.GetTablePrimaryKeys(ctx, tableName)
+	if reason := checksumWholeTableReason(primaryKeys, err, columns, columnTypes); reason != "" {
+		// 无可分块的单列整数主键，整表比较
+		result.WholeTable = true
+		result.WholeTableReason = reason
+		result.ChunkCount = 1
+		mysqlCount, mysqlSum, err := mysqlConn.GetChecksum(ctx, tableName, mysqlExpr, filter)
+		if err != nil {
+			return nil, err
+		}
+		pgCount, pgSum, err := postgresConn.GetChecksum(ctx, pgTableName, pgExpr, "")
+		if err != nil {
+			return nil, err
+		}
+		result.Mismatched = mysqlCount != pgCount || mysqlSum != pgSum
+		return result, nil
+	}
+
+	v.primaryKey = primaryKeys[0]
+	v.pgPrimaryKey = strings.ToLower(primaryKeys[0])
+	v.unsigned = checksumUnsignedKeyPattern.MatchString(columnTypes[v.primaryKey])
+	result.PrimaryKey = v.primaryKey
+
+	// 两端主键的整体范围，PostgreSQL中多出的行也需要覆盖
+	mysqlMin, mysqlMax, err := mysqlConn.GetColumnMinMax(ctx, tableName, v.primaryKey, filter)
+	if err != nil {
+		return nil, err
+	}
+	pgMin, pgMax, err := postgresConn.GetColumnMinMax(ctx, pgTableName, v.pgPrimaryKey)
+	if err != nil {
+		return nil, err
+	}
+	if !mysqlMin.Valid && !pgMin.Valid {
+		return result, nil
+	}
+
+	low, high := ChecksumKey{ordinal: math.MaxUint64, unsigned: v.unsigned}, ChecksumKey{unsigned: v.unsigned}
+	for _, bound := range []struct {
+		min, max string
+		valid    bool
+	}{{mysqlMin.String, mysqlMax.String, mysqlMin.Valid}, {pgMin.String, pgMax.String, pgMin.Valid}} {
+		if !bound.valid {
+			continue
+		}
+		minKey, err := parseChecksumKey(bound.min, v.unsigned)
+		if err != nil {
+			return nil, err
+		}
+		maxKey, err := parseChecksumKey(bound.max, v.unsigned)
+		if err != nil {
+			return nil, err
+		}
+		if minKey.ordinal < low.ordinal {
+			low = minKey
+		}
+		if maxKey.ordinal > high.ordinal {
+			high = maxKey
+		}
+	}
+
+	// 按MySQL端主键值每 chunkSize 行划分一个分块
+	start := low
+	for {
+		boundary, ok, err := mysqlConn.GetChunkBoundary(ctx, tableName, v.primaryKey, start.Arg(), chunkSize, filter)
+		if err != nil {
+			return nil, err
+		}
+		end := high
+		if ok {
+			if end, err = parseChecksumKey(boundary, v.unsigned); err != nil {
+				return nil, err
+			}
+			if end.ordinal > high.ordinal {
+				end = high
+			}
+		}
+
+		result.ChunkCount++
+		if err := v.compareRange(ctx, start, end); err != nil {
+			return nil, err
+		}
+
+		if end.ordinal >= high.ordinal {
+			break
+		}
+		start = ChecksumKey{ordinal: end.ordinal + 1, unsigned: v.unsigned}
+	}
+
+	result.MismatchedRanges = mergeChecksumRanges(result.MismatchedRanges)
+	result.Mismatched = len(result.MismatchedRanges) > 0
+	return result, nil
+}
+
+// checksumWholeTableReason 返回不能按主键分块、只能整表比较的原因，可以分块时返回空字符串
+// columns 为参与校验的列，不包含脱敏列；主键列被脱敏时PostgreSQL中的主键值与MySQL不同，按MySQL主键划分的
+// 范围在PostgreSQL中对应的是无关的行，修复时会删除错误的行，因此只能整表比较
+func checksumWholeTableReason(primaryKeys []string, pkErr error, columns []string, columnTypes map[string]string) string {
+	switch {
+	case pkErr != nil || len(primaryKeys) == 0:
+		return "没有主键"
+	case len(primaryKeys) > 1:
+		return "复合主键"
+	case !containsColumn(columns, primaryKeys[0]):
+		return fmt.Sprintf("主键列 %s 已脱敏", primaryKeys[0])
+	case !chunkableKeyType(columnTypes[primaryKeys[0]]):
+		return fmt.Sprintf("主键列 %s 不是整数类型", primaryKeys[0])
+	}
+	return ""
+}
+
+// containsColumn 列名列表中是否包含指定列（不区分大小写）
+func containsColumn(columns []string, column string) bool {
+	for _, col := range columns {
+		if strings.EqualFold(col, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// chunkableKeyType 主键类型能否按范围分块：有符号或无符号整数类型
+func chunkableKeyType(columnType string) bool {
+	return checksumIntegerKeyPattern.MatchString(strings.TrimSpace(columnType))
+}
+
+// rangeChecksums 计算主键范围内两端的行数和校验和
+func (v *checksumValidator) rangeChecksums(ctx context.Context, start, end ChecksumKey) (mysqlCount, pgCount int64, equal bool, err error) {
+	mysqlFilter := combineFilters(v.mysqlFilter, fmt.Sprintf("`%s` BETWEEN ? AND ?", v.primaryKey))
+	mysqlCount, mysqlSum, err := v.mysqlConn.GetChecksum(ctx, v.tableName, v.mysqlExpr, mysqlFilter, start.Arg(), end.Arg())
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	pgFilter := fmt.Sprintf(`"%s" BETWEEN $1 AND $2`, v.pgPrimaryKey)
+	lower, upper := postgresKeyBounds(start, end)
+	pgCount, pgSum, err := v.postgresConn.GetChecksum(ctx, v.pgTableName, v.pgExpr, pgFilter, lower, upper)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	return mysqlCount, pgCount, mysqlCount == pgCount && mysqlSum == pgSum, nil
+}
+
+// compareRange 比较主键范围，不一致时二分下钻
+func (v *checksumValidator) compareRange(ctx context.Context, start, end ChecksumKey) error {
+	mysqlCount, pgCount, equal, err := v.rangeChecksums(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	if equal {
+		return nil
+	}
+
+	// 范围足够小，或一端完全没有数据时不再拆分
+	if start == end || (mysqlCount <= checksumLeafRows && pgCount <= checksumLeafRows) || mysqlCount == 0 || pgCount == 0 {
+		v.result.MismatchedRanges = append(v.result.MismatchedRanges, ChecksumRange{
+			Start:        start,
+			End:          end,
+			MySQLRows:    mysqlCount,
+			PostgresRows: pgCount,
+		})
+		if len(v.result.SampleRows) < checksumMaxSampleRows {
+			return v.compareRows(ctx, start, end, mysqlCount, pgCount)
+		}
+		return nil
+	}
+
+	// 避免 start+end 溢出
+	mid := start.ordinal + (end.ordinal-start.ordinal)/2
+	if err := v.compareRange(ctx, start, ChecksumKey{ordinal: mid, unsigned: v.unsigned}); err != nil {
+		return err
+	}
+	return v.compareRange(ctx, ChecksumKey{ordinal: mid + 1, unsigned: v.unsigned}, end)
+}
+
+// compareRows 逐行比较主键范围内的数据，记录不一致行示例
+func (v *checksumValidator) compareRows(ctx context.Context, start, end ChecksumKey, mysqlCount, pgCount int64) error {
+	limit := 0
+	if mysqlCount > checksumLeafRows || pgCount > checksumLeafRows {
+		// 一端没有数据的大范围只取少量行作为示例
+		limit = checksumMaxSampleRows
+	}
+
+	mysqlFilter := combineFilters(v.mysqlFilter, fmt.Sprintf("`%s` BETWEEN ? AND ?", v.primaryKey))
+	mysqlHashes, err := v.mysqlConn.GetRowHashes(ctx, v.tableName, v.primaryKey, v.mysqlExpr, limit, mysqlFilter, start.Arg(), end.Arg())
+	if err != nil {
+		return err
+	}
+	pgFilter := fmt.Sprintf(`"%s" BETWEEN $1 AND $2`, v.pgPrimaryKey)
+	lower, upper := postgresKeyBounds(start, end)
+	pgHashes, err := v.postgresConn.GetRowHashes(ctx, v.pgTableName, v.pgPrimaryKey, v.pgExpr, limit, pgFilter, lower, upper)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []RowMismatch
+	for key, hash := range mysqlHashes {
+		pgHash, ok := pgHashes[key]
+		if !ok {
+			mismatches = append(mismatches, RowMismatch{PrimaryKey: key, Reason: "PostgreSQL中缺失"})
+		} else if pgHash != hash {
+			mismatches = append(mismatches, RowMismatch{PrimaryKey: key, Reason: "内容不一致"})
+		}
+	}
+	for key := range pgHashes {
+		if _, ok := mysqlHashes[key]; !ok {
+			mismatches = append(mismatches, RowMismatch{PrimaryKey: key, Reason: "PostgreSQL中多余"})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		a, _ := parseChecksumKey(mismatches[i].PrimaryKey, v.unsigned)
+		b, _ := parseChecksumKey(mismatches[j].PrimaryKey, v.unsigned)
+		return a.ordinal < b.ordinal
+	})
+
+	for _, mismatch := range mismatches {
+		if len(v.result.SampleRows) >= checksumMaxSampleRows {
+			break
+		}
+		v.result.SampleRows = append(v.result.SampleRows, mismatch)
+	}
+
+	return nil
+}
+
+// mergeChecksumRanges 合并相邻的不一致主键范围
+func mergeChecksumRanges(ranges []ChecksumRange) []ChecksumRange {
+	var merged []ChecksumRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && merged[n-1].End.ordinal != math.MaxUint64 && merged[n-1].End.ordinal+1 == r.Start.ordinal {
+			merged[n-1].End = r.End
+			merged[n-1].MySQLRows += r.MySQLRows
+			merged[n-1].PostgresRows += r.PostgresRows
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestChecksumWholeTableReason(t *testing.T) {
+	columnTypes := map[string]string{"id": "bigint", "uid": "bigint(20) unsigned", "code": "varchar(32)", "name": "varchar(64)"}
+	tests := []struct {
+		name        string
+		primaryKeys []string
+		pkErr       error
+		columns     []string
+		whole       bool
+	}{
+		{name: "单列整数主键", primaryKeys: []string{"id"}, columns: []string{"id", "name"}},
+		{name: "BIGINT UNSIGNED主键", primaryKeys: []string{"uid"}, columns: []string{"uid", "name"}},
+		{name: "没有主键", pkErr: errors.New("表 t 没有主键"), columns: []string{"name"}, whole: true},
+		{name: "复合主键", primaryKeys: []string{"id", "code"}, columns: []string{"id", "code", "name"}, whole: true},
+		{name: "字符串主键", primaryKeys: []string{"code"}, columns: []string{"code", "name"}, whole: true},
+		{name: "主键列已脱敏", primaryKeys: []string{"id"}, columns: []string{"name"}, whole: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := checksumWholeTableReason(tt.primaryKeys, tt.pkErr, tt.columns, columnTypes)
+			if (reason != "") != tt.whole {
+				t.Fatalf("checksumWholeTableReason() = %q，期望整表比较: %v", reason, tt.whole)
+			}
+		})
+	}
+}
+
+func TestChecksumKeyOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		unsigned bool
+		values   []string // 按主键顺序排列
+	}{
+		{name: "有符号主键", values: []string{"-9223372036854775808", "-1", "0", "1", "9223372036854775807"}},
+		{name: "BIGINT UNSIGNED主键", unsigned: true, values: []string{"0", "9223372036854775807", "9223372036854775808", "18446744073709551615"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prev ChecksumKey
+			for i, text := range tt.values {
+				key, err := parseChecksumKey(text, tt.unsigned)
+				if err != nil {
+					t.Fatalf("parseChecksumKey(%q) 错误 = %v", text, err)
+				}
+				if key.String() != text {
+					t.Fatalf("ChecksumKey.String() = %q，期望 %q", key.String(), text)
+				}
+				if i > 0 && key.ordinal <= prev.ordinal {
+					t.Fatalf("主键 %s 应排在 %s 之后", text, prev)
+				}
+				prev = key
+			}
+		})
+	}
+
+	big, _ := parseChecksumKey("18446744073709551615", true)
+	if arg, ok := big.Arg().(uint64); !ok || arg != math.MaxUint64 {
+		t.Fatalf("ChecksumKey.Arg() = %v，期望 uint64 %d", big.Arg(), uint64(math.MaxUint64))
+	}
+	negative, _ := parseChecksumKey("-5", false)
+	if arg, ok := negative.Arg().(int64); !ok || arg != -5 {
+		t.Fatalf("ChecksumKey.Arg() = %v，期望 int64 -5", negative.Arg())
+	}
+	if _, err := parseChecksumKey("18446744073709551615", false); err == nil {
+		t.Fatal("有符号主键超出int64范围时应返回错误")
+	}
+}
+
+func TestMergeChecksumRangesUnsigned(t *testing.T) {
+	key := func(text string) ChecksumKey {
+		k, err := parseChecksumKey(text, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	ranges := []ChecksumRange{
+		{Start: key("9223372036854775800"), End: key("9223372036854775807"), MySQLRows: 1},
+		{Start: key("9223372036854775808"), End: key("18446744073709551615"), MySQLRows: 2},
+	}
+
+	merged := mergeChecksumRanges(ranges)
+	if len(merged) != 1 {
+		t.Fatalf("mergeChecksumRanges() = %v，期望合并为一个范围", merged)
+	}
+	if got := merged[0].String(); got != "[9223372036854775800, 18446744073709551615] (MySQL 3 行, PostgreSQL 0 行)" {
+		t.Fatalf("合并后的范围 = %s", got)
+	}
+}
+
+func TestPostgresKeyBounds(t *testing.T) {
+	tests := []struct {
+		start, end   string
+		unsigned     bool
+		lower, upper string
+	}{
+		{start: "-10", end: "10", lower: "-10", upper: "10"},
+		{start: "1", end: "100", unsigned: true, lower: "1", upper: "100"},
+		{start: "9223372036854775800", end: "18446744073709551615", unsigned: true, lower: "9223372036854775800", upper: "9223372036854775807"},
+		{start: "9223372036854775808", end: "18446744073709551615", unsigned: true, lower: "1", upper: "0"},
+	}
+
+	for _, tt := range tests {
+		start, _ := parseChecksumKey(tt.start, tt.unsigned)
+		end, _ := parseChecksumKey(tt.end, tt.unsigned)
+		lower, upper := postgresKeyBounds(start, end)
+		if lower != tt.lower || upper != tt.upper {
+			t.Fatalf("postgresKeyBounds(%s, %s) = [%s, %s]，期望 [%s, %s]", tt.start, tt.end, lower, upper, tt.lower, tt.upper)
+		}
+	}
+}
@@ -2,14 +2,15 @@ package postgres
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/yourusername/mysql2pg/internal/config"
 	"github.com/yourusername/mysql2pg/internal/mysql"
 	"github.com/yourusername/mysql2pg/internal/postgres"
+	"github.com/yourusername/mysql2pg/internal/throttle"
 )
 
 // TableDataInconsistency 表数据不一致信息
@@ -17,141 +18,410 @@ type TableDataInconsistency struct {
 	TableName        string
 	MySQLRowCount    int64
 	PostgresRowCount int64
+	Checksum         *ChecksumResult // 校验和比较结果，validate_mode 为 checksum 时有效
+}
+
+// DataSyncRun 一次数据同步中所有表共享的日志、进度和统计收集器
+// 收集器为nil时表示未启用对应功能
+type DataSyncRun struct {
+	Log            func(format string, args ...interface{})
+	LogError       func(errMsg string)
+	UpdateProgress func()
+
+	Mutex          *sync.Mutex // 保护 CompletedTasks、InconsistentTables 和控制台输出
+	CompletedTasks *int
+	TotalTasks     int
+
+	InconsistentTables *[]TableDataInconsistency
+	IncrementalState   *IncrementalState
+	MaskingAudit       *MaskingAudit
+	DataFixes          *DataFixAudit
+	Limiter            *throttle.Limiter
+	Rejects            *RejectLog
+	Report             *RunReport
+	Progress           *DataProgress
+}
+
+// sendError 将协程中的错误写入容量为1的错误通道，通道中已有错误时丢弃
+func sendError(errorChan chan<- error, err error) {
+	select {
+	case errorChan <- err:
+	default:
+	}
 }
 
 // SyncTableData 同步表数据
-func SyncTableData(mysqlConn *mysql.Connection, postgresConn *postgres.Connection, config *config.Config, log func(format string, args ...interface{}), logError func(errMsg string), updateProgress func(), mutex *sync.Mutex, completedTasks *int, totalTasks int, inconsistentTables *[]TableDataInconsistency, tables []mysql.TableInfo, semaphore chan struct{}) error {
+func SyncTableData(ctx context.Context, mysqlConn *mysql.Connection, postgresConn *postgres.Connection, config *config.Config, run *DataSyncRun, tables []mysql.TableInfo, semaphore chan struct{}) error {
+	log, logError, updateProgress := run.Log, run.LogError, run.UpdateProgress
+	mutex, completedTasks, totalTasks := run.Mutex, run.CompletedTasks, run.TotalTasks
+	inconsistentTables, incrementalState := run.InconsistentTables, run.IncrementalState
+	maskingAudit, dataFixes, limiter := run.MaskingAudit, run.DataFixes, run.Limiter
+	rejects, report, progress := run.Rejects, run.Report, run.Progress
+
+	// 启用坏行隔离时先准备隔离行的保存位置
+	if err := rejects.Prepare(ctx, postgresConn); err != nil {
+		return err
+	}
+
 	var wg sync.WaitGroup
-	// 创建错误通道来捕获goroutine中的错误
-	errorChan := make(chan error, len(tables))
+	// 创建错误通道来捕获goroutine中的错误，每个表最多一个
+	tableErrors := make(chan error, len(tables))
 
 	for _, table := range tables {
 		semaphore <- struct{}{}
+		// 运行被取消后不再开始同步新的表
+		if ctx.Err() != nil {
+			<-semaphore
+			break
+		}
 		wg.Add(1)
 
 		go func(table mysql.TableInfo) {
+			// 表的同步错误先写入本表的通道，结束时记录到运行报告后再转发到 tableErrors
+			errorChan := make(chan error, 1)
+			started := time.Now()
+			// 已提交的行数和校验结果，用于运行报告
+			var processedRows int64
+			var validation, skipped string
 			defer func() {
+				pgTableName := targetTableName(config, table.Name)
+				select {
+				case err := <-errorChan:
+					report.RecordTable(table.Name, pgTableName, ReportFailed, processedRows, time.Since(started), validation, err)
+					tableErrors <- err
+				default:
+					if skipped != "" {
+						report.SkipTable(table.Name, pgTableName, skipped)
+					} else {
+						report.RecordTable(table.Name, pgTableName, ReportSynced, processedRows, time.Since(started), validation, nil)
+					}
+				}
+				progress.Finish(table.Name)
 				<-semaphore
 				updateProgress()
 				wg.Done()
 			}()
+			// interrupted 运行被取消时未提交的批次已回滚，记录已提交的行数，不作为错误记录到错误日志
+			interrupted := func() bool {
+				if ctx.Err() == nil {
+					return false
+				}
+				log("同步表 %s 已中断，未提交的批次已回滚，已提交 %d 行", table.Name, processedRows)
+				sendError(errorChan, fmt.Errorf("同步表 %s 已中断: %w", table.Name, ctx.Err()))
+				return true
+			}
+			// fail 记录错误日志并报告表同步失败
+			fail := func(errMsg string, err error) {
+				logError(errMsg)
+				sendError(errorChan, fmt.Errorf("同步表 %s 失败: %w", table.Name, err))
+			}
 
 			// 获取表列信息
-			columns, columnTypes, err := mysqlConn.GetTableColumnsWithTypes(table.Name)
+			columns, columnTypes, err := mysqlConn.GetTableColumnsWithTypes(ctx, table.Name)
 			if err != nil {
-				errMsg := fmt.Sprintf("获取表 %s 列信息失败: %v", table.Name, err)
-				logError(errMsg)
-				select {
-				case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-				default:
-				}
+				fail(fmt.Sprintf("获取表 %s 列信息失败: %v", table.Name, err), err)
 				return
 			}
 
-			// 获取表数据总行数
-			totalRows, err := mysqlConn.GetTableRowCount(table.Name)
+			// 按单表同步配置裁剪列
+			tableConfig := config.Conversion.FindTable(table.Name)
+			columns = projectColumns(tableConfig, columns)
+			if len(columns) == 0 {
+				err := fmt.Errorf("表 %s 没有需要同步的列", table.Name)
+				fail(err.Error(), err)
+				return
+			}
+			if primaryKeys, err := mysqlConn.GetTablePrimaryKeys(ctx, table.Name); err == nil {
+				if err := checkProjectedPrimaryKey(tableConfig, table.Name, primaryKeys); err != nil {
+					fail(err.Error(), err)
+					return
+				}
+			}
+			// 单表配置的数据过滤条件，同时用于读取数据和行数校验
+			where := tableConfig.Filter()
+			// 列数据脱敏处理器，没有需要脱敏的列时为nil
+			masker, err := postgres.NewColumnMasker(&config.Conversion.Masking, table.Name, columns, columnTypes)
 			if err != nil {
-				errMsg := fmt.Sprintf("获取表 %s 行数失败: %v", table.Name, err)
-				logError(errMsg)
-				select {
-				case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
+				fail(fmt.Sprintf("表 %s 的脱敏规则无效: %v", table.Name, err), err)
+				return
+			}
+			// 转换为 uuid 的 BINARY(16) 列
+			uuids := uuidColumns(config, table.Name, columnTypes)
+			// 转换为 jsonb 的列
+			jsonbs := jsonbColumns(config, table.Name, columnTypes)
+
+			// 增量同步模式：确定水位列及本次同步的水位范围 (上次水位, 当前最大值]
+			incremental := config.Conversion.Options.IsIncremental()
+			var filter string
+			var filterArgs []interface{}
+			var watermarkColumn, watermarkUpper string
+			var watermarkValid bool
+			if incremental {
+				configuredColumn := config.Conversion.Incremental.WatermarkColumn(table.Name)
+				if configuredColumn == "" {
+					log("表 %s 未配置水位列，跳过增量同步", table.Name)
+					skipped = "未配置水位列，跳过增量同步"
+					if config.Run.ShowConsoleLogs {
+						mutex.Lock()
+						overallProgress := progress.Percent(*completedTasks, totalTasks)
+						currentTask := *completedTasks + 1
+						fmt.Printf("\n进度: %.2f%% (%d/%d) : 表 %s 未配置水位列，跳过增量同步\n", overallProgress, currentTask, totalTasks, table.Name)
+						mutex.Unlock()
+					}
+					return
+				}
+
+				// 增量同步按主键合并，没有主键的表无法去重，在读取数据前失败
+				if _, err := mysqlConn.GetTablePrimaryKeys(ctx, table.Name); err != nil {
+					fail(fmt.Sprintf("表 %s 增量同步失败，增量同步要求表有主键: %v", table.Name, err), err)
+					return
+				}
+
+				var found bool
+				watermarkColumn, found = resolveColumnName(columns, configuredColumn)
+				if !found {
+					err := fmt.Errorf("水位列 %s 不存在", configuredColumn)
+					fail(fmt.Sprintf("表 %s 增量同步失败: %v", table.Name, err), err)
+					return
+				}
+
+				watermarkUpper, watermarkValid, err = mysqlConn.GetColumnMaxValue(ctx, table.Name, watermarkColumn)
+				if err != nil {
+					fail(fmt.Sprintf("获取表 %s 水位上界失败: %v", table.Name, err), err)
+					return
+				}
+
+				var previous *TableWatermark
+				if watermark, ok := incrementalState.Get(table.Name); ok {
+					if strings.EqualFold(watermark.Column, watermarkColumn) {
+						previous = &watermark
+					} else {
+						log("表 %s 的水位列由 %s 变更为 %s，将重新同步全部数据", table.Name, watermark.Column, watermarkColumn)
+					}
+				}
+				filter, filterArgs = buildWatermarkFilter(watermarkColumn, columnTypes[watermarkColumn], previous, watermarkUpper)
+				filter = combineFilters(where, filter)
+				if previous != nil {
+					log("表 %s 增量同步，水位列 %s，范围 [%s, %s]", table.Name, watermarkColumn, previous.Value, watermarkUpper)
+				} else {
+					log("表 %s 首次增量同步，水位列 %s，同步至 %s", table.Name, watermarkColumn, watermarkUpper)
+				}
+			}
+
+			if !incremental {
+				filter = where
+			}
+
+			// saveWatermark 表同步成功后记录本次水位上界
+			saveWatermark := func() bool {
+				if !incremental || !watermarkValid {
+					return true
+				}
+				if err := incrementalState.Update(table.Name, TableWatermark{Column: watermarkColumn, Value: watermarkUpper}); err != nil {
+					fail(fmt.Sprintf("保存表 %s 的增量同步水位失败: %v", table.Name, err), err)
+					return false
+				}
+				return true
+			}
+
+			// recordValidation 比较行数（及行内容校验和），不一致时记录到不一致表统计中
+			recordValidation := func(pgTableName string, mysqlRowCount, pgRowCount int64) (string, bool) {
+				consistent := pgRowCount == mysqlRowCount
+				var checksum *ChecksumResult
+				if config.Conversion.Options.IsChecksumValidation() {
+					var err error
+					checksum, err = ValidateTableChecksum(ctx, mysqlConn, postgresConn, table.Name, pgTableName, unmaskedColumns(config, table.Name, columns), columnTypes, uuids, jsonbs, where, config.Conversion.Limits.ChecksumChunkSize)
+					if err != nil {
+						fail(fmt.Sprintf("校验表 %s 数据校验和失败: %v", table.Name, err), err)
+						return "", false
+					}
+					if len(checksum.SkippedColumns) > 0 {
+						log("表 %s 的列 %s 不参与校验和比较", table.Name, strings.Join(checksum.SkippedColumns, ", "))
+					}
+					consistent = consistent && !checksum.Mismatched
+				}
+
+				if consistent {
+					return "数据一致", true
+				}
+
+				mutex.Lock()
+				*inconsistentTables = append(*inconsistentTables, TableDataInconsistency{
+					TableName:        table.Name,
+					MySQLRowCount:    mysqlRowCount,
+					PostgresRowCount: pgRowCount,
+					Checksum:         checksum,
+				})
+				mutex.Unlock()
+				return "数据不一致", true
+			}
+
+			// 修复模式：不重新同步整表，只修复内容校验和不一致的主键范围
+			if config.Conversion.Options.IsRepair() {
+				pgTableName := targetTableName(config, table.Name)
+
+				repair, err := RepairTableData(ctx, mysqlConn, postgresConn, config, log, limiter, table.Name, pgTableName, columns, columnTypes, where)
+				if err != nil {
+					logError(fmt.Sprintf("修复表 %s 数据失败: %v", table.Name, err))
+					sendError(errorChan, fmt.Errorf("修复表 %s 失败: %w", table.Name, err))
+					return
+				}
+
+				var repairResult string
+				switch {
+				case !repair.Before.Mismatched:
+					repairResult = "数据一致，无需修复"
+				case repair.After.Mismatched:
+					repairResult = fmt.Sprintf("修复 %d 个范围（%d 行）后仍不一致", repair.RepairedRanges, repair.CopiedRows)
 				default:
+					repairResult = fmt.Sprintf("修复 %d 个范围（%d 行）后数据一致", repair.RepairedRanges, repair.CopiedRows)
+				}
+
+				if repair.After.Mismatched {
+					mysqlRowCount, err := mysqlConn.GetTableRowCountWithFilter(ctx, table.Name, where)
+					if err == nil {
+						var pgRowCount int64
+						pgRowCount, err = postgresConn.GetTableRowCount(ctx, pgTableName)
+						if err == nil {
+							mutex.Lock()
+							*inconsistentTables = append(*inconsistentTables, TableDataInconsistency{
+								TableName:        table.Name,
+								MySQLRowCount:    mysqlRowCount,
+								PostgresRowCount: pgRowCount,
+								Checksum:         repair.After,
+							})
+							mutex.Unlock()
+						}
+					}
+					if err != nil {
+						logError(fmt.Sprintf("获取表 %s 行数失败: %v", table.Name, err))
+					}
 				}
+
+				if config.Run.ShowConsoleLogs {
+					mutex.Lock()
+					overallProgress := progress.Percent(*completedTasks, totalTasks)
+					currentTask := *completedTasks + 1
+					fmt.Printf("进度: %.2f%% (%d/%d) : 修复表 %s 完成，%s\n", overallProgress, currentTask, totalTasks, table.Name, repairResult)
+					mutex.Unlock()
+				}
+				log("修复表 %s 完成，%s", table.Name, repairResult)
+				processedRows, validation = repair.CopiedRows, repairResult
+				return
+			}
+
+			// 获取表数据总行数（增量模式下为本次需要同步的行数）
+			var totalRows int64
+			if config.Conversion.Options.EstimateRowCounts && filter == "" && table.Size.Rows > 0 {
+				// 使用统计信息中的估计行数，仅用于显示进度
+				totalRows = table.Size.Rows
+			} else if !incremental || watermarkValid {
+				totalRows, err = mysqlConn.GetTableRowCountWithFilter(ctx, table.Name, filter, filterArgs...)
+			}
+			if err != nil {
+				fail(fmt.Sprintf("获取表 %s 行数失败: %v", table.Name, err), err)
 				return
 			}
 
 			// 如果表为空，仍然显示同步信息并更新进度
 			if totalRows == 0 {
 
-				log("表 %s 没有数据，跳过同步", table.Name)
+				if incremental {
+					log("表 %s 没有新增或变更的数据，跳过同步", table.Name)
+				} else {
+					log("表 %s 没有数据，跳过同步", table.Name)
+				}
 				// 执行数据校验（如果启用）
 				var validationResult string
 				if config.Conversion.Options.ValidateData {
-					// 查询PostgreSQL表行数时，根据配置决定是否使用小写表名
-					pgTableName := table.Name
-					if config.Conversion.Options.LowercaseColumns {
-						pgTableName = strings.ToLower(pgTableName)
+					// 增量模式下比较的是整表行数
+					mysqlRowCount := totalRows
+					if incremental {
+						mysqlRowCount, err = mysqlConn.GetTableRowCountWithFilter(ctx, table.Name, where)
+						if err != nil {
+							fail(fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err), err)
+							return
+						}
 					}
-					pgRowCount, err := postgresConn.GetTableRowCount(pgTableName)
+
+					// 查询PostgreSQL目标表的行数
+					pgTableName := targetTableName(config, table.Name)
+					pgRowCount, err := postgresConn.GetTableRowCount(ctx, pgTableName)
 					if err != nil {
-						errMsg := fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err)
-						logError(errMsg)
-						select {
-						case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-						default:
-						}
+						fail(fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err), err)
 						return
 					}
 
-					if pgRowCount == totalRows {
-						validationResult = "数据一致"
-					} else {
-						validationResult = "数据不一致"
-						mutex.Lock()
-						*inconsistentTables = append(*inconsistentTables, TableDataInconsistency{
-							TableName:        table.Name,
-							MySQLRowCount:    totalRows,
-							PostgresRowCount: pgRowCount,
-						})
-						mutex.Unlock()
+					var ok bool
+					if validationResult, ok = recordValidation(pgTableName, mysqlRowCount, pgRowCount); !ok {
+						return
 					}
 				} else {
 					validationResult = "跳过验证"
 				}
 
+				if !saveWatermark() {
+					return
+				}
+				maskingAudit.Record(table.Name, masker)
+
 				// 显示同步成功信息
 				if config.Run.ShowConsoleLogs {
 					mutex.Lock()
-					overallProgress := float64(*completedTasks) / float64(totalTasks) * 100
+					overallProgress := progress.Percent(*completedTasks, totalTasks)
 					currentTask := *completedTasks + 1
 					fmt.Printf("\n进度: %.2f%% (%d/%d) : 同步表 %s 数据成功，共有 0 行数据，%s \n", overallProgress, currentTask, totalTasks, table.Name, validationResult)
 					mutex.Unlock()
 				}
 				// 记录同步完成信息
 				log("表 %s 同步完成，0 行数据，%s", table.Name, validationResult)
+				validation = validationResult
 				return
 			}
 
 			// 先清空表数据（根据配置决定是否执行）
-			// 根据配置决定是否将表名转换为小写
-			tableName := table.Name
-			if config.Conversion.Options.LowercaseColumns {
-				tableName = strings.ToLower(tableName)
+			// 获取PostgreSQL中的目标表名
+			tableName := targetTableName(config, table.Name)
+
+			// 使用 GetTablePrimaryKeys 获取所有主键
+			primaryKeys, pkErr := mysqlConn.GetTablePrimaryKeys(ctx, table.Name)
+
+			// upsert方式写入时按主键合并，不清空表数据，同步过程中表保持可读
+			var upsert *postgres.UpsertOptions
+			if config.Conversion.Options.IsUpsertLoad() {
+				if pkErr == nil {
+					upsert = &postgres.UpsertOptions{
+						ConflictColumns: primaryKeys,
+						UseMerge:        postgresConn.SupportsMerge(),
+					}
+				} else if incremental {
+					// 增量同步必须有主键
+					fail(fmt.Sprintf("表 %s 增量同步失败，增量同步要求表有主键: %v", table.Name, pkErr), pkErr)
+					return
+				} else {
+					log("警告: 表 %s 没有主键，无法使用upsert方式写入，将使用COPY方式写入", table.Name)
+				}
 			}
 
-			if config.Conversion.Options.TruncateBeforeSync {
+			// 增量同步只写入水位范围内的数据，任何情况下都不清空表
+			if config.Conversion.Options.TruncateBeforeSync && upsert == nil && !incremental {
 				// 开始事务用于清空表
-				tx, err := postgresConn.BeginTransaction(context.Background())
+				tx, err := postgresConn.BeginTransaction(ctx)
 				if err != nil {
-					errMsg := fmt.Sprintf("开始事务失败: %v", err)
-					logError(errMsg)
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
-					}
+					fail(fmt.Sprintf("开始事务失败: %v", err), err)
 					return
 				}
 
 				truncateQuery := fmt.Sprintf("TRUNCATE TABLE \"%s\"", tableName)
-				if _, err := tx.Exec(context.Background(), truncateQuery); err != nil {
-					errMsg := fmt.Sprintf("清空表 %s 数据失败: %v", table.Name, err)
-					logError(errMsg)
+				if _, err := tx.Exec(ctx, truncateQuery); err != nil {
 					tx.Rollback(context.Background())
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
-					}
+					fail(fmt.Sprintf("清空表 %s 数据失败: %v", table.Name, err), err)
 					return
 				}
 
 				// 提交清空表的事务
-				if err := tx.Commit(context.Background()); err != nil {
-					errMsg := fmt.Sprintf("提交事务失败: %v", err)
-					logError(errMsg)
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
-					}
+				if err := tx.Commit(ctx); err != nil {
+					fail(fmt.Sprintf("提交事务失败: %v", err), err)
 					return
 				}
 			}
@@ -167,35 +437,51 @@ func SyncTableData(mysqlConn *mysql.Connection, postgresConn *postgres.Connectio
 				batchInsertSize = 10000 // 默认值，提高到10000以提高性能
 			}
 
-			// 尝试使用基于主键的分页
-			var lastValue interface{}
+			// 按目标列类型创建行转换器，使COPY以二进制格式写入数值和时间
+			converter, err := postgresConn.NewRowConverter(ctx, tableName, columns, columnTypes, uuids, &config.Conversion.InvalidData)
+			if err != nil {
+				log("警告: %v，表 %s 的数据将按文本格式写入", err, table.Name)
+				converter = postgres.NewRowConverter(columns, columnTypes, nil, uuids, &config.Conversion.InvalidData)
+			}
+
+			// 有单列主键时按主键分页读取，否则使用一次不分页的查询流式读取
 			var primaryKey string
-			var useKeyPagination bool
 			var orderBy string
 
-			// 使用 GetTablePrimaryKeys 获取所有主键
-			primaryKeys, err := mysqlConn.GetTablePrimaryKeys(table.Name)
-			if err != nil {
-				log("警告: %v，将使用传统的OFFSET分页", err)
-				useKeyPagination = false
+			if pkErr != nil {
+				log("警告: %v，将使用不分页的流式查询读取数据", pkErr)
 			} else if len(primaryKeys) == 1 {
 				primaryKey = primaryKeys[0]
 				log("表 %s 的主键是 %s，将使用基于主键的分页", table.Name, primaryKey)
-				useKeyPagination = true
 			} else {
 				// 复合主键
-				useKeyPagination = false
 				// 构建 ORDER BY 子句
 				var quotedKeys []string
 				for _, k := range primaryKeys {
 					quotedKeys = append(quotedKeys, fmt.Sprintf("`%s`", k))
 				}
 				orderBy = strings.Join(quotedKeys, ", ")
-				log("表 %s 有复合主键 %v，将使用传统的OFFSET分页（带ORDER BY）", table.Name, primaryKeys)
+				log("表 %s 有复合主键 %v，将使用不分页的流式查询读取数据（带ORDER BY）", table.Name, primaryKeys)
 			}
 
-			// 同步数据
-			var processedRows int64
+			// 读取端在独立的协程中读取MySQL数据并写入有界通道，写入端同时从通道COPY到PostgreSQL
+			stream := postgres.NewRowStream(batchInsertSize)
+			producer := &tableRowProducer{
+				mysqlConn:  mysqlConn,
+				tableName:  table.Name,
+				columns:    columns,
+				primaryKey: primaryKey,
+				keyColumns: primaryKeys,
+				orderBy:    orderBy,
+				pageSize:   int(batchSize),
+				filter:     filter,
+				filterArgs: filterArgs,
+				converter:  converter,
+				masker:     masker,
+				limiter:    limiter,
+				quarantine: rejects != nil,
+			}
+			go producer.run(ctx, stream)
 
 			// 进度条状态跟踪（减少闪烁）
 			type progressState struct {
@@ -205,71 +491,47 @@ func SyncTableData(mysqlConn *mysql.Connection, postgresConn *postgres.Connectio
 			state := &progressState{}
 
 			for {
-				var rows *sql.Rows
-				var currentBatchSize int
-
-				// 使用现有的分页查询方法
-				if useKeyPagination {
-					// 使用基于主键的分页
-					rows, err = mysqlConn.GetTableDataWithPagination(table.Name, columns, primaryKey, lastValue, int(batchSize))
-				} else {
-					// 使用传统的OFFSET分页
-					rows, err = mysqlConn.GetTableData(table.Name, columns, int(processedRows), int(batchSize), orderBy)
-				}
-
-				if err != nil {
-					errMsg := fmt.Sprintf("分页获取表 %s 数据失败: %v", table.Name, err)
-					logError(errMsg)
-					select {
-					case errorChan <- fmt.Errorf("分页同步表 %s 失败: %w", table.Name, err):
-					default:
-					}
+				// 运行被取消时不再开始新的批次，已提交的批次保留
+				if interrupted() {
+					stream.Stop()
 					return
 				}
 
-				// 为每个批次开始新事务
-				tx, err := postgresConn.BeginTransaction(context.Background())
+				// 每 batchSize 行数据在一个事务中提交
+				tx, err := postgresConn.BeginLoadTransaction(ctx)
 				if err != nil {
-					errMsg := fmt.Sprintf("开始事务失败: %v", err)
-					logError(errMsg)
-					rows.Close()
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
-					}
+					stream.Stop()
+					fail(fmt.Sprintf("开始事务失败: %v", err), err)
 					return
 				}
 
-				// 使用批量插入并获取实际处理的行数
-				currentBatchSize, lastValue, err = postgresConn.BatchInsertDataWithTransactionAndGetLastValue(tx, tableName, columns, columnTypes, batchInsertSize, primaryKey, rows)
-				rows.Close() // 确保关闭rows
-
+				// 从数据流读取并写入，返回实际处理的行数
+				currentBatchSize, eof, err := postgresConn.CopyFromStream(ctx, tx, tableName, columns, batchInsertSize, upsert, stream, int(batchSize), rejects.Handler(ctx, tx, table.Name, columns))
 				if err != nil {
-					errMsg := fmt.Sprintf("插入表 %s 数据失败: %v", table.Name, err)
-					logError(errMsg)
+					// 取消时正在写入的批次被中止，回滚事务
 					tx.Rollback(context.Background())
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
+					stream.Stop()
+					if interrupted() {
+						return
 					}
+					fail(fmt.Sprintf("插入表 %s 数据失败: %v", table.Name, err), err)
 					return
 				}
 
 				// 提交当前批次的事务
-				if err := tx.Commit(context.Background()); err != nil {
-					errMsg := fmt.Sprintf("提交事务失败: %v", err)
-					logError(errMsg)
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
+				if err := tx.Commit(ctx); err != nil {
+					stream.Stop()
+					if interrupted() {
+						return
 					}
+					fail(fmt.Sprintf("提交事务失败: %v", err), err)
 					return
 				}
 
 				// 更新处理的行数
-				if currentBatchSize > 0 {
-					processedRows += int64(currentBatchSize)
-				} else {
+				processedRows += int64(currentBatchSize)
+				progress.Update(table.Name, processedRows)
+				if eof {
 					// 没有更多数据，退出循环
 					log("分页同步表 %s 完成，共处理 %d 行数据", table.Name, processedRows)
 					break
@@ -277,14 +539,14 @@ func SyncTableData(mysqlConn *mysql.Connection, postgresConn *postgres.Connectio
 
 				// 显示同步进度
 				if config.Run.ShowConsoleLogs {
-					progress := float64(processedRows) / float64(totalRows) * 100
-					if progress > 100 {
-						progress = 100
+					tableProgress := float64(processedRows) / float64(totalRows) * 100
+					if tableProgress > 100 {
+						tableProgress = 100
 					}
 
 					// 生成进度条
 					barLength := 20
-					filledLength := int(progress / 100 * float64(barLength))
+					filledLength := int(tableProgress / 100 * float64(barLength))
 					// 确保空格重复次数不会为负数
 					spaceCount := barLength - filledLength - 1
 					if spaceCount < 0 {
@@ -294,17 +556,17 @@ func SyncTableData(mysqlConn *mysql.Connection, postgresConn *postgres.Connectio
 
 					// 使用互斥锁保护日志输出
 					mutex.Lock()
-					overallProgress := float64(*completedTasks) / float64(totalTasks) * 100
+					overallProgress := progress.Percent(*completedTasks, totalTasks)
 					currentTask := *completedTasks + 1
 
 					// 只有当进度条长度或进度百分比变化时才更新（减少闪烁）
 					// 当进度条实际长度变化或进度百分比变化超过0.5%时才更新
-					if state.lastBarLength != filledLength || progress-state.lastProgress >= 0.5 {
+					if state.lastBarLength != filledLength || tableProgress-state.lastProgress >= 0.5 {
 						// 使用ANSI转义序列清除当前行，然后输出新的进度信息
 						// \033[2K 清除整个行，\r 回到行首
-						fmt.Printf("\033[2K\r进度: %.2f%% (%d/%d) : 同步表 %s [%s] %.2f%%", overallProgress, currentTask, totalTasks, table.Name, bar, progress)
+						fmt.Printf("\033[2K\r进度: %.2f%% (%d/%d) : 同步表 %s [%s] %.2f%%%s", overallProgress, currentTask, totalTasks, table.Name, bar, tableProgress, progress.ETA())
 						state.lastBarLength = filledLength
-						state.lastProgress = progress
+						state.lastProgress = tableProgress
 					}
 					mutex.Unlock()
 				}
@@ -316,66 +578,70 @@ func SyncTableData(mysqlConn *mysql.Connection, postgresConn *postgres.Connectio
 
 			if config.Conversion.Options.ValidateData {
 				// 尝试重新获取MySQL表行数以进行更准确的校验
-				currentMySQLCount, err := mysqlConn.GetTableRowCount(table.Name)
+				currentMySQLCount, err := mysqlConn.GetTableRowCountWithFilter(ctx, table.Name, where)
 				if err == nil {
 					finalMySQLRowCount = currentMySQLCount
+				} else if incremental {
+					// 增量模式下需要比较整表行数，不能使用本次增量的行数
+					fail(fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err), err)
+					return
 				} else {
 					log("警告: 无法重新获取表 %s 的行数进行校验: %v，将使用初始行数", table.Name, err)
 				}
 
-				// 查询PostgreSQL表行数时，根据配置决定是否使用小写表名
-				pgTableName := table.Name
-				if config.Conversion.Options.LowercaseColumns {
-					pgTableName = strings.ToLower(pgTableName)
-				}
-				pgRowCount, err := postgresConn.GetTableRowCount(pgTableName)
+				// 查询PostgreSQL目标表的行数
+				pgTableName := targetTableName(config, table.Name)
+				pgRowCount, err := postgresConn.GetTableRowCount(ctx, pgTableName)
 				if err != nil {
-					errMsg := fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err)
-					logError(errMsg)
-					select {
-					case errorChan <- fmt.Errorf("同步表 %s 失败: %w", table.Name, err):
-					default:
-					}
+					fail(fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err), err)
 					return
 				}
 
-				if pgRowCount == finalMySQLRowCount {
-					validationResult = "数据一致"
-				} else {
-					validationResult = "数据不一致"
-					mutex.Lock()
-					*inconsistentTables = append(*inconsistentTables, TableDataInconsistency{
-						TableName:        table.Name,
-						MySQLRowCount:    finalMySQLRowCount,
-						PostgresRowCount: pgRowCount,
-					})
-					mutex.Unlock()
+				var ok bool
+				if validationResult, ok = recordValidation(pgTableName, finalMySQLRowCount, pgRowCount); !ok {
+					return
 				}
 			} else {
 				validationResult = "跳过验证"
 			}
 
+			if !saveWatermark() {
+				return
+			}
+			maskingAudit.Record(table.Name, masker)
+			dataFixes.Record(table.Name, converter)
+
+			// 被隔离的行没有写入PostgreSQL
+			if rejected := rejects.Count(table.Name); rejected > 0 {
+				validationResult += fmt.Sprintf("，%d 行写入失败已隔离到 %s", rejected, rejects.Location())
+			}
+
 			// 显示同步成功信息（根据配置决定是否在控制台显示）
 			if config.Run.ShowConsoleLogs {
 				mutex.Lock()
-				overallProgress := float64(*completedTasks) / float64(totalTasks) * 100
+				overallProgress := progress.Percent(*completedTasks, totalTasks)
 				currentTask := *completedTasks + 1
 				// 先输出一个换行符，确保完成信息显示在新的一行
-				fmt.Printf("\n进度: %.2f%% (%d/%d) : 同步表 %s 完成，%d 行数据，%s\n", overallProgress, currentTask, totalTasks, table.Name, processedRows, validationResult)
+				fmt.Printf("\n进度: %.2f%% (%d/%d) : 同步表 %s 完成，%d 行数据，%s%s\n", overallProgress, currentTask, totalTasks, table.Name, processedRows, validationResult, progress.ETA())
 				mutex.Unlock()
 			}
 
 			// 记录同步完成信息
 			log("\n分页同步表 %s 完成，%d 行数据，%s", table.Name, processedRows, validationResult)
+			validation = validationResult
 		}(table)
 	}
 
 	// 等待所有goroutine完成
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("数据同步已中断: %w", err)
+	}
+
 	// 检查是否有错误发生
 	select {
-	case err := <-errorChan:
+	case err := <-tableErrors:
 		// 返回第一个遇到的错误
 		return err
 	default:
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"sync"
+
+	"github.com/yourusername/mysql2pg/internal/postgres"
+)
+
+// DataFixAuditEntry 单表一类无效数据的处理记录
+type DataFixAuditEntry struct {
+	TableName string
+	postgres.DataFix
+}
+
+// DataFixAudit 本次运行中按无效数据处理策略处理的值
+type DataFixAudit struct {
+	mutex   sync.Mutex
+	Entries []DataFixAuditEntry
+}
+
+// Record 记录表中发现的无效数据
+func (a *DataFixAudit) Record(tableName string, converter *postgres.RowConverter) {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, fix := range converter.DataFixes() {
+		a.Entries = append(a.Entries, DataFixAuditEntry{TableName: tableName, DataFix: fix})
+	}
+}
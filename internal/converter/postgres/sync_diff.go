@@ -0,0 +1,290 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+)
+
+// 表结构差异的类型
+const (
+	DiffMissingTable       = "missing_table"        // PostgreSQL中缺少表
+	DiffExtraTable         = "extra_table"          // PostgreSQL中多出的表
+	DiffMissingColumn      = "missing_column"       // 缺少列
+	DiffExtraColumn        = "extra_column"         // 多出的列
+	DiffTypeMismatch       = "type_mismatch"        // 列类型不同
+	DiffNullability        = "nullability_mismatch" // 是否允许NULL不同
+	DiffDefaultMismatch    = "default_mismatch"     // 默认值不同
+	DiffMissingPrimaryKey  = "missing_primary_key"  // 缺少主键或主键列不同
+	DiffMissingIndex       = "missing_index"        // 缺少索引
+	DiffIndexMismatch      = "index_mismatch"       // 存在相同列的索引，但唯一性不同
+	DiffMissingView        = "missing_view"         // 缺少视图
+	DiffMissingFunction    = "missing_function"     // 缺少函数
+	DiffUnconvertibleTable = "unconvertible_table"  // 无法按转换规则生成期望的表结构
+)
+
+// reNextval 序列默认值，比较时忽略序列名
+var reNextval = regexp.MustCompile(`nextval\('[^']*'::regclass\)`)
+
+// SchemaDifference 一处MySQL与PostgreSQL结构的差异
+type SchemaDifference struct {
+	Kind     string `json:"kind"`
+	Object   string `json:"object"`             // PostgreSQL中的表名、视图名或函数名
+	Column   string `json:"column,omitempty"`   // 列名或索引名
+	Expected string `json:"expected,omitempty"` // 按转换规则期望的定义
+	Actual   string `json:"actual,omitempty"`   // PostgreSQL中的实际定义
+}
+
+// SchemaDiff 结构对比结果
+type SchemaDiff struct {
+	Schema      string             `json:"schema"`
+	Tables      int                `json:"tables"`
+	Indexes     int                `json:"indexes"`
+	Views       int                `json:"views"`
+	Functions   int                `json:"functions"`
+	Differences []SchemaDifference `json:"differences"`
+}
+
+// add 记录一处差异
+func (d *SchemaDiff) add(kind, object, column, expected, actual string) {
+	d.Differences = append(d.Differences, SchemaDifference{Kind: kind, Object: object, Column: column, Expected: expected, Actual: actual})
+}
+
+// Diff 对比MySQL的表、索引、视图和函数按转换规则得到的期望结构与PostgreSQL当前schema中的实际结构
+// 期望的列定义由转换后的建表DDL在临时表中创建得到，类型和默认值与系统目录使用相同的格式
+func (m *Manager) Diff(ctx context.Context) (*SchemaDiff, error) {
+	m.Log("对比 MySQL 和 PostgreSQL 的表结构 ...")
+
+	tables, functions, indexes, views, _, _, err := m.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := m.postgresConn.CurrentSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := m.postgresConn.GetCatalogTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SchemaDiff{Schema: schema, Differences: []SchemaDifference{}}
+	options := m.config.Conversion.Options
+
+	// 表和列
+	expectedTables := make(map[string]bool)
+	for _, table := range tables {
+		pgTableName := targetTableName(m.config, table.Name)
+		expectedTables[pgTableName] = true
+		diff.Tables++
+		m.diffTable(ctx, diff, table, pgTableName, catalog[pgTableName])
+	}
+	// 指定了同步表范围时其他表不属于本次转换，不报告多出的表
+	if !options.UseTableList && !options.SkipUseTableList && (options.TableDDL || options.Data) {
+		var extra []string
+		for name := range catalog {
+			if !expectedTables[name] && name != rejectTableName {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			diff.add(DiffExtraTable, name, "", "", "")
+		}
+	}
+
+	// 索引
+	if options.Indexes {
+		for _, index := range indexes {
+			projected, ok := projectIndex(m.config, index)
+			if !ok {
+				continue
+			}
+			diff.Indexes++
+			pgTableName := targetTableName(m.config, index.Table)
+			if catalog[pgTableName] == nil {
+				continue // 缺少的表已报告
+			}
+			columns := m.indexColumns(index.Table, projected)
+			if len(columns) == 0 {
+				continue
+			}
+			m.diffIndex(diff, pgTableName, projected, columns, catalog[pgTableName].Indexes)
+		}
+	}
+
+	// 视图
+	if options.View && len(views) > 0 {
+		pgViews, err := m.postgresConn.GetViewNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, view := range views {
+			diff.Views++
+			if name := strings.ToLower(view.ViewName); !pgViews[name] {
+				diff.add(DiffMissingView, name, "", "", "")
+			}
+		}
+	}
+
+	// 函数
+	if options.Functions && len(functions) > 0 {
+		routines, err := m.postgresConn.GetRoutineNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, function := range functions {
+			diff.Functions++
+			if name := strings.ToLower(function.Name); !routines[name] {
+				diff.add(DiffMissingFunction, name, "", "", "")
+			}
+		}
+	}
+
+	m.Log("对比完成，%d 个表、%d 个索引、%d 个视图、%d 个函数，发现 %d 处差异", diff.Tables, diff.Indexes, diff.Views, diff.Functions, len(diff.Differences))
+	return diff, nil
+}
+
+// diffTable 对比一个表的列和主键，actual 为nil时表示PostgreSQL中没有该表
+func (m *Manager) diffTable(ctx context.Context, diff *SchemaDiff, table mysql.TableInfo, pgTableName string, actual *postgres.CatalogTable) {
+	if actual == nil {
+		diff.add(DiffMissingTable, pgTableName, "", "", "")
+		return
+	}
+
+	projected, pgResult, err := m.buildTableDDL(table)
+	if err != nil {
+		diff.add(DiffUnconvertibleTable, pgTableName, "", "", err.Error())
+		return
+	}
+	// 存储列名映射，用于对比索引列
+	m.tableColumnNamesMap[table.Name] = pgResult.ColumnNames
+
+	expected, err := m.postgresConn.ExpectedColumns(ctx, pgTableName, pgResult.DDL)
+	if err != nil {
+		diff.add(DiffUnconvertibleTable, pgTableName, "", "", err.Error())
+		return
+	}
+
+	actualColumns := make(map[string]postgres.CatalogColumn, len(actual.Columns))
+	for _, column := range actual.Columns {
+		actualColumns[column.Name] = column
+	}
+	expectedColumns := make(map[string]bool, len(expected))
+	for _, want := range expected {
+		expectedColumns[want.Name] = true
+		got, ok := actualColumns[want.Name]
+		if !ok {
+			diff.add(DiffMissingColumn, pgTableName, want.Name, want.Type, "")
+			continue
+		}
+		if got.Type != want.Type {
+			diff.add(DiffTypeMismatch, pgTableName, want.Name, want.Type, got.Type)
+		}
+		if got.NotNull != want.NotNull {
+			diff.add(DiffNullability, pgTableName, want.Name, nullability(want.NotNull), nullability(got.NotNull))
+		}
+		if reNextval.ReplaceAllString(got.Default, "nextval()") != reNextval.ReplaceAllString(want.Default, "nextval()") {
+			diff.add(DiffDefaultMismatch, pgTableName, want.Name, want.Default, got.Default)
+		}
+	}
+	for _, column := range actual.Columns {
+		if !expectedColumns[column.Name] {
+			diff.add(DiffExtraColumn, pgTableName, column.Name, "", column.Type)
+		}
+	}
+
+	// 主键
+	for _, index := range projected.Indexes {
+		if index.Name != "PRIMARY" {
+			continue
+		}
+		want := m.indexColumns(table.Name, index)
+		var got []string
+		for _, pgIndex := range actual.Indexes {
+			if pgIndex.IsPrimary {
+				got = pgIndex.Columns
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			diff.add(DiffMissingPrimaryKey, pgTableName, "", strings.Join(want, ", "), strings.Join(got, ", "))
+		}
+	}
+}
+
+// diffIndex 对比一个索引，PostgreSQL中存在相同列顺序的索引即视为已创建
+func (m *Manager) diffIndex(diff *SchemaDiff, pgTableName string, index mysql.IndexInfo, columns []string, pgIndexes []postgres.CatalogIndex) {
+	found := false
+	for _, pgIndex := range pgIndexes {
+		if strings.Join(pgIndex.Columns, ",") != strings.Join(columns, ",") {
+			continue
+		}
+		found = true
+		if !index.IsUnique || pgIndex.IsUnique {
+			return
+		}
+	}
+	expected := strings.Join(columns, ", ")
+	if index.IsUnique {
+		expected = "UNIQUE " + expected
+	}
+	if found {
+		diff.add(DiffIndexMismatch, pgTableName, index.Name, expected, "非唯一索引")
+		return
+	}
+	diff.add(DiffMissingIndex, pgTableName, index.Name, expected, "")
+}
+
+// indexColumns 按与 ConvertIndexDDL 相同的规则得到MySQL表 tableName 的索引在PostgreSQL中的列名
+func (m *Manager) indexColumns(tableName string, index mysql.IndexInfo) []string {
+	columnNamesMap := m.tableColumnNamesMap[tableName]
+	var columns []string
+	for _, column := range index.Columns {
+		if strings.ToLower(column) == "pri_key" {
+			continue
+		}
+		if converted, ok := columnNamesMap[column]; ok {
+			column = strings.Trim(converted, `"`)
+		}
+		if m.config.Conversion.Options.LowercaseColumns {
+			column = strings.ToLower(column)
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// nullability 列是否允许NULL的显示文本
+func nullability(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+// WriteText 以文本形式输出对比结果，每处差异一行
+func (d *SchemaDiff) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "对比 schema %s: %d 个表，%d 个索引，%d 个视图，%d 个函数\n", d.Schema, d.Tables, d.Indexes, d.Views, d.Functions)
+	if len(d.Differences) == 0 {
+		fmt.Fprintln(w, "没有发现差异")
+		return
+	}
+	for _, difference := range d.Differences {
+		object := difference.Object
+		if difference.Column != "" {
+			object += "." + difference.Column
+		}
+		line := fmt.Sprintf("%-22s %s", difference.Kind, object)
+		if difference.Expected != "" || difference.Actual != "" {
+			line += fmt.Sprintf("  期望: %s  实际: %s", difference.Expected, difference.Actual)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "共发现 %d 处差异\n", len(d.Differences))
+}
@@ -0,0 +1,390 @@
+package postgres
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+)
+
+// 转换后的列定义，如 "name" VARCHAR(20) not null
+var reDumpColumnDefinition = regexp.MustCompile(`^"([^"]+)"\s+(.+)$`)
+
+// dumpHeader 每个导出文件开头的会话设置，使文件可以单独加载
+const dumpHeader = "SET client_encoding = 'UTF8';\nSET standard_conforming_strings = on;\n\n"
+
+// pgTypeNames 转换后DDL中的类型与PostgreSQL类型名的对应关系，按顺序匹配前缀
+var pgTypeNames = []struct {
+	prefix string
+	name   string
+}{
+	{"BIGSERIAL", "int8"},
+	{"BIGINT", "int8"},
+	{"SMALLSERIAL", "int2"},
+	{"SMALLINT", "int2"},
+	{"SERIAL", "int4"},
+	{"INTEGER", "int4"},
+	{"REAL", "float4"},
+	{"DOUBLE PRECISION", "float8"},
+	{"NUMERIC", "numeric"},
+	{"DECIMAL", "numeric"},
+	{"BOOLEAN", "bool"},
+	{"BYTEA", "bytea"},
+	{"BIT VARYING", "varbit"},
+	{"BIT", "bit"},
+	{"UUID", "uuid"},
+	{"JSONB", "jsonb"},
+	{"JSON", "json"},
+	{"TIMESTAMPTZ", "timestamptz"},
+	{"TIMESTAMP WITH TIME ZONE", "timestamptz"},
+	{"TIMESTAMP", "timestamp"},
+	{"DATE", "date"},
+	{"TIME", "time"},
+}
+
+// pgColumnTypesFromDDL 从转换后的建表DDL获取各列的PostgreSQL类型名，键为小写列名
+// 导出到文件时没有目标库可查询，按DDL确定每列的值转换方式
+func pgColumnTypesFromDDL(ddl string) map[string]string {
+	start := strings.Index(ddl, "(")
+	end := strings.LastIndex(ddl, ")")
+	if start == -1 || end <= start {
+		return nil
+	}
+
+	columnTypes := make(map[string]string)
+	for _, part := range splitTopLevelCommas(ddl[start+1 : end]) {
+		match := reDumpColumnDefinition.FindStringSubmatch(part)
+		if match == nil {
+			continue
+		}
+		typeDef := strings.ToUpper(match[2])
+		for _, t := range pgTypeNames {
+			if strings.HasPrefix(typeDef, t.prefix) {
+				columnTypes[strings.ToLower(match[1])] = t.name
+				break
+			}
+		}
+	}
+	return columnTypes
+}
+
+// dumpWriter 导出文件写入器，文件按顺序编号，超过拆分大小时在语句或COPY数据行之间切换到下一个文件
+type dumpWriter struct {
+	dir        string
+	compress   bool
+	splitBytes int64
+
+	seq        int
+	name       string
+	file       *os.File
+	gz         *gzip.Writer
+	w          *bufio.Writer
+	size       int64
+	copyHeader string // 当前COPY块的语句，切换文件时在新文件中重新开始COPY
+	copyOpen   bool   // 当前文件中是否已写入COPY语句
+	files      []string
+}
+
+// newDumpWriter 创建导出文件写入器
+func newDumpWriter(dir string, compress bool, splitSizeMB int) *dumpWriter {
+	return &dumpWriter{dir: dir, compress: compress, splitBytes: int64(splitSizeMB) * 1024 * 1024}
+}
+
+// open 关闭当前文件并创建下一个编号的文件
+func (w *dumpWriter) open(name string) error {
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	w.seq++
+	w.name = name
+	path := filepath.Join(w.dir, fmt.Sprintf("%04d_%s.sql", w.seq, name))
+	if w.compress {
+		path += ".gz"
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建导出文件 %s 失败: %w", path, err)
+	}
+	w.file = file
+	var out io.Writer = file
+	if w.compress {
+		w.gz = gzip.NewWriter(file)
+		out = w.gz
+	}
+	w.w = bufio.NewWriterSize(out, 1024*1024)
+	w.size = 0
+	w.files = append(w.files, path)
+	return w.write([]byte(dumpHeader))
+}
+
+// write 写入内容并累计文件大小
+func (w *dumpWriter) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	return nil
+}
+
+// full 当前文件是否已达到拆分大小
+func (w *dumpWriter) full() bool {
+	return w.splitBytes > 0 && w.size >= w.splitBytes
+}
+
+// writeSQL 写入完整的SQL语句，当前文件已达到拆分大小时先切换到下一个文件
+func (w *dumpWriter) writeSQL(sql string) error {
+	if w.full() {
+		if err := w.open(w.name); err != nil {
+			return err
+		}
+	}
+	return w.write([]byte(sql))
+}
+
+// beginCopy 开始COPY数据块，COPY语句在写入第一行时写入，没有数据的表不生成COPY块
+func (w *dumpWriter) beginCopy(header string) {
+	w.copyHeader = header
+	w.copyOpen = false
+}
+
+// writeRow 写入一行COPY数据，当前文件已达到拆分大小时结束当前COPY块，在下一个文件中继续
+func (w *dumpWriter) writeRow(row []byte) error {
+	if !w.copyOpen {
+		if err := w.writeSQL(w.copyHeader); err != nil {
+			return err
+		}
+		w.copyOpen = true
+	} else if w.full() {
+		if err := w.write([]byte("\\.\n")); err != nil {
+			return err
+		}
+		if err := w.open(w.name); err != nil {
+			return err
+		}
+		if err := w.write([]byte(w.copyHeader)); err != nil {
+			return err
+		}
+	}
+	return w.write(row)
+}
+
+// endCopy 结束COPY数据块
+func (w *dumpWriter) endCopy() error {
+	if !w.copyOpen {
+		return nil
+	}
+	w.copyOpen = false
+	return w.write([]byte("\\.\n\n"))
+}
+
+// close 刷新并关闭当前文件
+func (w *dumpWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	file := w.file
+	w.file = nil
+	if err := w.w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			file.Close()
+			return fmt.Errorf("写入导出文件失败: %w", err)
+		}
+		w.gz = nil
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("关闭导出文件失败: %w", err)
+	}
+	return nil
+}
+
+// Dump 导出到文件：不连接PostgreSQL，将表结构、数据和其他对象按加载顺序写入可由psql执行的SQL文件
+// 数据以 COPY ... FROM stdin 块写入，值的转换与直接写入PostgreSQL时一致
+func (m *Manager) Dump(ctx context.Context) error {
+	dumpConfig := m.config.Dump
+	m.Log("导出表结构和数据到目录 %s，不连接PostgreSQL ...", dumpConfig.Dir)
+
+	tables, functions, indexes, views, users, tablePrivileges, err := m.getMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dumpConfig.Dir, 0755); err != nil {
+		return fmt.Errorf("创建导出目录失败: %w", err)
+	}
+
+	w := newDumpWriter(dumpConfig.Dir, dumpConfig.Compress, dumpConfig.SplitSizeMB)
+	defer w.close()
+
+	// 单文件模式下所有内容按顺序写入同一组文件，否则表结构、每个表的数据和其余对象分别写入
+	name := "dump"
+	if dumpConfig.PerTable {
+		name = "schema"
+	}
+	if err := w.open(name); err != nil {
+		return err
+	}
+
+	preData, postData := m.planSchema(tables, functions, indexes, views, users, tablePrivileges)
+	for _, file := range preData {
+		if err := w.writeSQL(file.content() + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if m.config.Conversion.Options.Data {
+		if m.config.Run.ShowConsoleLogs {
+			fmt.Println("\n导出表数据...")
+		}
+		for i, table := range sortTablesBySize(tables) {
+			if dumpConfig.PerTable {
+				if err := w.open("data_" + targetTableName(m.config, table.Name)); err != nil {
+					return err
+				}
+			}
+			rows, err := m.dumpTableData(ctx, w, table)
+			if err != nil {
+				m.logError(fmt.Sprintf("导出表 %s 数据失败: %v", table.Name, err))
+				return fmt.Errorf("导出表 %s 数据失败: %w", table.Name, err)
+			}
+			m.Log("导出表 %s 完成，%d 行数据", table.Name, rows)
+			if m.config.Run.ShowConsoleLogs {
+				fmt.Printf("进度: %.2f%% (%d/%d) : 导出表 %s 完成，%d 行数据\n", float64(i+1)/float64(len(tables))*100, i+1, len(tables), table.Name, rows)
+			}
+		}
+	}
+
+	if dumpConfig.PerTable {
+		if err := w.open("post_data"); err != nil {
+			return err
+		}
+	}
+	for _, file := range postData {
+		if err := w.writeSQL(file.content() + "\n"); err != nil {
+			return err
+		}
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n导出文件（按编号顺序使用 psql 加载）:")
+		for _, path := range w.files {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+	// 显示脱敏列审计信息和无效数据的处理统计
+	m.displayMaskingAudit()
+	m.displayDataFixes()
+
+	m.Log("导出完成，共 %d 个文件", len(w.files))
+	return nil
+}
+
+// dumpTableData 将表数据以 COPY ... FROM stdin 块写入导出文件，返回导出的行数
+func (m *Manager) dumpTableData(ctx context.Context, w *dumpWriter, table mysql.TableInfo) (int64, error) {
+	columns, columnTypes, err := m.mysqlConn.GetTableColumnsWithTypes(ctx, table.Name)
+	if err != nil {
+		return 0, err
+	}
+	tableConfig := m.config.Conversion.FindTable(table.Name)
+	columns = projectColumns(tableConfig, columns)
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("表 %s 没有需要同步的列", table.Name)
+	}
+
+	// 没有目标库时按转换后的DDL确定目标列类型，DDL转换失败时按文本写入
+	var pgColumnTypes map[string]string
+	if _, pgResult, err := m.buildTableDDL(table); err == nil && pgResult != nil {
+		pgColumnTypes = pgColumnTypesFromDDL(pgResult.DDL)
+	}
+	converter := postgres.NewRowConverter(columns, columnTypes, pgColumnTypes, uuidColumns(m.config, table.Name, columnTypes), &m.config.Conversion.InvalidData)
+	masker, err := postgres.NewColumnMasker(&m.config.Conversion.Masking, table.Name, columns, columnTypes)
+	if err != nil {
+		return 0, err
+	}
+	encoder := postgres.NewCopyTextEncoder(columns, pgColumnTypes)
+
+	// 与直接写入时相同，有单列主键时按主键分页读取，否则使用一次不分页的流式查询
+	primaryKeys, pkErr := m.mysqlConn.GetTablePrimaryKeys(ctx, table.Name)
+	if pkErr == nil {
+		if err := checkProjectedPrimaryKey(tableConfig, table.Name, primaryKeys); err != nil {
+			return 0, err
+		}
+	}
+	var primaryKey, orderBy string
+	if pkErr == nil && len(primaryKeys) == 1 {
+		primaryKey = primaryKeys[0]
+	} else if pkErr == nil {
+		var quotedKeys []string
+		for _, k := range primaryKeys {
+			quotedKeys = append(quotedKeys, fmt.Sprintf("`%s`", k))
+		}
+		orderBy = strings.Join(quotedKeys, ", ")
+	}
+
+	batchSize := m.config.Conversion.Limits.MaxRowsPerBatch
+	if batchSize <= 0 {
+		batchSize = 10000 // 默认值
+	}
+	stream := postgres.NewRowStream(m.config.Conversion.Limits.BatchInsertSize)
+	producer := &tableRowProducer{
+		mysqlConn:  m.mysqlConn,
+		tableName:  table.Name,
+		columns:    columns,
+		primaryKey: primaryKey,
+		keyColumns: primaryKeys,
+		orderBy:    orderBy,
+		pageSize:   batchSize,
+		filter:     tableConfig.Filter(),
+		converter:  converter,
+		masker:     masker,
+		limiter:    m.limiter,
+	}
+	go producer.run(ctx, stream)
+
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = fmt.Sprintf(`"%s"`, strings.ToLower(col))
+	}
+	header := fmt.Sprintf("COPY \"%s\" (%s) FROM stdin;\n", targetTableName(m.config, table.Name), strings.Join(quotedColumns, ", "))
+	w.beginCopy(header)
+
+	var rows int64
+	var line []byte
+	for {
+		row, ok := stream.Next()
+		if !ok {
+			break
+		}
+		line, err = encoder.EncodeRow(line[:0], row.Values)
+		if err == nil {
+			err = w.writeRow(line)
+		}
+		if err != nil {
+			stream.Stop()
+			return rows, fmt.Errorf("写入主键为 %s 的行失败: %w", row.PrimaryKey, err)
+		}
+		rows++
+	}
+	if err := stream.Err(); err != nil {
+		return rows, err
+	}
+
+	m.dataFixes.Record(table.Name, converter)
+	m.maskingAudit.Record(table.Name, masker)
+	return rows, w.endCopy()
+}
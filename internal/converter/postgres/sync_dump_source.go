@@ -0,0 +1,392 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+	"github.com/yourusername/mysql2pg/internal/throttle"
+)
+
+// SetDumpFile 使用mysqldump导出文件作为数据源，表结构、视图、函数和数据都从导出文件读取，不连接MySQL
+func (m *Manager) SetDumpFile(dumpFile *mysql.DumpFile) {
+	m.dumpFile = dumpFile
+}
+
+// dumpFileMetadata 从导出文件获取元数据，导出文件中没有用户和权限信息
+func (m *Manager) dumpFileMetadata() ([]mysql.TableInfo, []mysql.FunctionInfo, []mysql.IndexInfo, []mysql.ViewInfo, []mysql.UserInfo, []mysql.TablePrivInfo, error) {
+	options := m.config.Conversion.Options
+	var tables []mysql.TableInfo
+	var functions []mysql.FunctionInfo
+	var indexes []mysql.IndexInfo
+
+	if options.TableDDL || options.Indexes || options.Data || options.Grant {
+		tables = m.dumpFile.GetTables(options.SkipUseTableList, options.SkipTableList, options.UseTableList, options.TableList)
+
+		// 提取所有索引（排除主键）
+		if options.Indexes {
+			for _, table := range tables {
+				for _, index := range table.Indexes {
+					if index.Name != "PRIMARY" {
+						indexes = append(indexes, index)
+					}
+				}
+			}
+		}
+	}
+
+	if options.Functions {
+		functions = m.dumpFile.Functions
+	}
+	if len(m.dumpFile.Procedures) > 0 {
+		m.Log("警告: 导出文件中的存储过程 %s 没有对应的转换，已跳过", strings.Join(m.dumpFile.Procedures, ", "))
+	}
+	if len(m.dumpFile.Triggers) > 0 {
+		m.Log("警告: 导出文件中的触发器 %s 没有对应的转换，已跳过", strings.Join(m.dumpFile.Triggers, ", "))
+	}
+	if options.Users || options.Grant || options.TablePrivileges {
+		m.Log("警告: 导出文件中没有用户和权限信息，跳过用户和权限的转换")
+	}
+
+	return tables, functions, indexes, m.dumpFile.Views, nil, nil, nil
+}
+
+// dumpTableLoad 导出文件中一个表的一段连续数据的写入
+// 读取端解析 INSERT 语句并发送到行数据通道，写入端在独立的协程中从通道COPY到PostgreSQL
+type dumpTableLoad struct {
+	table      mysql.TableInfo
+	source     []string // INSERT 语句的列清单
+	positions  []int    // 写入的列在 INSERT 值中的位置
+	keyIndexes []int    // 主键列在 INSERT 值中的位置，用于标识被隔离的行
+	converter  *postgres.RowConverter
+	masker     *postgres.ColumnMasker
+	quarantine bool
+	stream     *postgres.RowStream
+	done       chan error
+}
+
+// loadDumpFileData 按文件顺序读取导出文件中的数据并写入PostgreSQL
+// mysqldump 按表依次导出数据，读取到下一个表的数据时当前表同步完成
+func (m *Manager) loadDumpFileData(ctx context.Context, tables []mysql.TableInfo) error {
+	// 启用坏行隔离时先准备隔离行的保存位置
+	if err := m.rejects.Prepare(ctx, m.postgresConn); err != nil {
+		return err
+	}
+
+	selected := make(map[string]mysql.TableInfo, len(tables))
+	for _, table := range tables {
+		selected[table.Name] = table
+	}
+	progress := NewDataProgress(tables)
+	rowCounts := make(map[string]int64)
+	loadErrors := make(map[string]error)
+	completed := make(map[string]bool)
+	// 各表开始读取数据的时间，用于运行报告中的耗时
+	started := make(map[string]time.Time)
+	var firstErr error
+
+	var current *dumpTableLoad
+	// finishLoad 结束当前一段数据的写入
+	finishLoad := func(readErr error) {
+		if current == nil {
+			return
+		}
+		err := current.finish(readErr)
+		m.maskingAudit.Record(current.table.Name, current.masker)
+		m.dataFixes.Record(current.table.Name, current.converter)
+		if err != nil && loadErrors[current.table.Name] == nil {
+			loadErrors[current.table.Name] = err
+		}
+		current = nil
+	}
+	// completeTable 表的数据读取完毕，校验并显示结果
+	completeTable := func(table mysql.TableInfo) {
+		startTime, ok := started[table.Name]
+		if !ok {
+			startTime = time.Now()
+		}
+		if err := m.completeDumpTable(ctx, table, rowCounts[table.Name], loadErrors[table.Name], startTime, progress, !completed[table.Name]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		completed[table.Name] = true
+	}
+
+	var currentTable string
+	err := m.dumpFile.ScanRows(func(tableName string, columns []string, values []interface{}) error {
+		// 运行被取消时停止读取导出文件
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		table, ok := selected[tableName]
+		if !ok {
+			return nil
+		}
+		if tableName != currentTable {
+			finishLoad(nil)
+			if currentTable != "" {
+				completeTable(selected[currentTable])
+			}
+			currentTable = tableName
+			if _, ok := started[tableName]; !ok {
+				started[tableName] = time.Now()
+			}
+		}
+		if loadErrors[tableName] != nil {
+			// 表已同步失败，跳过剩余的数据
+			return nil
+		}
+
+		// 同一个表的 INSERT 语句列清单变化时重新开始写入
+		if current != nil && !equalColumns(current.source, columns) {
+			finishLoad(nil)
+		}
+		if current == nil {
+			var err error
+			current, err = m.startDumpTableLoad(ctx, table, columns, rowCounts[tableName] == 0, progress)
+			if err != nil {
+				loadErrors[tableName] = err
+				return nil
+			}
+		}
+
+		rowCounts[tableName]++
+		if sent, err := current.send(ctx, values, m.limiter); err != nil || !sent {
+			finishLoad(err)
+		}
+		return nil
+	})
+	finishLoad(err)
+	if currentTable != "" {
+		completeTable(selected[currentTable])
+	}
+	if err != nil {
+		return fmt.Errorf("读取导出文件数据失败: %w", err)
+	}
+
+	// 导出文件中没有数据的表
+	for _, table := range tables {
+		if !completed[table.Name] {
+			completeTable(table)
+		}
+	}
+	return firstErr
+}
+
+// startDumpTableLoad 开始写入表的一段数据，source 为 INSERT 语句的列清单，truncate 为 true 时按配置先清空表
+func (m *Manager) startDumpTableLoad(ctx context.Context, table mysql.TableInfo, source []string, truncate bool, progress *DataProgress) (*dumpTableLoad, error) {
+	tableConfig := m.config.Conversion.FindTable(table.Name)
+	columns := projectColumns(tableConfig, source)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("表 %s 没有需要同步的列", table.Name)
+	}
+	if tableConfig.Filter() != "" {
+		m.Log("警告: 表 %s 配置的数据过滤条件在从导出文件转换时不生效，将同步全部数据", table.Name)
+	}
+
+	load := &dumpTableLoad{
+		table:      table,
+		source:     source,
+		quarantine: m.rejects != nil,
+		done:       make(chan error, 1),
+	}
+	for _, col := range columns {
+		for i, name := range source {
+			if name == col {
+				load.positions = append(load.positions, i)
+				break
+			}
+		}
+	}
+	primaryKeys := m.dumpFile.PrimaryKeys(table.Name)
+	if err := checkProjectedPrimaryKey(tableConfig, table.Name, primaryKeys); err != nil {
+		return nil, err
+	}
+	for _, key := range primaryKeys {
+		for i, name := range source {
+			if strings.EqualFold(name, key) {
+				load.keyIndexes = append(load.keyIndexes, i)
+			}
+		}
+	}
+
+	columnTypes := make(map[string]string, len(table.Columns))
+	for _, col := range table.Columns {
+		columnTypes[col.Name] = col.Type
+	}
+	uuids := uuidColumns(m.config, table.Name, columnTypes)
+	tableName := targetTableName(m.config, table.Name)
+
+	// upsert方式写入时按主键合并，不清空表数据
+	var upsert *postgres.UpsertOptions
+	if m.config.Conversion.Options.IsUpsertLoad() {
+		if len(primaryKeys) > 0 {
+			upsert = &postgres.UpsertOptions{
+				ConflictColumns: primaryKeys,
+				UseMerge:        m.postgresConn.SupportsMerge(),
+			}
+		} else {
+			m.Log("警告: 表 %s 没有主键，无法使用upsert方式写入，将使用COPY方式写入", table.Name)
+		}
+	}
+	if truncate && m.config.Conversion.Options.TruncateBeforeSync && upsert == nil {
+		if err := m.postgresConn.ExecuteDDL(ctx, fmt.Sprintf("TRUNCATE TABLE \"%s\"", tableName)); err != nil {
+			return nil, fmt.Errorf("清空表 %s 数据失败: %w", table.Name, err)
+		}
+	}
+
+	// 按目标列类型创建行转换器，使COPY以二进制格式写入数值和时间
+	var err error
+	load.converter, err = m.postgresConn.NewRowConverter(ctx, tableName, columns, columnTypes, uuids, &m.config.Conversion.InvalidData)
+	if err != nil {
+		m.Log("警告: %v，表 %s 的数据将按文本格式写入", err, table.Name)
+		load.converter = postgres.NewRowConverter(columns, columnTypes, nil, uuids, &m.config.Conversion.InvalidData)
+	}
+	load.masker, err = postgres.NewColumnMasker(&m.config.Conversion.Masking, table.Name, columns, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := m.config.Conversion.Limits.MaxRowsPerBatch
+	if batchSize <= 0 {
+		batchSize = 10000 // 默认值
+	}
+	batchInsertSize := m.config.Conversion.Limits.BatchInsertSize
+	if batchInsertSize <= 0 {
+		batchInsertSize = 10000 // 默认值
+	}
+	load.stream = postgres.NewRowStream(batchInsertSize)
+
+	go func() {
+		var written int64
+		var err error
+		for {
+			// 每 batchSize 行数据在一个事务中提交
+			tx, beginErr := m.postgresConn.BeginLoadTransaction(ctx)
+			if beginErr != nil {
+				err = fmt.Errorf("开始事务失败: %w", beginErr)
+				break
+			}
+			n, eof, copyErr := m.postgresConn.CopyFromStream(ctx, tx, tableName, columns, batchInsertSize, upsert, load.stream, batchSize, m.rejects.Handler(ctx, tx, table.Name, columns))
+			if copyErr != nil {
+				tx.Rollback(context.Background())
+				err = fmt.Errorf("插入表 %s 数据失败: %w", table.Name, copyErr)
+				break
+			}
+			if commitErr := tx.Commit(ctx); commitErr != nil {
+				err = fmt.Errorf("提交事务失败: %w", commitErr)
+				break
+			}
+			written += int64(n)
+			progress.Update(table.Name, written)
+			if eof {
+				break
+			}
+		}
+		if err != nil {
+			load.stream.Stop()
+		}
+		load.done <- err
+	}()
+
+	return load, nil
+}
+
+// send 转换一行数据并发送给写入端，写入端已停止时返回 false
+// 未启用坏行隔离时转换失败返回错误
+func (l *dumpTableLoad) send(ctx context.Context, values []interface{}, limiter *throttle.Limiter) (bool, error) {
+	raw := make([]interface{}, len(l.positions))
+	for i, pos := range l.positions {
+		raw[i] = values[pos]
+	}
+	// 按从导出文件读取的数据量限速
+	if err := limiter.Wait(ctx, postgres.RowByteSize(raw), 1); err != nil {
+		return false, err
+	}
+
+	rowValues := make([]interface{}, len(raw))
+	row := postgres.StreamRow{Values: rowValues, PrimaryKey: formatRowKey(values, l.keyIndexes)}
+	// 脱敏在类型转换前处理原始值，结果按列类型转换后写入
+	l.masker.Apply(raw)
+	if err := l.converter.Convert(raw, rowValues); err != nil {
+		if !l.quarantine {
+			return false, fmt.Errorf("转换表 %s 主键为 %s 的行失败: %w", l.table.Name, row.PrimaryKey, err)
+		}
+		row.Rejected = err.Error()
+	}
+	return l.stream.Send(row), nil
+}
+
+// finish 结束发送并等待写入端完成，readErr 不为nil时写入端回滚当前批次
+func (l *dumpTableLoad) finish(readErr error) error {
+	l.stream.Close(readErr)
+	return <-l.done
+}
+
+// completeDumpTable 表的数据同步结束后校验行数并显示结果，first 为 false 时表在导出文件中再次出现，不重复计入进度
+func (m *Manager) completeDumpTable(ctx context.Context, table mysql.TableInfo, rows int64, loadErr error, started time.Time, progress *DataProgress, first bool) error {
+	progress.Finish(table.Name)
+	if first {
+		defer m.updateProgress()
+	}
+
+	pgTableName := targetTableName(m.config, table.Name)
+	if loadErr != nil {
+		m.logError(fmt.Sprintf("同步表 %s 失败: %v", table.Name, loadErr))
+		m.report.RecordTable(table.Name, pgTableName, ReportFailed, rows, time.Since(started), "", loadErr)
+		return fmt.Errorf("同步表 %s 失败: %w", table.Name, loadErr)
+	}
+
+	// 没有MySQL连接，只比较导出文件中的行数和PostgreSQL表的行数
+	validationResult := "跳过验证"
+	if m.config.Conversion.Options.ValidateData {
+		pgRowCount, err := m.postgresConn.GetTableRowCount(ctx, pgTableName)
+		if err != nil {
+			m.logError(fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err))
+			m.report.RecordTable(table.Name, pgTableName, ReportFailed, rows, time.Since(started), "", err)
+			return fmt.Errorf("同步表 %s 失败: %w", table.Name, err)
+		}
+		validationResult = "数据一致"
+		if pgRowCount != rows {
+			validationResult = "数据不一致"
+			m.mutex.Lock()
+			m.inconsistentTables = append(m.inconsistentTables, TableDataInconsistency{
+				TableName:        table.Name,
+				MySQLRowCount:    rows,
+				PostgresRowCount: pgRowCount,
+			})
+			m.mutex.Unlock()
+		}
+	}
+
+	// 被隔离的行没有写入PostgreSQL
+	if rejected := m.rejects.Count(table.Name); rejected > 0 {
+		validationResult += fmt.Sprintf("，%d 行写入失败已隔离到 %s", rejected, m.rejects.Location())
+	}
+
+	if m.config.Run.ShowConsoleLogs {
+		m.mutex.Lock()
+		overallProgress := progress.Percent(m.completedTasks, m.totalTasks)
+		currentTask := m.completedTasks + 1
+		fmt.Printf("进度: %.2f%% (%d/%d) : 同步表 %s 完成，%d 行数据，%s%s\n", overallProgress, currentTask, m.totalTasks, table.Name, rows, validationResult, progress.ETA())
+		m.mutex.Unlock()
+	}
+	m.Log("从导出文件同步表 %s 完成，%d 行数据，%s", table.Name, rows, validationResult)
+	m.report.RecordTable(table.Name, pgTableName, ReportSynced, rows, time.Since(started), validationResult, nil)
+	return nil
+}
+
+// equalColumns 判断两个列清单是否相同
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fastLoadTable 快速加载模式下创建的表，数据同步后需要创建主键和索引、补建约束并转换为普通表
+type fastLoadTable struct {
+	name        string          // PostgreSQL表名
+	primaryKey  string          // 延后添加的主键约束，upsert写入时主键随表创建，为空
+	indexes     []fastLoadIndex // 延后到数据同步后创建的索引（包括唯一索引）
+	constraints []string        // 延后添加的CHECK约束
+
+	logged           bool // 已转换为普通表
+	primaryKeyFailed bool // 延后的主键添加失败
+}
+
+// ErrFastLoadIncomplete 运行结束时仍有快速加载的表为UNLOGGED或缺少主键
+var ErrFastLoadIncomplete = errors.New("快速加载未完成")
+
+// fastLoadIndex 数据同步后创建的主键或索引
+type fastLoadIndex struct {
+	table string // PostgreSQL表名
+	name  string // 索引名，主键为空
+	ddl   string
+}
+
+// fastLoadTableDDL 生成快速加载模式下的建表DDL：创建为UNLOGGED表并移除CHECK约束，
+// deferPrimaryKey 为 true 时同时移除主键约束，数据写入时不再维护主键索引
+// 返回新的DDL、需要在数据同步后添加的主键和CHECK约束
+func fastLoadTableDDL(result *ConvertTableDDLResult, deferPrimaryKey bool) (string, string, []string) {
+	ddl := result.DDL
+	if !strings.HasPrefix(ddl, "CREATE TABLE ") {
+		// 临时表不需要转换
+		return ddl, "", nil
+	}
+	ddl = "CREATE UNLOGGED TABLE " + strings.TrimPrefix(ddl, "CREATE TABLE ")
+
+	var primaryKey string
+	if deferPrimaryKey && result.PrimaryKey != "" && strings.Contains(ddl, ","+result.PrimaryKey) {
+		ddl = strings.Replace(ddl, ","+result.PrimaryKey, "", 1)
+		primaryKey = result.PrimaryKey
+	}
+
+	var constraints []string
+	for _, constraint := range result.Constraints {
+		if strings.Contains(ddl, ","+constraint) {
+			ddl = strings.Replace(ddl, ","+constraint, "", 1)
+			constraints = append(constraints, constraint)
+		}
+	}
+	return ddl, primaryKey, constraints
+}
+
+// createFastLoadTableDDL 快速加载模式下的建表DDL并记录延后创建的主键和约束
+// upsert写入（包括增量同步）按主键合并，主键必须在写入数据前存在，不延后
+func (m *Manager) createFastLoadTableDDL(pgTableName string, result *ConvertTableDDLResult) string {
+	deferPrimaryKey := !m.config.Conversion.Options.IsUpsertLoad()
+	ddl, primaryKey, constraints := fastLoadTableDDL(result, deferPrimaryKey)
+	if !deferPrimaryKey && result.PrimaryKey != "" {
+		m.Log("快速加载: 表 %s 以upsert方式写入，主键随表创建", pgTableName)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.fastLoadTables = append(m.fastLoadTables, fastLoadTable{name: pgTableName, primaryKey: primaryKey, constraints: constraints})
+	return ddl
+}
+
+// markFastLoadTable 在互斥锁保护下更新快速加载表的收尾状态
+func (m *Manager) markFastLoadTable(pgTableName string, update func(table *fastLoadTable)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i := range m.fastLoadTables {
+		if m.fastLoadTables[i].name == pgTableName {
+			update(&m.fastLoadTables[i])
+		}
+	}
+}
+
+// pendingFastLoadTables 仍为UNLOGGED或缺少主键的快速加载表
+func (m *Manager) pendingFastLoadTables() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var pending []string
+	for _, table := range m.fastLoadTables {
+		if !table.logged || table.primaryKeyFailed {
+			pending = append(pending, table.name)
+		}
+	}
+	return pending
+}
+
+// recoverFastLoad 运行失败或被中断后处理未完成收尾的快速加载表，返回运行的最终错误
+// 运行失败（未被中断）且还没有执行过收尾时执行收尾，使已写入的数据可以安全使用；
+// 被中断时不再执行耗时的建索引步骤，只记录警告。仍有未完成的表时记录到运行报告，
+// 并在错误中包装 ErrFastLoadIncomplete，调用方据此返回单独的退出码
+func (m *Manager) recoverFastLoad(ctx context.Context, runErr error) error {
+	if len(m.fastLoadTables) == 0 {
+		return runErr
+	}
+	if ctx.Err() == nil && !m.fastLoadFinished {
+		m.Log("运行失败，执行快速加载收尾，避免表保持UNLOGGED且缺少主键、索引和约束")
+		if err := m.finishFastLoad(ctx, make(chan struct{}, m.config.Conversion.Limits.Concurrency)); err != nil {
+			m.logError(fmt.Sprintf("快速加载收尾失败: %v", err))
+		}
+	}
+
+	pending := m.pendingFastLoadTables()
+	if len(pending) == 0 {
+		return runErr
+	}
+	m.report.SetFastLoadPending(pending)
+	message := fmt.Sprintf("快速加载模式下创建的 %d 个表仍为UNLOGGED或未创建主键、索引和约束，请重新运行转换: %s", len(pending), strings.Join(pending, ", "))
+	m.Log("警告: %s", message)
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Printf("警告: %s\n", message)
+	}
+	if runErr == nil {
+		return fmt.Errorf("%w: %s", ErrFastLoadIncomplete, message)
+	}
+	return fmt.Errorf("%w（%w: %s）", runErr, ErrFastLoadIncomplete, message)
+}
+
+// deferFastLoadIndex 表为快速加载模式下创建的表时，记录索引在数据同步后创建并返回 true
+func (m *Manager) deferFastLoadIndex(pgTableName, indexName, ddl string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i := range m.fastLoadTables {
+		if m.fastLoadTables[i].name == pgTableName {
+			m.fastLoadTables[i].indexes = append(m.fastLoadTables[i].indexes, fastLoadIndex{table: pgTableName, name: indexName, ddl: ddl})
+			return true
+		}
+	}
+	return false
+}
+
+// prepareFastLoad 数据同步前启用以 replica 角色写入，当前用户没有权限时只记录警告
+func (m *Manager) prepareFastLoad(ctx context.Context) {
+	if err := m.postgresConn.EnableReplicaRole(ctx); err != nil {
+		m.Log("警告: %v，快速加载时将正常执行触发器和外键检查", err)
+		return
+	}
+	m.Log("快速加载: 数据写入事务以 session_replication_role = replica 执行")
+}
+
+// finishFastLoad 快速加载收尾，在所有转换阶段完成后执行，与启用了哪些转换选项无关，顺序固定为：
+// 数据同步 → 并行创建主键和索引 → 并发为每个表补建CHECK约束、SET LOGGED并ANALYZE
+// 主键或索引创建失败时仍完成其余步骤，避免表保持UNLOGGED，最后返回错误；
+// 约束添加失败（MySQL中存在违反约束的数据）时只记录错误，SET LOGGED失败时返回错误
+func (m *Manager) finishFastLoad(ctx context.Context, semaphore chan struct{}) error {
+	if len(m.fastLoadTables) == 0 {
+		return nil
+	}
+	m.fastLoadFinished = true
+
+	indexErr := m.buildFastLoadIndexes(ctx, semaphore)
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n9. 快速加载收尾（补建约束、SET LOGGED、ANALYZE）...")
+	}
+	startTime := time.Now()
+
+	var wg sync.WaitGroup
+	errorChan := make(chan error, 1)
+	for _, table := range m.fastLoadTables {
+		wg.Add(1)
+		go func(table fastLoadTable) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			for _, constraint := range table.constraints {
+				ddl := fmt.Sprintf(`ALTER TABLE "%s" ADD %s`, table.name, constraint)
+				if err := m.postgresConn.ExecuteDDL(ctx, ddl); err != nil {
+					m.logError(fmt.Sprintf("为表 %s 添加约束失败: %v", table.name, err))
+				}
+			}
+
+			if err := m.postgresConn.ExecuteDDL(ctx, fmt.Sprintf(`ALTER TABLE "%s" SET LOGGED`, table.name)); err != nil {
+				m.logError(fmt.Sprintf("将表 %s 转换为普通表失败: %v", table.name, err))
+				sendError(errorChan, fmt.Errorf("将表 %s 转换为普通表失败: %w", table.name, err))
+				return
+			}
+			m.markFastLoadTable(table.name, func(t *fastLoadTable) { t.logged = true })
+
+			if err := m.postgresConn.ExecuteDDL(ctx, fmt.Sprintf(`ANALYZE "%s"`, table.name)); err != nil {
+				m.logError(fmt.Sprintf("更新表 %s 统计信息失败: %v", table.name, err))
+			}
+			m.Log("快速加载: 表 %s 已转换为普通表并更新统计信息", table.name)
+		}(table)
+	}
+	wg.Wait()
+
+	m.conversionStats = append(m.conversionStats, ConversionStageStat{
+		StageName:   "快速加载收尾",
+		StartTime:   startTime,
+		EndTime:     time.Now(),
+		ObjectCount: len(m.fastLoadTables),
+	})
+
+	select {
+	case err := <-errorChan:
+		return err
+	default:
+	}
+	return indexErr
+}
+
+// buildFastLoadIndexes 数据同步后创建快速加载表的主键和索引
+// 所有表的主键和索引一起调度，每个作为一个任务由 semaphore 控制并发，主键先于同一个表的索引开始
+func (m *Manager) buildFastLoadIndexes(ctx context.Context, semaphore chan struct{}) error {
+	var jobs []fastLoadIndex
+	for _, table := range m.fastLoadTables {
+		if table.primaryKey != "" {
+			jobs = append(jobs, fastLoadIndex{table: table.name, ddl: fmt.Sprintf(`ALTER TABLE "%s" ADD %s`, table.name, table.primaryKey)})
+		}
+		jobs = append(jobs, table.indexes...)
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("\n8. 快速加载: 并行创建主键和索引...")
+	}
+	startTime := time.Now()
+
+	var wg sync.WaitGroup
+	errorChan := make(chan error, 1)
+	for _, job := range jobs {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(job fastLoadIndex) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := m.postgresConn.ExecuteDDL(ctx, job.ddl)
+			switch {
+			case job.name == "" && err != nil:
+				m.logError(fmt.Sprintf("为表 %s 添加主键失败: %v", job.table, err))
+				m.markFastLoadTable(job.table, func(t *fastLoadTable) { t.primaryKeyFailed = true })
+				err = fmt.Errorf("为表 %s 添加主键失败: %w", job.table, err)
+			case job.name == "":
+				m.Log("快速加载: 表 %s 已添加主键", job.table)
+			case err != nil && strings.Contains(err.Error(), "already exists"):
+				m.Log("索引 %s 已存在，跳过创建", job.name)
+				m.report.SkipObject("index", job.name, "索引已存在")
+				err = nil
+			case err != nil:
+				m.logError(fmt.Sprintf("执行索引 %s DDL失败: %v", job.name, err))
+				m.report.RecordObject("index", job.name, ReportFailed, job.ddl, err)
+			default:
+				m.report.RecordObject("index", job.name, ReportConverted, job.ddl, nil)
+				m.Log("快速加载: 已创建表 %s 的索引 %s", job.table, job.name)
+			}
+			if err != nil {
+				sendError(errorChan, err)
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	m.conversionStats = append(m.conversionStats, ConversionStageStat{
+		StageName:   "快速加载创建索引",
+		StartTime:   startTime,
+		EndTime:     time.Now(),
+		ObjectCount: len(jobs),
+	})
+
+	select {
+	case err := <-errorChan:
+		return err
+	default:
+	}
+	return nil
+}
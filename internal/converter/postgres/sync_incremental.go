@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TableWatermark 单表的增量同步水位记录
+type TableWatermark struct {
+	Column    string    `json:"column"`     // 水位列
+	Value     string    `json:"value"`      // 上次同步到的水位值（含）
+	UpdatedAt time.Time `json:"updated_at"` // 记录时间
+}
+
+// IncrementalState 增量同步水位状态，保存在本地状态文件中
+type IncrementalState struct {
+	path   string
+	mutex  sync.Mutex
+	Tables map[string]TableWatermark `json:"tables"`
+}
+
+// LoadIncrementalState 从状态文件加载水位状态，文件不存在时返回空状态
+func LoadIncrementalState(path string) (*IncrementalState, error) {
+	state := &IncrementalState{
+		path:   path,
+		Tables: make(map[string]TableWatermark),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("读取增量同步状态文件失败: %w", err)
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return state, nil
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("解析增量同步状态文件 %s 失败: %w", path, err)
+	}
+	if state.Tables == nil {
+		state.Tables = make(map[string]TableWatermark)
+	}
+
+	return state, nil
+}
+
+// Get 获取表的水位记录
+func (s *IncrementalState) Get(tableName string) (TableWatermark, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	watermark, ok := s.Tables[tableName]
+	return watermark, ok
+}
+
+// Update 更新表的水位记录并立即写回状态文件
+func (s *IncrementalState) Update(tableName string, watermark TableWatermark) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	watermark.UpdatedAt = time.Now()
+	s.Tables[tableName] = watermark
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化增量同步状态失败: %w", err)
+	}
+
+	// 先写入临时文件再重命名，避免进程中断导致状态文件损坏
+	tmpFile, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("创建增量同步状态临时文件失败: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入增量同步状态失败: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入增量同步状态失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("保存增量同步状态文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// resolveColumnName 在表的列列表中查找列名（忽略大小写），返回表中实际的列名
+func resolveColumnName(columns []string, name string) (string, bool) {
+	for _, col := range columns {
+		if strings.EqualFold(col, name) {
+			return col, true
+		}
+	}
+	return "", false
+}
+
+// watermarkArg 根据水位列的MySQL类型将状态文件中的字符串水位值转换为查询参数
+// 整数类型转换为int64，避免MySQL按浮点数比较大整数时丢失精度
+func watermarkArg(value string, columnType string) interface{} {
+	lowerType := strings.ToLower(columnType)
+	if strings.Contains(lowerType, "int") {
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+		if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// buildWatermarkFilter 构建增量同步的水位范围条件：[上次水位, 本次水位上界]
+// 上界在同步开始前确定，同步过程中新写入的数据留给下一次同步处理
+// 下界包含上次水位：updated_at 等非唯一的水位列上，上次同步后才提交、水位值等于上次水位的行不会被漏掉；
+// 增量同步按主键upsert写入，重复读取边界上的行不影响结果
+func buildWatermarkFilter(column, columnType string, previous *TableWatermark, upperBound string) (string, []interface{}) {
+	if previous != nil {
+		return fmt.Sprintf("`%s` >= ? AND `%s` <= ?", column, column),
+			[]interface{}{watermarkArg(previous.Value, columnType), watermarkArg(upperBound, columnType)}
+	}
+	return fmt.Sprintf("`%s` <= ?", column), []interface{}{watermarkArg(upperBound, columnType)}
+}
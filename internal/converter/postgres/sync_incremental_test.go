@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildWatermarkFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		columnType string
+		previous   *TableWatermark
+		upper      string
+		filter     string
+		args       []interface{}
+	}{
+		{
+			name:       "首次同步",
+			columnType: "datetime",
+			upper:      "2024-05-01 10:00:00",
+			filter:     "`updated_at` <= ?",
+			args:       []interface{}{"2024-05-01 10:00:00"},
+		},
+		{
+			// 下界包含上次水位，上次同步后提交的同一时间戳的行不会被漏掉
+			name:       "包含上次水位",
+			columnType: "datetime",
+			previous:   &TableWatermark{Column: "updated_at", Value: "2024-05-01 09:00:00"},
+			upper:      "2024-05-01 10:00:00",
+			filter:     "`updated_at` >= ? AND `updated_at` <= ?",
+			args:       []interface{}{"2024-05-01 09:00:00", "2024-05-01 10:00:00"},
+		},
+		{
+			name:       "整数水位",
+			columnType: "bigint unsigned",
+			previous:   &TableWatermark{Column: "updated_at", Value: "18446744073709551614"},
+			upper:      "18446744073709551615",
+			filter:     "`updated_at` >= ? AND `updated_at` <= ?",
+			args:       []interface{}{uint64(18446744073709551614), uint64(18446744073709551615)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, args := buildWatermarkFilter("updated_at", tt.columnType, tt.previous, tt.upper)
+			if filter != tt.filter || !reflect.DeepEqual(args, tt.args) {
+				t.Fatalf("buildWatermarkFilter() = %q %v，期望 %q %v", filter, args, tt.filter, tt.args)
+			}
+		})
+	}
+}
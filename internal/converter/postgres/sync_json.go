@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+var (
+	// 列定义中列名之后的类型，如 `doc` longtext 中的 longtext
+	reColumnDefinitionType = regexp.MustCompile("^(\\s*`[^`]+`\\s+)(\\w+(?:\\(\\d+\\))?)")
+	// 可以配置为转换为 jsonb 的文本类型
+	reJSONTextType = regexp.MustCompile(`^(char|varchar|tinytext|text|mediumtext|longtext)\b`)
+	// 文本列的字符集
+	reColumnCharset = regexp.MustCompile(`(?i)\s+(?:CHARACTER\s+SET|CHARSET)\s+\w+`)
+)
+
+// jsonbColumns 获取表中转换为 jsonb 的列，键为小写列名
+// json_mode 为 jsonb 时包含所有 json 列，另外包含 json_columns 中配置的文本列
+func jsonbColumns(cfg *config.Config, tableName string, columnTypes map[string]string) map[string]bool {
+	var result map[string]bool
+	for col, colType := range columnTypes {
+		lowerType := strings.ToLower(strings.TrimSpace(colType))
+		isJSON := lowerType == "json" && cfg.Conversion.Options.JSONMode == config.JSONModeJSONB
+		isText := reJSONTextType.MatchString(lowerType) && cfg.Conversion.IsJSONColumn(tableName, col)
+		if !isJSON && !isText {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]bool)
+		}
+		result[strings.ToLower(col)] = true
+	}
+	return result
+}
+
+// tableJSONBColumns 获取表结构中转换为 jsonb 的列
+func tableJSONBColumns(cfg *config.Config, table mysql.TableInfo) map[string]bool {
+	columnTypes := make(map[string]string, len(table.Columns))
+	for _, col := range table.Columns {
+		columnTypes[col.Name] = col.Type
+	}
+	return jsonbColumns(cfg, table.Name, columnTypes)
+}
+
+// applyJSONBColumns 将SHOW CREATE TABLE输出中需要转换的列的类型替换为 jsonb，并移除文本列的字符集和排序规则
+func applyJSONBColumns(mysqlDDL string, columns map[string]bool) string {
+	if len(columns) == 0 {
+		return mysqlDDL
+	}
+
+	lines := strings.Split(mysqlDDL, "\n")
+	for i, line := range lines {
+		match := reColumnDefinitionLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil || !columns[strings.ToLower(match[1])] {
+			continue
+		}
+		line = reColumnDefinitionType.ReplaceAllString(line, "${1}jsonb")
+		line = reColumnCharset.ReplaceAllString(line, "")
+		lines[i] = reCollateSuffix.ReplaceAllString(line, "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"sync"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+)
+
+// MaskingAuditEntry 单表单列的脱敏审计记录
+type MaskingAuditEntry struct {
+	TableName string
+	postgres.MaskedColumn
+}
+
+// MaskingAudit 本次运行的脱敏审计信息
+type MaskingAudit struct {
+	mutex   sync.Mutex
+	Entries []MaskingAuditEntry
+}
+
+// Record 记录表的脱敏列信息
+func (a *MaskingAudit) Record(tableName string, masker *postgres.ColumnMasker) {
+	if a == nil || masker == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, col := range masker.MaskedColumns() {
+		a.Entries = append(a.Entries, MaskingAuditEntry{TableName: tableName, MaskedColumn: col})
+	}
+}
+
+// unmaskedColumns 过滤掉需要脱敏的列，脱敏列两端内容必然不同，不参与校验和比较
+func unmaskedColumns(cfg *config.Config, tableName string, columns []string) []string {
+	var result []string
+	for _, col := range columns {
+		if cfg.Conversion.Masking.FindRule(tableName, col) == nil {
+			result = append(result, col)
+		}
+	}
+	return result
+}
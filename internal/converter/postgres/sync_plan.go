@@ -0,0 +1,359 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+var (
+	// 转换时移除的列属性和表属性，在迁移脚本中作为警告列出
+	rePlanOnUpdate  = regexp.MustCompile(`(?i)\bON\s+UPDATE\s+CURRENT_TIMESTAMP\b`)
+	rePlanUnsigned  = regexp.MustCompile(`(?i)\bunsigned\b`)
+	rePlanVirtual   = regexp.MustCompile(`(?i)\bVIRTUAL\b`)
+	rePlanPartition = regexp.MustCompile(`(?i)\bPARTITION\s+BY\b`)
+	rePlanFulltext  = regexp.MustCompile("(?i)^FULLTEXT\\s+(?:KEY|INDEX)\\s+`([^`]+)`")
+)
+
+// planFile 迁移脚本中一个阶段的SQL文件
+type planFile struct {
+	stage      string // 阶段名称，用于文件名
+	title      string // 阶段说明，写在文件开头
+	body       strings.Builder
+	statements int
+	warnings   int
+}
+
+// newPlanFile 创建阶段SQL文件
+func newPlanFile(stage, title string) *planFile {
+	return &planFile{stage: stage, title: title}
+}
+
+// comment 写入SQL注释，多行内容逐行注释
+func (f *planFile) comment(format string, args ...interface{}) {
+	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
+		f.body.WriteString("-- " + line + "\n")
+	}
+}
+
+// warn 以SQL注释写入转换警告
+func (f *planFile) warn(format string, args ...interface{}) {
+	f.warnings++
+	f.comment("警告: "+format, args...)
+}
+
+// statement 写入一条SQL语句，缺少分号时补全
+func (f *planFile) statement(sql string) {
+	sql = strings.TrimSpace(sql)
+	if sql == "" {
+		return
+	}
+	if !strings.HasSuffix(sql, ";") {
+		sql += ";"
+	}
+	f.statements++
+	f.body.WriteString(sql + "\n")
+}
+
+// blank 写入空行分隔不同对象
+func (f *planFile) blank() {
+	f.body.WriteString("\n")
+}
+
+// content 阶段SQL的完整内容，开头为阶段说明和统计
+func (f *planFile) content() string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("-- %s\n", f.title))
+	content.WriteString(fmt.Sprintf("-- 语句数: %d，警告数: %d\n\n", f.statements, f.warnings))
+	content.WriteString(f.body.String())
+	return content.String()
+}
+
+// write 将阶段SQL写入目录，文件名带顺序编号
+func (f *planFile) write(dir string, number int) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%02d_%s.sql", number, f.stage))
+	if err := os.WriteFile(path, []byte(f.content()), 0644); err != nil {
+		return "", fmt.Errorf("写入迁移脚本 %s 失败: %w", path, err)
+	}
+	return path, nil
+}
+
+// tableConversionWarnings 检查MySQL建表语句中转换到PostgreSQL时被移除或改变语义的定义
+func tableConversionWarnings(mysqlDDL string) []string {
+	var warnings []string
+	for _, line := range strings.Split(mysqlDDL, "\n") {
+		line = strings.TrimSpace(line)
+		if match := rePlanFulltext.FindStringSubmatch(line); match != nil {
+			warnings = append(warnings, fmt.Sprintf("FULLTEXT 索引 %s 将转换为普通索引，全文检索需要改用 tsvector 和 GIN 索引", match[1]))
+			continue
+		}
+		match := reColumnDefinitionLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		column := match[1]
+		if rePlanOnUpdate.MatchString(line) {
+			warnings = append(warnings, fmt.Sprintf("列 %s 的 ON UPDATE CURRENT_TIMESTAMP 已移除，PostgreSQL 中需要触发器维护", column))
+		}
+		if rePlanUnsigned.MatchString(line) {
+			warnings = append(warnings, fmt.Sprintf("列 %s 的 UNSIGNED 已移除，PostgreSQL 不检查非负取值范围", column))
+		}
+		if rePlanVirtual.MatchString(line) {
+			warnings = append(warnings, fmt.Sprintf("列 %s 为 VIRTUAL 生成列，将转换为 STORED 生成列", column))
+		}
+	}
+	if rePlanPartition.MatchString(mysqlDDL) {
+		warnings = append(warnings, "分区定义已移除，目标表为普通表")
+	}
+	return warnings
+}
+
+// Plan 生成迁移脚本：读取MySQL元数据并执行所有转换，将每个阶段的SQL按执行顺序写入编号的 .sql 文件，不连接PostgreSQL
+// 转换失败的对象和转换时被移除的定义以SQL注释的形式写入脚本
+func (m *Manager) Plan(ctx context.Context) error {
+	dir := m.config.Run.PlanDir
+	m.Log("生成迁移脚本到目录 %s，不连接PostgreSQL ...", dir)
+
+	tables, functions, indexes, views, users, tablePrivileges, err := m.getMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建迁移脚本目录失败: %w", err)
+	}
+
+	// 按正常转换流程的顺序生成各阶段脚本
+	preData, postData := m.planSchema(tables, functions, indexes, views, users, tablePrivileges)
+	files := preData
+	if m.config.Conversion.Options.Data {
+		files = append(files, m.planData(tables))
+	}
+	files = append(files, postData...)
+
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("+----------------------------------------------------+------------+------------+")
+		fmt.Printf("| %-50s | %-10s | %-10s |\n", "迁移脚本", "语句数", "警告数")
+		fmt.Println("+----------------------------------------------------+------------+------------+")
+	}
+	for i, file := range files {
+		path, err := file.write(dir, i+1)
+		if err != nil {
+			return err
+		}
+		m.Log("生成迁移脚本 %s，%d 条语句，%d 个警告", path, file.statements, file.warnings)
+		if m.config.Run.ShowConsoleLogs {
+			fmt.Printf("| %-50s | %-10d | %-10d |\n", path, file.statements, file.warnings)
+		}
+	}
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Println("+----------------------------------------------------+------------+------------+")
+	}
+
+	m.Log("迁移脚本生成完成!")
+	return nil
+}
+
+// planSchema 按启用的转换选项生成各阶段脚本，分为数据同步前（建表）和数据同步后（视图、索引、函数、用户、权限等）两部分
+func (m *Manager) planSchema(tables []mysql.TableInfo, functions []mysql.FunctionInfo, indexes []mysql.IndexInfo, views []mysql.ViewInfo, users []mysql.UserInfo, tablePrivileges []mysql.TablePrivInfo) (preData, postData []*planFile) {
+	options := m.config.Conversion.Options
+	if options.TableDDL {
+		preData = append(preData, m.planTables(tables))
+	}
+	if options.View {
+		postData = append(postData, m.planViews(views))
+	}
+	if options.Indexes {
+		postData = append(postData, m.planIndexes(indexes))
+	}
+	if options.Functions {
+		postData = append(postData, m.planFunctions(functions))
+	}
+	if options.Users {
+		postData = append(postData, m.planUsers(users))
+	}
+	if options.TablePrivileges {
+		postData = append(postData, m.planTablePrivileges(tablePrivileges))
+	}
+	if options.FastLoad && options.TableDDL {
+		postData = append(postData, m.planFastLoad())
+	}
+	return preData, postData
+}
+
+// planTables 生成建表脚本，包括表注释和列注释
+func (m *Manager) planTables(tables []mysql.TableInfo) *planFile {
+	file := newPlanFile("tables", "转换表结构")
+	for _, table := range tables {
+		pgTableName := targetTableName(m.config, table.Name)
+		file.comment("表 %s -> %s", table.Name, pgTableName)
+
+		projected, pgResult, err := m.buildTableDDL(table)
+		if err != nil || pgResult == nil {
+			file.warn("转换表 %s 失败: %v，MySQL DDL:\n%s", table.Name, err, table.DDL)
+			file.blank()
+			continue
+		}
+		m.tableColumnNamesMap[table.Name] = pgResult.ColumnNames
+		for _, warning := range tableConversionWarnings(projected.DDL) {
+			file.warn("%s", warning)
+		}
+
+		ddl := pgResult.DDL
+		if m.config.Conversion.Options.FastLoad {
+			ddl = m.createFastLoadTableDDL(pgTableName, pgResult)
+		}
+		if m.config.Conversion.Options.SkipExistingTables {
+			file.comment("skip_existing_tables: 表已存在时跳过创建")
+			ddl = strings.Replace(ddl, " TABLE ", " TABLE IF NOT EXISTS ", 1)
+		} else {
+			file.statement(fmt.Sprintf("DROP TABLE IF EXISTS \"%s\" CASCADE", pgTableName))
+		}
+		file.statement(ddl)
+
+		if pgResult.TableComment != "" {
+			file.statement(fmt.Sprintf("COMMENT ON TABLE \"%s\" IS '%s'", pgTableName, m.processComment(pgResult.TableComment)))
+		}
+		for _, column := range projected.Columns {
+			if column.Comment == "" {
+				continue
+			}
+			columnName, ok := pgResult.ColumnNames[column.Name]
+			if !ok {
+				name := column.Name
+				if m.config.Conversion.Options.LowercaseColumns {
+					name = strings.ToLower(name)
+				}
+				columnName = fmt.Sprintf(`"%s"`, name)
+			}
+			file.statement(fmt.Sprintf("COMMENT ON COLUMN \"%s\".%s IS '%s'", pgTableName, columnName, m.processComment(column.Comment)))
+		}
+		file.blank()
+	}
+	return file
+}
+
+// planData 列出需要同步数据的表，数据不写入脚本
+func (m *Manager) planData(tables []mysql.TableInfo) *planFile {
+	file := newPlanFile("data", "同步表数据（数据不包含在脚本中，按数据大小从大到小同步）")
+	for _, table := range sortTablesBySize(tables) {
+		line := fmt.Sprintf("表 %s -> %s，估计 %d 行，%d 字节", table.Name, targetTableName(m.config, table.Name), table.Size.Rows, table.Size.DataLength)
+		if where := m.config.Conversion.FindTable(table.Name).Filter(); where != "" {
+			line += "，过滤条件: " + where
+		}
+		file.comment("%s", line)
+	}
+	return file
+}
+
+// planViews 生成视图脚本
+func (m *Manager) planViews(views []mysql.ViewInfo) *planFile {
+	file := newPlanFile("views", "转换表视图")
+	for _, view := range views {
+		file.comment("视图 %s", view.ViewName)
+		pgViewDDL, err := ConvertViewDDL(view.ViewName, view.ViewDefinition, m.config.MySQL.Database)
+		if err != nil {
+			file.warn("转换表视图 %s 失败: %v，MySQL 定义:\n%s", view.ViewName, err, view.ViewDefinition)
+		} else {
+			file.statement(pgViewDDL)
+		}
+		file.blank()
+	}
+	return file
+}
+
+// planIndexes 生成索引脚本
+func (m *Manager) planIndexes(indexes []mysql.IndexInfo) *planFile {
+	file := newPlanFile("indexes", "转换表索引")
+	for _, index := range indexes {
+		lowercaseIndexName := strings.ToLower(index.Name)
+		columnNamesMap := m.tableColumnNamesMap[index.Table]
+
+		projected, ok := projectIndex(m.config, index)
+		if !ok {
+			file.warn("索引 %s 引用了不同步的列，跳过创建", lowercaseIndexName)
+			continue
+		}
+		pgDDL, err := ConvertIndexDDL(projected.Table, projected, m.config.Conversion.Options.LowercaseColumns, columnNamesMap)
+		if err != nil {
+			file.warn("转换索引 %s 失败: %v", lowercaseIndexName, err)
+			continue
+		}
+		file.statement(pgDDL)
+	}
+	return file
+}
+
+// planFunctions 生成函数脚本
+func (m *Manager) planFunctions(functions []mysql.FunctionInfo) *planFile {
+	file := newPlanFile("functions", "转换库函数")
+	for _, function := range functions {
+		file.comment("函数 %s", function.Name)
+		pgDDL, err := ConvertFunctionDDL(function)
+		if err != nil {
+			file.warn("转换函数 %s 失败: %v，MySQL DDL:\n%s", function.Name, err, function.DDL)
+		} else {
+			file.statement(pgDDL)
+		}
+		file.blank()
+	}
+	return file
+}
+
+// planUsers 生成用户及权限脚本
+func (m *Manager) planUsers(users []mysql.UserInfo) *planFile {
+	file := newPlanFile("users", "转换库用户")
+	for _, user := range users {
+		pgDDLs, err := ConvertUserDDL(user)
+		if err != nil {
+			file.warn("转换用户 %s 失败: %v", user.Name, err)
+			continue
+		}
+		if len(pgDDLs) == 0 {
+			continue
+		}
+		file.comment("用户 %s", user.Name)
+		for _, ddl := range pgDDLs {
+			file.statement(ddl)
+		}
+		file.blank()
+	}
+	return file
+}
+
+// planTablePrivileges 生成表权限脚本
+func (m *Manager) planTablePrivileges(tablePrivileges []mysql.TablePrivInfo) *planFile {
+	file := newPlanFile("table_privileges", "转换表权限")
+	for _, tablePriv := range tablePrivileges {
+		pgDDLs, err := ConvertTablePrivilegeDDL(tablePriv)
+		if err != nil {
+			file.warn("转换表权限失败: %v", err)
+			continue
+		}
+		for _, ddl := range pgDDLs {
+			file.statement(ddl)
+		}
+	}
+	return file
+}
+
+// planFastLoad 生成快速加载收尾脚本：添加主键、补建CHECK约束、SET LOGGED并ANALYZE
+// 索引脚本同样在数据导入后执行，执行顺序为导入数据、索引、本脚本
+func (m *Manager) planFastLoad() *planFile {
+	file := newPlanFile("fast_load", "快速加载收尾（添加主键、补建约束、SET LOGGED、ANALYZE）")
+	for _, table := range m.fastLoadTables {
+		if table.primaryKey != "" {
+			file.statement(fmt.Sprintf(`ALTER TABLE "%s" ADD %s`, table.name, table.primaryKey))
+		}
+		for _, constraint := range table.constraints {
+			file.statement(fmt.Sprintf(`ALTER TABLE "%s" ADD %s`, table.name, constraint))
+		}
+		file.statement(fmt.Sprintf(`ALTER TABLE "%s" SET LOGGED`, table.name))
+		file.statement(fmt.Sprintf(`ANALYZE "%s"`, table.name))
+	}
+	return file
+}
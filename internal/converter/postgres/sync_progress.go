@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+// sortTablesBySize 按数据大小从大到小排序表，使最大的表最先开始同步，返回排序后的副本
+func sortTablesBySize(tables []mysql.TableInfo) []mysql.TableInfo {
+	sorted := make([]mysql.TableInfo, len(tables))
+	copy(sorted, tables)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Size.DataLength > sorted[j].Size.DataLength
+	})
+	return sorted
+}
+
+// DataProgress 按数据量（字节）统计数据同步的整体进度和预计剩余时间
+// 表的数据量来自 information_schema.TABLES 的统计信息，没有统计信息时按表数量统计
+type DataProgress struct {
+	mutex      sync.Mutex
+	start      time.Time
+	totalBytes int64
+	sizes      map[string]mysql.TableSize
+	done       map[string]int64
+}
+
+// NewDataProgress 创建数据同步进度
+func NewDataProgress(tables []mysql.TableInfo) *DataProgress {
+	p := &DataProgress{
+		start: time.Now(),
+		sizes: make(map[string]mysql.TableSize, len(tables)),
+		done:  make(map[string]int64, len(tables)),
+	}
+	for _, table := range tables {
+		p.sizes[table.Name] = table.Size
+		p.totalBytes += table.Size.DataLength
+	}
+	return p
+}
+
+// Update 根据已同步的行数更新表的进度
+func (p *DataProgress) Update(tableName string, processedRows int64) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	size := p.sizes[tableName]
+	bytes := processedRows * size.AvgRowLength
+	if bytes > size.DataLength {
+		bytes = size.DataLength
+	}
+	p.done[tableName] = bytes
+}
+
+// Finish 标记表同步结束（成功、跳过或失败）
+func (p *DataProgress) Finish(tableName string) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.done[tableName] = p.sizes[tableName].DataLength
+}
+
+// Percent 整体进度百分比，没有表大小统计信息时按已完成的任务数计算
+func (p *DataProgress) Percent(completedTasks, totalTasks int) float64 {
+	if p != nil {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		if p.totalBytes > 0 {
+			return float64(p.doneBytes()) / float64(p.totalBytes) * 100
+		}
+	}
+	if totalTasks == 0 {
+		return 0
+	}
+	return float64(completedTasks) / float64(totalTasks) * 100
+}
+
+// ETA 按已同步的数据量估算剩余时间，无法估算时返回空字符串
+func (p *DataProgress) ETA() string {
+	if p == nil {
+		return ""
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	done := p.doneBytes()
+	if p.totalBytes == 0 || done == 0 {
+		return ""
+	}
+	elapsed := time.Since(p.start)
+	remaining := time.Duration(float64(elapsed) * float64(p.totalBytes-done) / float64(done))
+	return fmt.Sprintf("，预计剩余 %s", remaining.Round(time.Second))
+}
+
+// doneBytes 已同步的数据量，调用方需持有锁
+func (p *DataProgress) doneBytes() int64 {
+	var done int64
+	for _, bytes := range p.done {
+		done += bytes
+	}
+	return done
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+)
+
+// rejectTableName 保存隔离行的PostgreSQL表
+const rejectTableName = "mysql2pg_rejects"
+
+// RejectLog 写入失败被隔离的行的记录，所有表的同步协程共享
+type RejectLog struct {
+	mode     string
+	filePath string
+
+	prepareOnce sync.Once
+	prepareErr  error
+
+	mutex  sync.Mutex
+	file   *os.File
+	Counts map[string]int64 // 各表被隔离的行数
+}
+
+// rejectRecord 隔离文件中的一行记录
+type rejectRecord struct {
+	Table      string                 `json:"table"`
+	PrimaryKey string                 `json:"primary_key,omitempty"`
+	Error      string                 `json:"error"`
+	Row        map[string]interface{} `json:"row"`
+	RejectedAt time.Time              `json:"rejected_at"`
+}
+
+// NewRejectLog 根据配置创建隔离行记录，未启用坏行隔离时返回nil
+func NewRejectLog(cfg *config.Config) *RejectLog {
+	if !cfg.Conversion.Options.IsRejectEnabled() {
+		return nil
+	}
+	return &RejectLog{
+		mode:     cfg.Conversion.Options.RejectMode,
+		filePath: cfg.Conversion.Options.RejectFile,
+		Counts:   make(map[string]int64),
+	}
+}
+
+// Prepare 创建隔离行的保存位置，只在第一次调用时执行
+func (r *RejectLog) Prepare(ctx context.Context, postgresConn *postgres.Connection) error {
+	if r == nil {
+		return nil
+	}
+	r.prepareOnce.Do(func() {
+		if r.mode == config.RejectModeTable {
+			ddl := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (
+	id BIGSERIAL PRIMARY KEY,
+	table_name TEXT NOT NULL,
+	primary_key TEXT,
+	error TEXT NOT NULL,
+	row_data TEXT,
+	rejected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`, rejectTableName)
+			if err := postgresConn.ExecuteDDL(ctx, ddl); err != nil {
+				r.prepareErr = fmt.Errorf("创建隔离行表 %s 失败: %w", rejectTableName, err)
+			}
+			return
+		}
+
+		file, err := os.OpenFile(r.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			r.prepareErr = fmt.Errorf("打开隔离行文件失败: %w", err)
+			return
+		}
+		r.file = file
+	})
+	return r.prepareErr
+}
+
+// Handler 获取指定表的隔离行处理函数，未启用坏行隔离时返回nil
+// reject_mode 为 table 时隔离行在当前批次的事务 tx 中写入，与其余数据一同提交
+func (r *RejectLog) Handler(ctx context.Context, tx pgx.Tx, tableName string, columns []string) func(row postgres.RejectedRow) error {
+	if r == nil {
+		return nil
+	}
+	return func(row postgres.RejectedRow) error {
+		data := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			data[col] = row.Values[i]
+		}
+
+		if r.mode == config.RejectModeTable {
+			rowData, err := json.Marshal(data)
+			if err != nil {
+				return fmt.Errorf("序列化隔离行失败: %w", err)
+			}
+			query := fmt.Sprintf(`INSERT INTO "%s" (table_name, primary_key, error, row_data) VALUES ($1, $2, $3, $4)`, rejectTableName)
+			if _, err := tx.Exec(ctx, query, tableName, row.PrimaryKey, row.Error, string(rowData)); err != nil {
+				return fmt.Errorf("写入隔离行表 %s 失败: %w", rejectTableName, err)
+			}
+			r.mutex.Lock()
+			r.Counts[tableName]++
+			r.mutex.Unlock()
+			return nil
+		}
+
+		line, err := json.Marshal(rejectRecord{
+			Table:      tableName,
+			PrimaryKey: row.PrimaryKey,
+			Error:      row.Error,
+			Row:        data,
+			RejectedAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("序列化隔离行失败: %w", err)
+		}
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		if _, err := r.file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("写入隔离行文件失败: %w", err)
+		}
+		r.Counts[tableName]++
+		return nil
+	}
+}
+
+// Count 获取表被隔离的行数
+func (r *RejectLog) Count(tableName string) int64 {
+	if r == nil {
+		return 0
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.Counts[tableName]
+}
+
+// Location 隔离行的保存位置
+func (r *RejectLog) Location() string {
+	if r.mode == config.RejectModeTable {
+		return rejectTableName + " 表"
+	}
+	return r.filePath
+}
+
+// Close 关闭隔离行文件
+func (r *RejectLog) Close() error {
+	if r == nil || r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/mysql"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+	"github.com/yourusername/mysql2pg/internal/throttle"
+)
+
+// RepairResult 单表修复结果
+type RepairResult struct {
+	Before         *ChecksumResult // 修复前的校验结果
+	After          *ChecksumResult // 修复后的校验结果，未执行修复时与 Before 相同
+	RepairedRanges int             // 修复的主键范围数
+	CopiedRows     int64           // 重新复制的行数
+}
+
+// RepairTableData 比较表内容校验和，只对不一致的主键范围进行修复，然后重新校验
+// 每个范围在一个事务中先删除PostgreSQL中该范围的数据，再从MySQL重新复制满足 filter 条件的数据
+func RepairTableData(ctx context.Context, mysqlConn *mysql.Connection, postgresConn *postgres.Connection, cfg *config.Config, log func(format string, args ...interface{}), limiter *throttle.Limiter, tableName, pgTableName string, columns []string, columnTypes map[string]string, filter string) (*RepairResult, error) {
+	chunkSize := cfg.Conversion.Limits.ChecksumChunkSize
+	// 脱敏列两端内容必然不同，不参与校验和比较
+	checksumColumns := unmaskedColumns(cfg, tableName, columns)
+	uuids := uuidColumns(cfg, tableName, columnTypes)
+	jsonbs := jsonbColumns(cfg, tableName, columnTypes)
+
+	before, err := ValidateTableChecksum(ctx, mysqlConn, postgresConn, tableName, pgTableName, checksumColumns, columnTypes, uuids, jsonbs, filter, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	result := &RepairResult{Before: before, After: before}
+
+	if !before.Mismatched {
+		return result, nil
+	}
+	if before.WholeTable {
+		log("警告: 表 %s 只能整表比较（%s），无法按范围修复，请重新同步整表", tableName, before.WholeTableReason)
+		return result, nil
+	}
+
+	for _, r := range before.MismatchedRanges {
+		log("修复表 %s 主键范围 %s", tableName, r.String())
+		copied, err := repairRange(ctx, mysqlConn, postgresConn, cfg, limiter, tableName, pgTableName, before.PrimaryKey, columns, columnTypes, uuids, filter, r)
+		if err != nil {
+			return nil, fmt.Errorf("修复表 %s 主键范围 [%s, %s] 失败: %w", tableName, r.Start, r.End, err)
+		}
+		result.RepairedRanges++
+		result.CopiedRows += copied
+	}
+
+	after, err := ValidateTableChecksum(ctx, mysqlConn, postgresConn, tableName, pgTableName, checksumColumns, columnTypes, uuids, jsonbs, filter, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	result.After = after
+
+	return result, nil
+}
+
+// repairRange 在一个事务中删除并重新复制一个主键范围的数据
+func repairRange(ctx context.Context, mysqlConn *mysql.Connection, postgresConn *postgres.Connection, cfg *config.Config, limiter *throttle.Limiter, tableName, pgTableName, primaryKey string, columns []string, columnTypes map[string]string, uuids map[string]bool, filter string, r ChecksumRange) (int64, error) {
+	batchSize := cfg.Conversion.Limits.MaxRowsPerBatch
+	if batchSize <= 0 {
+		batchSize = 10000 // 默认值
+	}
+	batchInsertSize := cfg.Conversion.Limits.BatchInsertSize
+	if batchInsertSize <= 0 {
+		batchInsertSize = 10000 // 默认值
+	}
+
+	tx, err := postgresConn.BeginTransaction(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("开始事务失败: %w", err)
+	}
+	// 运行被取消时 ctx 已失效，使用 context.Background() 回滚，与其他写入事务一致
+	defer tx.Rollback(context.Background())
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM "%s" WHERE "%s" BETWEEN $1 AND $2`, pgTableName, strings.ToLower(primaryKey))
+	lower, upper := postgresKeyBounds(r.Start, r.End)
+	if _, err := tx.Exec(ctx, deleteQuery, lower, upper); err != nil {
+		return 0, fmt.Errorf("删除PostgreSQL数据失败: %w", err)
+	}
+
+	// 重新复制的数据同样需要脱敏
+	masker, err := postgres.NewColumnMasker(&cfg.Conversion.Masking, tableName, columns, columnTypes)
+	if err != nil {
+		return 0, err
+	}
+	converter, err := postgresConn.NewRowConverter(ctx, pgTableName, columns, columnTypes, uuids, &cfg.Conversion.InvalidData)
+	if err != nil {
+		return 0, err
+	}
+	rangeFilter := combineFilters(filter, fmt.Sprintf("`%s` BETWEEN ? AND ?", primaryKey))
+	var lastValue interface{}
+	var copied int64
+	for {
+		rows, err := mysqlConn.GetTableDataWithPagination(ctx, tableName, columns, primaryKey, lastValue, batchSize, rangeFilter, r.Start.Arg(), r.End.Arg())
+		if err != nil {
+			return 0, err
+		}
+
+		currentBatchSize, currentLastValue, err := postgresConn.BatchInsertDataWithTransactionAndGetLastValue(ctx, tx, pgTableName, columns, converter, batchInsertSize, primaryKey, nil, masker, limiter, rows)
+		rows.Close()
+		if err != nil {
+			return 0, err
+		}
+
+		copied += int64(currentBatchSize)
+		if currentBatchSize < batchSize {
+			break
+		}
+		lastValue = currentLastValue
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return copied, nil
+}
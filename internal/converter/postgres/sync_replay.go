@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+// 回放查询的检查结果
+const (
+	ReplayOK     = "ok"     // 转换后的语句可以在PostgreSQL中执行
+	ReplayFailed = "failed" // 转换后的语句在PostgreSQL中报错
+)
+
+// 检查语句的方式
+const (
+	ReplayExplain = "explain" // 执行 EXPLAIN，不执行语句本身
+	ReplayPrepare = "prepare" // 只准备语句，用于带参数占位符的语句
+)
+
+// reReplayable 可以回放的语句：查询和数据修改语句
+var reReplayable = regexp.MustCompile(`(?i)^[\s(]*(select|insert|update|delete|replace|with)\b`)
+
+// ReplayedQuery 一个查询指纹的回放结果
+type ReplayedQuery struct {
+	Fingerprint string   `json:"fingerprint"`
+	Count       int      `json:"count"`  // 在日志中出现的次数
+	Sample      string   `json:"sample"` // 日志中第一次出现时的语句
+	SQL         string   `json:"sql"`    // 转换后在PostgreSQL中检查的语句
+	Method      string   `json:"method"`
+	Status      string   `json:"status"`
+	Error       string   `json:"error,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// ReplayResult 查询日志的回放结果
+type ReplayResult struct {
+	Log              string          `json:"log"`
+	Statements       int             `json:"statements"`        // 日志中的语句数
+	Skipped          int             `json:"skipped"`           // 不回放的语句数，如 SET、SHOW、BEGIN
+	Executions       int             `json:"executions"`        // 回放的语句数
+	FailedExecutions int             `json:"failed_executions"` // 失败的指纹对应的语句数
+	Failed           int             `json:"failed"`            // 失败的指纹数
+	Queries          []ReplayedQuery `json:"queries"`
+}
+
+// Replay 读取MySQL通用查询日志或慢查询日志，按指纹去重后用SQL转换规则转换，并在PostgreSQL中检查每个指纹的示例语句
+// 默认执行 EXPLAIN，prepare 为true或语句带 ? 占位符时只准备语句；都在回滚的事务中进行，不修改数据
+func (m *Manager) Replay(ctx context.Context, logPath string, prepare bool) (*ReplayResult, error) {
+	m.Log("回放查询日志 %s ...", logPath)
+
+	result := &ReplayResult{Log: logPath, Queries: []ReplayedQuery{}}
+	queries := make(map[string]*ReplayedQuery)
+	err := mysql.ScanQueryLogFile(logPath, func(query string) error {
+		result.Statements++
+		if !reReplayable.MatchString(query) {
+			result.Skipped++
+			return nil
+		}
+		result.Executions++
+		fingerprint := mysql.FingerprintQuery(query)
+		if q := queries[fingerprint]; q != nil {
+			q.Count++
+			return nil
+		}
+		queries[fingerprint] = &ReplayedQuery{Fingerprint: fingerprint, Count: 1, Sample: query}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取查询日志 %s 失败: %w", logPath, err)
+	}
+
+	for _, q := range queries {
+		result.Queries = append(result.Queries, *q)
+	}
+	// 出现次数多的在前
+	sort.Slice(result.Queries, func(i, j int) bool {
+		if result.Queries[i].Count != result.Queries[j].Count {
+			return result.Queries[i].Count > result.Queries[j].Count
+		}
+		return result.Queries[i].Fingerprint < result.Queries[j].Fingerprint
+	})
+
+	for i := range result.Queries {
+		m.replayQuery(ctx, &result.Queries[i], prepare)
+		if result.Queries[i].Status == ReplayFailed {
+			result.Failed++
+			result.FailedExecutions += result.Queries[i].Count
+		}
+	}
+
+	m.Log("回放完成，%d 条语句，%d 个查询指纹，%d 个失败", result.Executions, len(result.Queries), result.Failed)
+	return result, nil
+}
+
+// replayQuery 转换一个指纹的示例语句并在PostgreSQL中检查
+func (m *Manager) replayQuery(ctx context.Context, q *ReplayedQuery, prepare bool) {
+	sql, warnings, err := TranslateSQL(q.Sample, m.config.MySQL.Database, m.config.Conversion.Options.LowercaseColumns)
+	if err != nil {
+		q.Status, q.Error = ReplayFailed, err.Error()
+		return
+	}
+	q.Warnings = warnings
+
+	// ? 占位符改为 $n，带参数的语句无法 EXPLAIN
+	sql, params := numberPlaceholders(sql)
+	q.SQL = sql
+	q.Method = ReplayExplain
+	if prepare || params > 0 {
+		q.Method = ReplayPrepare
+	}
+
+	if err := m.postgresConn.CheckQuery(ctx, sql, q.Method == ReplayExplain); err != nil {
+		q.Status, q.Error = ReplayFailed, err.Error()
+		return
+	}
+	q.Status = ReplayOK
+}
+
+// numberPlaceholders 将字符串常量以外的 ? 占位符依次替换为 $1、$2 ...，返回替换后的语句和参数个数
+func numberPlaceholders(sql string) (string, int) {
+	masked, literals := maskStringLiterals(sql)
+	if !strings.Contains(masked, "?") {
+		return sql, 0
+	}
+	var b strings.Builder
+	params := 0
+	for i := 0; i < len(masked); i++ {
+		if masked[i] == '?' {
+			params++
+			fmt.Fprintf(&b, "$%d", params)
+			continue
+		}
+		b.WriteByte(masked[i])
+	}
+	return unmaskStringLiterals(b.String(), literals), params
+}
+
+// WriteText 以文本形式输出回放结果，失败的指纹在前，附错误信息
+func (r *ReplayResult) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "查询日志 %s: %d 条语句，回放 %d 条，跳过 %d 条，%d 个查询指纹\n", r.Log, r.Statements, r.Executions, r.Skipped, len(r.Queries))
+	for _, status := range []string{ReplayFailed, ReplayOK} {
+		for _, q := range r.Queries {
+			if q.Status != status {
+				continue
+			}
+			fmt.Fprintf(w, "%-6s %6d 次  %s\n", q.Status, q.Count, truncateReplayText(q.Fingerprint, 160))
+			if q.Status == ReplayFailed {
+				fmt.Fprintf(w, "       错误: %s\n", q.Error)
+				fmt.Fprintf(w, "       语句: %s\n", truncateReplayText(q.SQL, 300))
+			}
+			for _, warning := range q.Warnings {
+				fmt.Fprintf(w, "       警告: %s\n", warning)
+			}
+		}
+	}
+	if r.Executions > 0 {
+		fmt.Fprintf(w, "共 %d 个查询指纹失败，占回放语句的 %.1f%%\n", r.Failed, float64(r.FailedExecutions)*100/float64(r.Executions))
+	}
+}
+
+// truncateReplayText 截断过长的语句，便于在控制台中查看
+func truncateReplayText(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return strings.ToValidUTF8(s[:limit], "") + " ..."
+}
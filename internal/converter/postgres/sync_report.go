@@ -0,0 +1,493 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+)
+
+// 运行报告中对象和表的状态
+const (
+	ReportConverted = "converted" // 对象已在PostgreSQL中创建
+	ReportSynced    = "synced"    // 表数据已同步
+	ReportSkipped   = "skipped"   // 按配置或因已存在而跳过
+	ReportFailed    = "failed"
+)
+
+// reportFileName 运行报告的文件名（不含扩展名），每次运行覆盖上次的报告
+const reportFileName = "mysql2pg-report"
+
+// ReportStage 运行报告中的一个转换阶段
+type ReportStage struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Seconds    float64   `json:"seconds"`
+	Objects    int       `json:"objects"`
+}
+
+// ReportObject 运行报告中的一个数据库对象：表结构、视图、函数、索引、用户或表权限
+type ReportObject struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"` // 跳过的原因
+	Error  string `json:"error,omitempty"`
+	SQL    string `json:"sql,omitempty"` // 生成的PostgreSQL语句
+}
+
+// ReportTable 运行报告中一个表的数据同步结果
+type ReportTable struct {
+	Name          string  `json:"name"`
+	Target        string  `json:"target"`
+	Status        string  `json:"status"`
+	Rows          int64   `json:"rows"`
+	Seconds       float64 `json:"seconds"`
+	RowsPerSecond float64 `json:"rows_per_second"`
+	Validation    string  `json:"validation,omitempty"` // 校验结果，如 数据一致、跳过验证
+	Note          string  `json:"note,omitempty"`       // 跳过的原因
+	Error         string  `json:"error,omitempty"`
+}
+
+// ReportMismatch 运行报告中数据校验不一致的表
+type ReportMismatch struct {
+	Table            string   `json:"table"`
+	MySQLRows        int64    `json:"mysql_rows"`
+	PostgresRows     int64    `json:"postgres_rows"`
+	MismatchedRanges []string `json:"mismatched_ranges,omitempty"` // 校验和不一致的主键范围
+	WholeTable       string   `json:"whole_table,omitempty"`       // 只能整表比较的原因，此时没有主键范围
+}
+
+// ReportValidation 运行报告中的数据校验结果
+type ReportValidation struct {
+	Enabled    bool             `json:"enabled"`
+	Mode       string           `json:"mode,omitempty"`
+	Mismatches []ReportMismatch `json:"mismatches"`
+}
+
+// RunReport 一次运行的结果，转换和同步过程中记录，运行结束后写入报告文件
+type RunReport struct {
+	mutex sync.Mutex
+
+	Database   string           `json:"database"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+	Seconds    float64          `json:"seconds"`
+	Status     string           `json:"status"` // ok、failed、cancelled（运行被中断）或 mismatch（数据校验不一致）
+	Error      string           `json:"error,omitempty"`
+	Stages     []ReportStage    `json:"stages"`
+	Objects    []ReportObject   `json:"objects"`
+	Tables     []ReportTable    `json:"tables"`
+	Validation ReportValidation `json:"validation"`
+	// 快速加载模式下运行结束时仍为UNLOGGED或缺少主键的表，需要重新运行转换
+	FastLoadPending []string `json:"fast_load_pending,omitempty"`
+}
+
+// NewRunReport 创建运行报告，记录开始时间
+func NewRunReport(cfg *config.Config) *RunReport {
+	return &RunReport{
+		Database:  cfg.MySQL.Database,
+		StartedAt: time.Now(),
+		Stages:    []ReportStage{},
+		Objects:   []ReportObject{},
+		Tables:    []ReportTable{},
+		Validation: ReportValidation{
+			Enabled:    cfg.Conversion.Options.ValidateData,
+			Mismatches: []ReportMismatch{},
+		},
+	}
+}
+
+// RecordObject 记录一个对象的转换结果，err 不为nil时状态为失败
+func (r *RunReport) RecordObject(objectType, name, status, sql string, err error) {
+	if r == nil {
+		return
+	}
+	object := ReportObject{Type: objectType, Name: name, Status: status, SQL: sql}
+	if err != nil {
+		object.Status, object.Error = ReportFailed, err.Error()
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Objects = append(r.Objects, object)
+}
+
+// SkipObject 记录一个跳过的对象及跳过的原因
+func (r *RunReport) SkipObject(objectType, name, note string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Objects = append(r.Objects, ReportObject{Type: objectType, Name: name, Status: ReportSkipped, Note: note})
+}
+
+// SetFastLoadPending 记录未完成快速加载收尾的表
+func (r *RunReport) SetFastLoadPending(tables []string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.FastLoadPending = tables
+}
+
+// SkipTable 记录一个跳过数据同步的表及跳过的原因
+func (r *RunReport) SkipTable(name, target, note string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Tables = append(r.Tables, ReportTable{Name: name, Target: target, Status: ReportSkipped, Note: note})
+}
+
+// RecordTable 记录一个表的数据同步结果，err 不为nil时状态为失败
+func (r *RunReport) RecordTable(name, target, status string, rows int64, elapsed time.Duration, validation string, err error) {
+	if r == nil {
+		return
+	}
+	table := ReportTable{Name: name, Target: target, Status: status, Rows: rows, Seconds: elapsed.Seconds(), Validation: validation}
+	if table.Seconds > 0 {
+		table.RowsPerSecond = float64(rows) / table.Seconds
+	}
+	if err != nil {
+		table.Status, table.Error = ReportFailed, err.Error()
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Tables = append(r.Tables, table)
+}
+
+// Finish 结束运行报告：记录结束时间、各阶段耗时和数据校验不一致的表
+func (r *RunReport) Finish(cfg *config.Config, stages []ConversionStageStat, inconsistentTables []TableDataInconsistency, runErr error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.FinishedAt = time.Now()
+	r.Seconds = r.FinishedAt.Sub(r.StartedAt).Seconds()
+	for _, stat := range stages {
+		r.Stages = append(r.Stages, ReportStage{
+			Name:       stat.StageName,
+			StartedAt:  stat.StartTime,
+			FinishedAt: stat.EndTime,
+			Seconds:    stat.EndTime.Sub(stat.StartTime).Seconds(),
+			Objects:    stat.ObjectCount,
+		})
+	}
+	if r.Validation.Enabled {
+		r.Validation.Mode = cfg.Conversion.Options.ValidateMode
+	}
+	for _, table := range inconsistentTables {
+		mismatch := ReportMismatch{Table: table.TableName, MySQLRows: table.MySQLRowCount, PostgresRows: table.PostgresRowCount}
+		if table.Checksum != nil {
+			mismatch.WholeTable = table.Checksum.WholeTableReason
+			for _, rng := range table.Checksum.MismatchedRanges {
+				mismatch.MismatchedRanges = append(mismatch.MismatchedRanges, rng.String())
+			}
+		}
+		r.Validation.Mismatches = append(r.Validation.Mismatches, mismatch)
+	}
+
+	// 对象和表按完成顺序记录，报告中按名称排序便于比较两次运行
+	sort.SliceStable(r.Objects, func(i, j int) bool {
+		if r.Objects[i].Type != r.Objects[j].Type {
+			return reportObjectOrder(r.Objects[i].Type) < reportObjectOrder(r.Objects[j].Type)
+		}
+		return r.Objects[i].Name < r.Objects[j].Name
+	})
+	sort.SliceStable(r.Tables, func(i, j int) bool { return r.Tables[i].Name < r.Tables[j].Name })
+
+	switch {
+	case errors.Is(runErr, context.Canceled):
+		r.Status, r.Error = "cancelled", runErr.Error()
+	case runErr != nil:
+		r.Status, r.Error = ReportFailed, runErr.Error()
+	case len(r.Validation.Mismatches) > 0:
+		r.Status = "mismatch"
+	default:
+		r.Status = "ok"
+	}
+}
+
+// reportObjectTypes 报告中对象类型的顺序，与转换阶段的顺序一致
+var reportObjectTypes = []string{"table", "view", "function", "index", "user", "privilege"}
+
+// reportObjectOrder 对象类型在报告中的顺序
+func reportObjectOrder(objectType string) int {
+	for i, t := range reportObjectTypes {
+		if t == objectType {
+			return i
+		}
+	}
+	return len(reportObjectTypes)
+}
+
+// count 统计指定状态的对象数
+func (r *RunReport) count(status string) int {
+	n := 0
+	for _, object := range r.Objects {
+		if object.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteJSON 以JSON格式输出运行报告
+func (r *RunReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("生成运行报告失败: %w", err)
+	}
+	return nil
+}
+
+// junitTestSuites JUnit XML 的根元素
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite 一类对象对应一个测试套件
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase 一个对象或表对应一个测试用例
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure 失败的测试用例
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSkipped 跳过的测试用例
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// add 添加测试用例并更新套件的统计
+func (s *junitTestSuite) add(c junitTestCase) {
+	s.Tests++
+	if c.Failure != nil {
+		s.Failures++
+	}
+	if c.Skipped != nil {
+		s.Skipped++
+	}
+	s.Cases = append(s.Cases, c)
+}
+
+// WriteJUnit 以JUnit XML格式输出运行报告：每类对象、数据同步和数据校验各为一个测试套件，
+// 失败的对象、表和校验不一致的表为失败的测试用例，CI可直接展示
+func (r *RunReport) WriteJUnit(w io.Writer) error {
+	root := junitTestSuites{Name: "mysql2pg", Time: formatJUnitSeconds(r.Seconds)}
+
+	for _, objectType := range reportObjectTypes {
+		suite := junitTestSuite{Name: "mysql2pg." + objectType, Time: "0"}
+		for _, object := range r.Objects {
+			if object.Type != objectType {
+				continue
+			}
+			c := junitTestCase{Name: object.Name, ClassName: suite.Name, Time: "0", SystemOut: object.SQL}
+			switch object.Status {
+			case ReportFailed:
+				c.Failure = &junitFailure{Message: object.Error, Text: object.Error}
+			case ReportSkipped:
+				c.Skipped = &junitSkipped{Message: object.Note}
+			}
+			suite.add(c)
+		}
+		if suite.Tests > 0 {
+			root.Suites = append(root.Suites, suite)
+		}
+	}
+
+	if len(r.Tables) > 0 {
+		suite := junitTestSuite{Name: "mysql2pg.data"}
+		var seconds float64
+		for _, table := range r.Tables {
+			seconds += table.Seconds
+			c := junitTestCase{Name: table.Name, ClassName: suite.Name, Time: formatJUnitSeconds(table.Seconds)}
+			switch table.Status {
+			case ReportFailed:
+				c.Failure = &junitFailure{Message: table.Error, Text: table.Error}
+			case ReportSkipped:
+				c.Skipped = &junitSkipped{Message: table.Note}
+			default:
+				c.SystemOut = fmt.Sprintf("%d 行，%.0f 行/秒，%s", table.Rows, table.RowsPerSecond, table.Validation)
+			}
+			suite.add(c)
+		}
+		suite.Time = formatJUnitSeconds(seconds)
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if r.Validation.Enabled {
+		suite := junitTestSuite{Name: "mysql2pg.validation", Time: "0"}
+		mismatches := make(map[string]ReportMismatch)
+		for _, mismatch := range r.Validation.Mismatches {
+			mismatches[mismatch.Table] = mismatch
+		}
+		for _, table := range r.Tables {
+			if table.Status != ReportSynced {
+				continue
+			}
+			c := junitTestCase{Name: table.Name, ClassName: suite.Name, Time: "0"}
+			if mismatch, ok := mismatches[table.Name]; ok {
+				message := fmt.Sprintf("数据不一致: MySQL %d 行，PostgreSQL %d 行", mismatch.MySQLRows, mismatch.PostgresRows)
+				text := message
+				if mismatch.WholeTable != "" {
+					text += "\n仅整表比较: " + mismatch.WholeTable
+				}
+				for _, rng := range mismatch.MismatchedRanges {
+					text += "\n不一致的主键范围: " + rng
+				}
+				c.Failure = &junitFailure{Message: message, Text: text}
+			}
+			suite.add(c)
+		}
+		if suite.Tests > 0 {
+			root.Suites = append(root.Suites, suite)
+		}
+	}
+
+	if len(r.FastLoadPending) > 0 {
+		suite := junitTestSuite{Name: "mysql2pg.fast_load", Time: "0"}
+		for _, table := range r.FastLoadPending {
+			message := "表仍为UNLOGGED或未创建主键、索引和约束，请重新运行转换"
+			suite.add(junitTestCase{Name: table, ClassName: suite.Name, Time: "0", Failure: &junitFailure{Message: message, Text: message}})
+		}
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if len(root.Suites) > 0 {
+		root.Suites[0].Timestamp = r.StartedAt.Format("2006-01-02T15:04:05")
+	}
+	// 运行失败但没有对应的对象时（如获取元数据失败），单独记录一个失败的测试用例
+	if r.Error != "" && r.count(ReportFailed) == 0 && !r.hasFailedTable() {
+		suite := junitTestSuite{Name: "mysql2pg.run", Time: formatJUnitSeconds(r.Seconds)}
+		suite.add(junitTestCase{Name: "run", ClassName: suite.Name, Time: formatJUnitSeconds(r.Seconds), Failure: &junitFailure{Message: r.Error, Text: r.Error}})
+		root.Suites = append(root.Suites, suite)
+	}
+	for _, suite := range root.Suites {
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("生成运行报告失败: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("生成运行报告失败: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("生成运行报告失败: %w", err)
+	}
+	return nil
+}
+
+// hasFailedTable 是否有同步失败的表
+func (r *RunReport) hasFailedTable() bool {
+	for _, table := range r.Tables {
+		if table.Status == ReportFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// formatJUnitSeconds JUnit XML 中的耗时，单位为秒
+func formatJUnitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// WriteFiles 按配置的格式将运行报告写入报告目录，返回写入的文件
+func (r *RunReport) WriteFiles(cfg *config.Config) ([]string, error) {
+	if err := os.MkdirAll(cfg.Run.ReportDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建运行报告目录失败: %w", err)
+	}
+
+	var files []string
+	for _, format := range cfg.Run.ReportFormats {
+		var ext string
+		var write func(io.Writer) error
+		switch format {
+		case config.ReportFormatJSON:
+			ext, write = ".json", r.WriteJSON
+		case config.ReportFormatJUnit:
+			ext, write = ".xml", r.WriteJUnit
+		case config.ReportFormatHTML:
+			ext, write = ".html", r.WriteHTML
+		default:
+			continue
+		}
+
+		path := filepath.Join(cfg.Run.ReportDir, reportFileName+ext)
+		file, err := os.Create(path)
+		if err != nil {
+			return files, fmt.Errorf("创建运行报告文件失败: %w", err)
+		}
+		err = write(file)
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("写入运行报告文件失败: %w", closeErr)
+		}
+		if err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// writeReport 结束运行报告并写入报告目录，写入失败只记录错误，不影响运行结果
+func (m *Manager) writeReport(runErr error) {
+	m.report.Finish(m.config, m.conversionStats, m.inconsistentTables, runErr)
+	files, err := m.report.WriteFiles(m.config)
+	if err != nil {
+		m.logError(fmt.Sprintf("写入运行报告失败: %v", err))
+	}
+	if len(files) == 0 {
+		return
+	}
+	if m.config.Run.ShowConsoleLogs {
+		fmt.Printf("运行报告: %s\n", strings.Join(files, ", "))
+	}
+	m.Log("运行报告已写入 %s", strings.Join(files, ", "))
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// runReportTemplate 运行报告的HTML模板，样式内嵌，单个文件即可查看
+var runReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatAssessDuration,
+	"count":    func(r *RunReport, status string) int { return r.count(status) },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>MySQL2PG 运行报告</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.25em; margin-top: 2em; border-bottom: 1px solid #ddd; padding-bottom: .3em; }
+table { border-collapse: collapse; margin: .5em 0; font-size: .9em; }
+th, td { border: 1px solid #ddd; padding: .35em .7em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; } td.num { text-align: right; }
+.ok, .converted, .synced { color: #2e7d32; } .skipped, .mismatch, .cancelled { color: #ef6c00; } .failed { color: #c62828; }
+.summary td { min-width: 8em; } .muted { color: #777; }
+details pre { white-space: pre-wrap; max-width: 60em; margin: .3em 0; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>MySQL2PG 运行报告</h1>
+<p class="muted">数据库: {{.Database}}，开始时间: {{.StartedAt.Format "2006-01-02 15:04:05"}}，结束时间: {{.FinishedAt.Format "2006-01-02 15:04:05"}}</p>
+
+<h2>概览</h2>
+<table class="summary">
+<tr><th>结果</th><td class="{{.Status}}">{{.Status}}</td><th>总耗时</th><td class="num">{{duration .Seconds}}</td></tr>
+<tr><th>转换的对象</th><td class="num">{{count . "converted"}}</td><th>同步的表</th><td class="num">{{len .Tables}}</td></tr>
+<tr><th>跳过的对象</th><td class="num">{{count . "skipped"}}</td><th>校验不一致的表</th><td class="num">{{len .Validation.Mismatches}}</td></tr>
+<tr><th>失败的对象</th><td class="num">{{count . "failed"}}</td><th></th><td></td></tr>
+</table>
+{{if .Error}}<p class="failed">{{.Error}}</p>{{end}}
+{{if .FastLoadPending}}<p class="failed">快速加载未完成，以下表仍为UNLOGGED或未创建主键、索引和约束，请重新运行转换: {{range $i, $t := .FastLoadPending}}{{if $i}}, {{end}}{{$t}}{{end}}</p>{{end}}
+
+<h2>阶段</h2>
+{{if .Stages}}<table>
+<tr><th>阶段</th><th>对象数量</th><th>开始时间</th><th>耗时(秒)</th></tr>
+{{range .Stages}}<tr><td>{{.Name}}</td><td class="num">{{.Objects}}</td><td>{{.StartedAt.Format "15:04:05"}}</td><td class="num">{{printf "%.2f" .Seconds}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">无</p>{{end}}
+
+<h2>表数据</h2>
+{{if .Tables}}<table>
+<tr><th>表</th><th>目标表</th><th>状态</th><th>行数</th><th>耗时(秒)</th><th>行/秒</th><th>校验 / 错误</th></tr>
+{{range .Tables}}<tr><td>{{.Name}}</td><td>{{.Target}}</td><td class="{{.Status}}">{{.Status}}</td>
+<td class="num">{{.Rows}}</td><td class="num">{{printf "%.2f" .Seconds}}</td><td class="num">{{printf "%.0f" .RowsPerSecond}}</td>
+<td>{{.Validation}}{{.Note}}{{if .Error}}<span class="failed">{{.Error}}</span>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">无</p>{{end}}
+
+{{if .Validation.Enabled}}<h2>数据校验</h2>
+{{if .Validation.Mismatches}}<table>
+<tr><th>表</th><th>MySQL行数</th><th>PostgreSQL行数</th><th>不一致的主键范围</th></tr>
+{{range .Validation.Mismatches}}<tr><td>{{.Table}}</td><td class="num">{{.MySQLRows}}</td><td class="num">{{.PostgresRows}}</td>
+<td>{{if .WholeTable}}<span class="muted">仅整表比较: {{.WholeTable}}</span><br>{{end}}{{range .MismatchedRanges}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="ok">所有表数据一致（校验方式: {{.Validation.Mode}}）</p>{{end}}{{end}}
+
+<h2>对象</h2>
+{{if .Objects}}<table>
+<tr><th>类型</th><th>名称</th><th>状态</th><th>说明 / 错误</th><th>生成的SQL</th></tr>
+{{range .Objects}}<tr><td>{{.Type}}</td><td>{{.Name}}</td><td class="{{.Status}}">{{.Status}}</td>
+<td>{{.Note}}{{if .Error}}<span class="failed">{{.Error}}</span>{{end}}</td>
+<td>{{if .SQL}}<details><summary>SQL</summary><pre>{{.SQL}}</pre></details>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">无</p>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML 以HTML格式输出运行报告
+func (r *RunReport) WriteHTML(w io.Writer) error {
+	if err := runReportTemplate.Execute(w, r); err != nil {
+		return fmt.Errorf("生成运行报告失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+	"github.com/yourusername/mysql2pg/internal/postgres"
+	"github.com/yourusername/mysql2pg/internal/throttle"
+)
+
+// errStreamStopped 写入端出错后停止接收数据，读取端随之退出
+var errStreamStopped = errors.New("写入端已停止接收数据")
+
+// tableRowProducer 表数据读取端，从MySQL读取数据，完成类型转换和脱敏后发送到行数据通道
+type tableRowProducer struct {
+	mysqlConn  *mysql.Connection
+	tableName  string
+	columns    []string
+	primaryKey string   // 单列主键，为空时不使用主键分页
+	keyColumns []string // 所有主键列，用于标识被隔离的行
+	orderBy    string   // 没有单列主键时的排序子句
+	pageSize   int
+	filter     string
+	filterArgs []interface{}
+	converter  *postgres.RowConverter
+	masker     *postgres.ColumnMasker
+	limiter    *throttle.Limiter
+	quarantine bool // 是否启用坏行隔离，启用时转换失败的行交给写入端隔离，否则读取端报错
+}
+
+// run 读取全部数据并发送到 stream，结束时关闭 stream
+// 有单列主键时按主键分页读取，写入端写入当前页时读取端已在读取下一页；
+// 否则使用一次不分页的查询流式读取，避免OFFSET分页重复扫描已读取的数据
+func (p *tableRowProducer) run(ctx context.Context, stream *postgres.RowStream) {
+	var err error
+	defer func() {
+		stream.Close(err)
+	}()
+
+	if p.primaryKey == "" {
+		var rows *sql.Rows
+		rows, err = p.mysqlConn.GetTableData(ctx, p.tableName, p.columns, 0, 0, p.orderBy, p.filter, p.filterArgs...)
+		if err != nil {
+			err = fmt.Errorf("读取表 %s 数据失败: %w", p.tableName, err)
+			return
+		}
+		_, _, err = p.sendRows(ctx, rows, stream)
+		return
+	}
+
+	var lastValue interface{}
+	for {
+		var rows *sql.Rows
+		rows, err = p.mysqlConn.GetTableDataWithPagination(ctx, p.tableName, p.columns, p.primaryKey, lastValue, p.pageSize, p.filter, p.filterArgs...)
+		if err != nil {
+			err = fmt.Errorf("分页读取表 %s 数据失败: %w", p.tableName, err)
+			return
+		}
+
+		var count int
+		var pageLastValue interface{}
+		count, pageLastValue, err = p.sendRows(ctx, rows, stream)
+		if err != nil || count < p.pageSize {
+			return
+		}
+		lastValue = pageLastValue
+	}
+}
+
+// sendRows 逐行扫描、转换并发送数据，返回发送的行数和最后一行的主键值
+// 写入端停止接收时返回 errStreamStopped
+func (p *tableRowProducer) sendRows(ctx context.Context, rows *sql.Rows, stream *postgres.RowStream) (int, interface{}, error) {
+	defer rows.Close()
+
+	primaryKeyIndex := -1
+	var keyIndexes []int
+	for i, col := range p.columns {
+		if p.primaryKey != "" && strings.EqualFold(col, p.primaryKey) {
+			primaryKeyIndex = i
+		}
+		for _, key := range p.keyColumns {
+			if strings.EqualFold(col, key) {
+				keyIndexes = append(keyIndexes, i)
+			}
+		}
+	}
+
+	values := make([]interface{}, len(p.columns))
+	valuePtrs := make([]interface{}, len(p.columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	var count int
+	var lastValue interface{}
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return count, lastValue, fmt.Errorf("扫描行数据失败: %w", err)
+		}
+		if primaryKeyIndex != -1 {
+			lastValue = values[primaryKeyIndex]
+		}
+
+		// 按从MySQL读取的数据量限速
+		if err := p.limiter.Wait(ctx, postgres.RowByteSize(values), 1); err != nil {
+			return count, lastValue, err
+		}
+
+		rowValues := make([]interface{}, len(values))
+		row := postgres.StreamRow{Values: rowValues, PrimaryKey: formatRowKey(values, keyIndexes)}
+		// 脱敏在类型转换前处理原始值，结果按列类型转换后写入
+		p.masker.Apply(values)
+		if err := p.converter.Convert(values, rowValues); err != nil {
+			if !p.quarantine {
+				return count, lastValue, fmt.Errorf("转换表 %s 主键为 %s 的行失败: %w", p.tableName, row.PrimaryKey, err)
+			}
+			row.Rejected = err.Error()
+		}
+
+		if !stream.Send(row) {
+			return count, lastValue, errStreamStopped
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, lastValue, fmt.Errorf("读取表 %s 数据失败: %w", p.tableName, err)
+	}
+
+	return count, lastValue, nil
+}
+
+// formatRowKey 将主键列的原始值格式化为文本，多列主键以逗号分隔
+func formatRowKey(values []interface{}, keyIndexes []int) string {
+	if len(keyIndexes) == 0 {
+		return ""
+	}
+	parts := make([]string, len(keyIndexes))
+	for i, index := range keyIndexes {
+		switch v := values[index].(type) {
+		case nil:
+			parts[i] = "NULL"
+		case []byte:
+			parts[i] = string(v)
+		default:
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(parts, ",")
+}
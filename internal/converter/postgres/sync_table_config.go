@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+var (
+	// CREATE TABLE 语句中的表名
+	reCreateTableName = regexp.MustCompile("(?i)^(\\s*CREATE\\s+(?:TEMPORARY\\s+)?TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?)`[^`]+`")
+	// 列定义行，如 `name` varchar(64) NOT NULL,
+	reColumnDefinitionLine = regexp.MustCompile("^`([^`]+)`\\s")
+	// 键和约束定义行
+	reKeyDefinitionLine = regexp.MustCompile(`(?i)^(PRIMARY\s+KEY|UNIQUE\s+(KEY|INDEX)|KEY|INDEX|FULLTEXT|SPATIAL|CONSTRAINT|FOREIGN\s+KEY|CHECK)\b`)
+	// 反引号包围的标识符
+	reBacktickIdentifier = regexp.MustCompile("`([^`]+)`")
+	// 主键定义行
+	rePrimaryKeyLine = regexp.MustCompile(`(?i)^\s*PRIMARY\s+KEY\s*\(([^)]*)\)`)
+)
+
+// targetTableName 获取表在PostgreSQL中的表名
+// 优先使用单表同步配置中的目标表名，并根据配置决定是否转换为小写
+func targetTableName(cfg *config.Config, tableName string) string {
+	pgTableName := tableName
+	if tc := cfg.Conversion.FindTable(tableName); tc != nil && tc.TargetTable != "" {
+		pgTableName = tc.TargetTable
+	}
+	if cfg.Conversion.Options.LowercaseColumns {
+		pgTableName = strings.ToLower(pgTableName)
+	}
+	return pgTableName
+}
+
+// projectColumns 按单表同步配置裁剪需要同步的列
+func projectColumns(tc *config.TableConfig, columns []string) []string {
+	if tc == nil {
+		return columns
+	}
+	var projected []string
+	for _, col := range columns {
+		if tc.IncludesColumn(col) {
+			projected = append(projected, col)
+		}
+	}
+	return projected
+}
+
+// checkProjectedPrimaryKey 检查单表同步配置没有排除主键列
+// 主键用于分页读取、upsert合并以及校验和分块，排除主键列会重复读取同一页数据并写入重复行，因此直接报错
+func checkProjectedPrimaryKey(tc *config.TableConfig, tableName string, primaryKeys []string) error {
+	if tc == nil {
+		return nil
+	}
+	var excluded []string
+	for _, key := range primaryKeys {
+		if !tc.IncludesColumn(key) {
+			excluded = append(excluded, key)
+		}
+	}
+	if len(excluded) > 0 {
+		return fmt.Errorf("表 %s 的主键列 %s 被 include_columns/exclude_columns 排除，主键列必须同步", tableName, strings.Join(excluded, ", "))
+	}
+	return nil
+}
+
+// primaryKeyColumnsFromDDL 从SHOW CREATE TABLE输出中解析主键列，没有主键时返回nil
+func primaryKeyColumnsFromDDL(mysqlDDL string) []string {
+	for _, line := range strings.Split(mysqlDDL, "\n") {
+		match := rePrimaryKeyLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		var columns []string
+		for _, col := range reBacktickIdentifier.FindAllStringSubmatch(match[1], -1) {
+			columns = append(columns, col[1])
+		}
+		return columns
+	}
+	return nil
+}
+
+// combineFilters 使用 AND 合并多个WHERE条件，忽略空条件
+func combineFilters(filters ...string) string {
+	var parts []string
+	for _, f := range filters {
+		if strings.TrimSpace(f) != "" {
+			parts = append(parts, fmt.Sprintf("(%s)", f))
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// projectTableInfo 按单表同步配置生成用于创建PostgreSQL表的表信息
+// DDL中的表名替换为目标表名，并移除不同步的列以及引用这些列的键和约束
+func projectTableInfo(cfg *config.Config, table mysql.TableInfo) mysql.TableInfo {
+	tc := cfg.Conversion.FindTable(table.Name)
+	if tc == nil {
+		return table
+	}
+
+	projected := table
+	projected.DDL = projectTableDDL(table.DDL, tc.TargetTable, tc.IncludesColumn)
+
+	projected.Columns = nil
+	for _, col := range table.Columns {
+		if tc.IncludesColumn(col.Name) {
+			projected.Columns = append(projected.Columns, col)
+		}
+	}
+
+	projected.Indexes = nil
+	for _, index := range table.Indexes {
+		if projectedIndex, ok := projectIndex(cfg, index); ok {
+			projected.Indexes = append(projected.Indexes, projectedIndex)
+		}
+	}
+
+	return projected
+}
+
+// projectIndex 按单表同步配置转换索引，索引引用了不同步的列时返回 false
+func projectIndex(cfg *config.Config, index mysql.IndexInfo) (mysql.IndexInfo, bool) {
+	tc := cfg.Conversion.FindTable(index.Table)
+	if tc == nil {
+		return index, true
+	}
+	for _, col := range index.Columns {
+		if !tc.IncludesColumn(col) {
+			return index, false
+		}
+	}
+	if tc.TargetTable != "" {
+		index.Table = tc.TargetTable
+	}
+	return index, true
+}
+
+// projectTableDDL 替换SHOW CREATE TABLE输出中的表名，并移除不需要的列定义及引用这些列的键定义
+func projectTableDDL(mysqlDDL, targetTable string, keep func(column string) bool) string {
+	lines := strings.Split(mysqlDDL, "\n")
+	var out []string
+	closeIndex := -1
+
+	for i, line := range lines {
+		if i == 0 && targetTable != "" {
+			line = reCreateTableName.ReplaceAllString(line, "${1}`"+targetTable+"`")
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if i > 0 && closeIndex == -1 {
+			if strings.HasPrefix(trimmed, ")") {
+				closeIndex = len(out)
+			} else if match := reColumnDefinitionLine.FindStringSubmatch(trimmed); match != nil {
+				if !keep(match[1]) {
+					continue
+				}
+			} else if reKeyDefinitionLine.MatchString(trimmed) {
+				if !keyColumnsKept(trimmed, keep) {
+					continue
+				}
+			}
+		}
+
+		out = append(out, line)
+	}
+
+	// 修正定义行末尾的逗号：最后一个定义行不带逗号，其余定义行带逗号
+	if closeIndex > 1 {
+		for j := 1; j < closeIndex; j++ {
+			line := strings.TrimRight(out[j], " \t\r")
+			line = strings.TrimSuffix(line, ",")
+			if j < closeIndex-1 {
+				line += ","
+			}
+			out[j] = line
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// keyColumnsKept 判断键定义第一个括号中引用的列是否都需要保留
+func keyColumnsKept(definition string, keep func(column string) bool) bool {
+	start := strings.Index(definition, "(")
+	if start == -1 {
+		return true
+	}
+	depth := 0
+	end := len(definition)
+	for i := start; i < len(definition); i++ {
+		switch definition[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+
+	for _, match := range reBacktickIdentifier.FindAllStringSubmatch(definition[start:end], -1) {
+		if !keep(match[1]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+func TestCheckProjectedPrimaryKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		tc          *config.TableConfig
+		primaryKeys []string
+		wantErr     bool
+	}{
+		{name: "没有单表配置", tc: nil, primaryKeys: []string{"id"}},
+		{name: "排除非主键列", tc: &config.TableConfig{ExcludeColumns: []string{"secret"}}, primaryKeys: []string{"id"}},
+		{name: "排除主键列", tc: &config.TableConfig{ExcludeColumns: []string{"ID"}}, primaryKeys: []string{"id"}, wantErr: true},
+		{name: "include_columns不包含主键列", tc: &config.TableConfig{IncludeColumns: []string{"name"}}, primaryKeys: []string{"id"}, wantErr: true},
+		{name: "排除复合主键中的一列", tc: &config.TableConfig{ExcludeColumns: []string{"line_no"}}, primaryKeys: []string{"order_id", "line_no"}, wantErr: true},
+		{name: "没有主键", tc: &config.TableConfig{ExcludeColumns: []string{"id"}}, primaryKeys: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkProjectedPrimaryKey(tt.tc, "orders", tt.primaryKeys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkProjectedPrimaryKey() 错误 = %v，期望返回错误: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildTableDDLRejectsExcludedPrimaryKey(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Conversion.Tables = []config.TableConfig{{Table: "orders", ExcludeColumns: []string{"id"}}}
+	m := &Manager{config: cfg}
+
+	ddl := "CREATE TABLE `orders` (\n  `id` bigint NOT NULL AUTO_INCREMENT,\n  `name` varchar(64) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+	if got := primaryKeyColumnsFromDDL(ddl); !reflect.DeepEqual(got, []string{"id"}) {
+		t.Fatalf("primaryKeyColumnsFromDDL() = %v，期望 [id]", got)
+	}
+
+	_, _, err := m.buildTableDDL(mysql.TableInfo{Name: "orders", DDL: ddl})
+	if err == nil || !strings.Contains(err.Error(), "主键列 id") {
+		t.Fatalf("buildTableDDL() 错误 = %v，期望主键列被排除的错误", err)
+	}
+}
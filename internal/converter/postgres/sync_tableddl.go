@@ -27,6 +27,8 @@ var (
 
 	// 类型映射相关正则
 	reTinyInt1       = regexp.MustCompile(`(?i)\btinyint\(1\)\b`)
+	reBit            = regexp.MustCompile(`(?i)\bbit\b(?:\((\d+)\))?`)
+	reBitDefault     = regexp.MustCompile(`(?i)\bdefault\s+b'([01]+)'`)
 	reJsonLength     = regexp.MustCompile(`(?i)\bjson\((\d+)\)\b`)
 	reJsonWithLength = regexp.MustCompile(`(?i)json\(\d+\)`)
 
@@ -77,7 +79,7 @@ func init() {
 // 应用类型映射的顺序
 var typeMappingOrder = []string{
 	// 特殊处理的类型放在前面
-	"tinyint(1)",
+	"tinyint(1)", "bit",
 	// 整数类型
 	"bigint", "biginteger", "int", "integer", "smallinteger", "tinyinteger", "tinyint", "smallint", "mediumint",
 	// 浮点数类型
@@ -119,6 +121,7 @@ var typeMap = map[string]string{
 	"smallinteger": "SMALLINT",
 	"tinyinteger":  "SMALLINT",
 	"tinyint(1)":   "BOOLEAN",
+	"bit":          "BOOLEAN", // bit(1)，bit(n) 转换为 BIT VARYING(n)
 	"tinyint":      "SMALLINT",
 	"smallint":     "SMALLINT",
 	"mediumint":    "INTEGER",
@@ -172,6 +175,8 @@ type ConvertTableDDLResult struct {
 	TableComment   string
 	ColumnNames    map[string]string // 键：原始列名，值：转换后的列名（带双引号格式）
 	ColumnComments map[string]string // 键：原始列名，值：列注释
+	Constraints    []string          // DDL中的CHECK约束定义
+	PrimaryKey     string            // DDL中的主键约束定义，如 PRIMARY KEY ("id")，没有主键时为空
 }
 
 // parseTableInfo 解析表名和是否为临时表
@@ -317,6 +322,11 @@ func convertDataType(mysqlType string) (postgresType string, isAutoIncrement boo
 		return postgresType, isAutoIncrement, nil
 	}
 
+	if match := reBit.FindStringSubmatch(mysqlType); match != nil {
+		postgresType = bitType(match[1])
+		return postgresType, isAutoIncrement, nil
+	}
+
 	if reJsonLength.MatchString(mysqlType) {
 		postgresType = "JSON"
 		return postgresType, isAutoIncrement, nil
@@ -359,6 +369,34 @@ func convertDataType(mysqlType string) (postgresType string, isAutoIncrement boo
 	return postgresType, isAutoIncrement, nil
 }
 
+// bitType 获取 bit(width) 对应的PostgreSQL类型，bit(1) 转换为 BOOLEAN，其余转换为 BIT VARYING(width)
+func bitType(width string) string {
+	if width == "" || width == "1" {
+		return "BOOLEAN"
+	}
+	return fmt.Sprintf("BIT VARYING(%s)", width)
+}
+
+// convertBitDefinition 转换列定义中的 bit 类型及其 b'...' 默认值
+func convertBitDefinition(typeDefinition string) string {
+	match := reBit.FindStringSubmatch(typeDefinition)
+	if match == nil {
+		return typeDefinition
+	}
+	pgType := bitType(match[1])
+	typeDefinition = reBit.ReplaceAllLiteralString(typeDefinition, pgType)
+	if pgType == "BOOLEAN" {
+		// BOOLEAN 列的默认值 b'1' 转换为 true
+		typeDefinition = reBitDefault.ReplaceAllStringFunc(typeDefinition, func(m string) string {
+			if strings.Contains(reBitDefault.FindStringSubmatch(m)[1], "1") {
+				return "default true"
+			}
+			return "default false"
+		})
+	}
+	return typeDefinition
+}
+
 // processColumnDefinition 处理列定义，提取列名、类型定义和注释
 func processColumnDefinition(line string, lowercaseColumns bool) (columnName string, typeDefinition string, columnComment string, isConstraint bool, isCheckConstraint bool, checkConstraintDefinition string, isIncompleteType bool, err error) {
 	line = strings.ReplaceAll(line, " ON UPDATE CURRENT_TIMESTAMP", "")
@@ -536,6 +574,11 @@ func cleanTypeDefinition(typeDefinition string) string {
 			continue
 		}
 
+		if mysqlType == "bit" {
+			lowerTypeDef = convertBitDefinition(lowerTypeDef)
+			continue
+		}
+
 		if pattern, ok := typePatterns[mysqlType]; ok {
 			lowerTypeDef = pattern.ReplaceAllStringFunc(lowerTypeDef, func(m string) string {
 				match := pattern.FindStringSubmatch(m)
@@ -912,6 +955,7 @@ func ConvertTableDDL(mysqlDDL string, lowercaseColumns bool) (*ConvertTableDDLRe
 	}
 
 	// 添加主键约束
+	var primaryKeyDef string
 	if primaryKeyColumn != "" {
 		if originalColumnName, ok := columnNames[strings.ToLower(primaryKeyColumn)]; ok {
 			primaryKeyColumn = originalColumnName
@@ -919,14 +963,14 @@ func ConvertTableDDL(mysqlDDL string, lowercaseColumns bool) (*ConvertTableDDLRe
 				primaryKeyColumn = strings.ToLower(primaryKeyColumn)
 			}
 		}
-		primaryKeyDef := fmt.Sprintf(`PRIMARY KEY ("%s")`, primaryKeyColumn)
+		primaryKeyDef = fmt.Sprintf(`PRIMARY KEY ("%s")`, primaryKeyColumn)
 		tableElements = append(tableElements, primaryKeyDef)
 	}
 
 	// 添加 CHECK 约束
+	var uniqueCheckConstraints []string
 	if len(checkConstraints) > 0 {
 		// 去重 CHECK 约束，避免重复添加
-		uniqueCheckConstraints := make([]string, 0)
 		seenConstraints := make(map[string]bool)
 
 		for _, constraint := range checkConstraints {
@@ -968,6 +1012,8 @@ func ConvertTableDDL(mysqlDDL string, lowercaseColumns bool) (*ConvertTableDDLRe
 		TableComment:   tableComment,
 		ColumnNames:    columnNamesMap,
 		ColumnComments: columnCommentsMap,
+		Constraints:    uniqueCheckConstraints,
+		PrimaryKey:     primaryKeyDef,
 	}, nil
 }
 
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+// translateSourceConstructs 转换时会改变语义或需要额外准备的MySQL写法，在原语句中检查
+var translateSourceConstructs = []assessConstruct{
+	{regexp.MustCompile(`"`), "双引号字符串：MySQL中是字符串，PostgreSQL中是标识符，需改为单引号"},
+	{regexp.MustCompile(`\?`), "参数占位符 ?：PostgreSQL驱动通常使用 $1、$2"},
+	{regexp.MustCompile(`(?i)\b(date_format|str_to_date)\s*\(`), "DATE_FORMAT / STR_TO_DATE：格式字符串需改为PostgreSQL的模板，如 %Y-%m-%d 改为 YYYY-MM-DD"},
+	{regexp.MustCompile(`(?i)\b(json_extract|json_value)\s*\(`), "JSON_EXTRACT / JSON_VALUE：路径 '$.key' 需改为键名或 #> '{a,b}' 路径"},
+	{regexp.MustCompile(`(?i)\bjson_(depth|overlaps)\s*\(`), "JSON_DEPTH / JSON_OVERLAPS：没有对应函数，转换为 NULL"},
+	{regexp.MustCompile(`(?i)\brand\s*\(\s*[^)\s]`), "RAND(seed)：random() 不支持种子，种子被忽略"},
+	{regexp.MustCompile(`(?i)\buuid\s*\(\s*\)`), "UUID()：转换为 uuid_generate_v4()，需要 uuid-ossp 扩展"},
+	{regexp.MustCompile(`(?i)\blast_insert_id\s*\(`), "LAST_INSERT_ID：转换为 lastval()，建议改用 INSERT ... RETURNING"},
+	{regexp.MustCompile(`(?i)\bconcat\s*\(`), "CONCAT：MySQL中任一参数为NULL时结果为NULL，PostgreSQL的 concat() 忽略NULL"},
+}
+
+// translateResultConstructs 转换后仍保留的MySQL特有写法，在转换结果中检查
+var translateResultConstructs = append([]assessConstruct{
+	{regexp.MustCompile(`(?i)\bon\s+duplicate\s+key\s+update\b`), "ON DUPLICATE KEY UPDATE：需改写为 INSERT ... ON CONFLICT ... DO UPDATE"},
+	{regexp.MustCompile(`(?i)^\s*replace\s+(into\b|\w)`), "REPLACE INTO：需改写为 INSERT ... ON CONFLICT ... DO UPDATE"},
+	{regexp.MustCompile(`(?i)^\s*insert\s+ignore\b`), "INSERT IGNORE：需改写为 INSERT ... ON CONFLICT DO NOTHING"},
+	{regexp.MustCompile(`(?i)\b(low_priority|high_priority|delayed|sql_no_cache|sql_cache|sql_buffer_result|sql_small_result|sql_big_result)\b`), "查询修饰符（如 SQL_NO_CACHE、LOW_PRIORITY）：PostgreSQL不支持，需删除"},
+	{regexp.MustCompile(`(?i)\b(use|force|ignore)\s+(index|key)\s*\(`), "索引提示 USE/FORCE/IGNORE INDEX：PostgreSQL不支持，需删除"},
+	{regexp.MustCompile(`(?is)^\s*(update|delete)\b.*\blimit\s+\d+`), "UPDATE/DELETE ... LIMIT：需改写为子查询"},
+	{regexp.MustCompile(`(?i)\bwith\s+rollup\b`), "WITH ROLLUP：需改写为 GROUP BY ROLLUP(...)"},
+	{regexp.MustCompile(`(?i)\block\s+in\s+share\s+mode\b`), "LOCK IN SHARE MODE：需改为 FOR SHARE"},
+	{regexp.MustCompile(`(?i)^\s*(show|desc|describe)\b`), "SHOW / DESCRIBE：需改为查询系统目录或 information_schema"},
+	{regexp.MustCompile(`(?i)^\s*load\s+data\b`), "LOAD DATA：需改为 COPY"},
+	{regexp.MustCompile(`(?i)\block\s+tables\b|\bunlock\s+tables\b`), "LOCK TABLES：需改为事务中的 LOCK TABLE"},
+	{regexp.MustCompile(`@@\w+`), "系统变量：需改为 current_setting() 或 SHOW"},
+	{regexp.MustCompile(`(^|[^@\w])@\w+|:=`), "用户变量：PostgreSQL的SQL中没有会话变量"},
+	{regexp.MustCompile(`(?i)\bseparator\b`), "GROUP_CONCAT ... SEPARATOR：分隔符未能转换，需改为 string_agg 的第二个参数"},
+	{regexp.MustCompile(`(?i)\b(sha2|conv|timestampdiff)\s*\(`), "SHA2 / CONV / TIMESTAMPDIFF：参数形式不支持，未转换"},
+	{regexp.MustCompile(`(?i)\bget_lock\s*\(|\brelease_lock\s*\(`), "GET_LOCK / RELEASE_LOCK：可改用 pg_advisory_lock"},
+}, viewManualConstructs...)
+
+// TranslatedStatement 一条SQL语句的转换结果
+type TranslatedStatement struct {
+	File     string   `json:"file,omitempty"` // 语句所在的文件，从标准输入读取时为空
+	Index    int      `json:"index"`          // 语句在文件中的序号，从1开始
+	Source   string   `json:"source"`
+	SQL      string   `json:"sql"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// TranslateSQL 将一条MySQL语句转换为PostgreSQL语法，返回转换后的语句和无法自动转换或语义可能改变的写法
+// dbName 不为空时移除该数据库名前缀，lowercase 为true时将字符串常量以外的内容转为小写，与 lowercase_columns 转换的列名一致
+func TranslateSQL(sql string, dbName string, lowercase bool) (string, []string, error) {
+	sql = strings.TrimSpace(sql)
+	if sql == "" {
+		return "", nil, fmt.Errorf("空的SQL语句")
+	}
+	translated, err := translateMySQLSQL(sql, dbName, "statement")
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 字符串常量中的内容不参与检查
+	maskedSource, _ := maskStringLiterals(sql)
+	maskedResult, literals := maskStringLiterals(translated)
+	warnings := append(findConstructs(translateSourceConstructs, maskedSource), findConstructs(translateResultConstructs, maskedResult)...)
+
+	if lowercase {
+		maskedResult = strings.ToLower(maskedResult)
+		for placeholder, literal := range literals {
+			maskedResult = strings.ReplaceAll(maskedResult, strings.ToLower(placeholder), literal)
+		}
+		translated = maskedResult
+	}
+	return translated, warnings, nil
+}
+
+// TranslateStatements 读取SQL文本，按分号和 DELIMITER 拆分为语句并逐条转换
+// file 为语句所在的文件名，只用于记录在结果中
+func TranslateStatements(r io.Reader, file string, dbName string, lowercase bool) ([]TranslatedStatement, error) {
+	statements, err := mysql.SplitStatements(r)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]TranslatedStatement, 0, len(statements))
+	for i, stmt := range statements {
+		result := TranslatedStatement{File: file, Index: i + 1, Source: stmt}
+		sql, warnings, err := TranslateSQL(stmt, dbName, lowercase)
+		if err != nil {
+			// 无法转换时保留原语句
+			result.SQL = stmt
+			result.Warnings = []string{fmt.Sprintf("转换失败: %v", err)}
+		} else {
+			result.SQL, result.Warnings = sql, warnings
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// WriteTranslatedSQL 输出转换后的SQL脚本，警告以注释的形式写在对应语句之前
+func WriteTranslatedSQL(w io.Writer, statements []TranslatedStatement) error {
+	for _, stmt := range statements {
+		location := fmt.Sprintf("%d", stmt.Index)
+		if stmt.File != "" {
+			location = fmt.Sprintf("%s:%d", stmt.File, stmt.Index)
+		}
+		for _, warning := range stmt.Warnings {
+			if _, err := fmt.Fprintf(w, "-- [%s] 警告: %s\n", location, warning); err != nil {
+				return fmt.Errorf("写入转换结果失败: %w", err)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s;\n\n", strings.TrimSuffix(stmt.SQL, ";")); err != nil {
+			return fmt.Errorf("写入转换结果失败: %w", err)
+		}
+	}
+	return nil
+}
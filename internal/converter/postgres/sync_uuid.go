@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+var (
+	// 列定义中的 binary(16) 类型
+	reBinary16 = regexp.MustCompile(`(?i)\bbinary\(16\)`)
+	// MySQL 8 生成UUID主键的默认值，如 (uuid_to_bin(uuid(), 1))
+	reUUIDToBinDefault = regexp.MustCompile(`(?i)uuid_to_bin\(\s*uuid\(\)\s*(?:,\s*\w+\s*)?\)`)
+)
+
+// uuidColumns 获取表中转换为 uuid 的 BINARY(16) 列，键为小写列名，值为是否以 UUID_TO_BIN(uuid, 1) 的字节顺序存储
+func uuidColumns(cfg *config.Config, tableName string, columnTypes map[string]string) map[string]bool {
+	var result map[string]bool
+	for col, colType := range columnTypes {
+		swapFlag, ok := cfg.Conversion.UUID.FindColumn(tableName, col, colType)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]bool)
+		}
+		result[strings.ToLower(col)] = swapFlag
+	}
+	return result
+}
+
+// tableUUIDColumns 获取表结构中转换为 uuid 的 BINARY(16) 列
+func tableUUIDColumns(cfg *config.Config, table mysql.TableInfo) map[string]bool {
+	columnTypes := make(map[string]string, len(table.Columns))
+	for _, col := range table.Columns {
+		columnTypes[col.Name] = col.Type
+	}
+	return uuidColumns(cfg, table.Name, columnTypes)
+}
+
+// applyUUIDColumns 将SHOW CREATE TABLE输出中UUID列的 binary(16) 替换为 uuid
+// 默认值 uuid_to_bin(uuid()) 同时替换为 uuid()，在PostgreSQL中转换为 gen_random_uuid()
+func applyUUIDColumns(mysqlDDL string, uuids map[string]bool) string {
+	if len(uuids) == 0 {
+		return mysqlDDL
+	}
+
+	lines := strings.Split(mysqlDDL, "\n")
+	for i, line := range lines {
+		match := reColumnDefinitionLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		if _, ok := uuids[strings.ToLower(match[1])]; !ok {
+			continue
+		}
+		line = reBinary16.ReplaceAllString(line, "uuid")
+		lines[i] = reUUIDToBinDefault.ReplaceAllString(line, "uuid()")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/mysql2pg/internal/mysql"
+)
+
+// Validate 只校验已同步的数据，不转换对象也不写入数据
+// 比较每个表在MySQL和PostgreSQL中的行数，validate_mode 为 checksum 时同时比较行内容校验和；
+// 数据源为导出文件时比较导出文件中的行数
+func (m *Manager) Validate(ctx context.Context) error {
+	m.Log("校验 MySQL 和 PostgreSQL 中的表数据 ...")
+
+	options := m.config.Conversion.Options
+	var tables []mysql.TableInfo
+	var fileRowCounts map[string]int64
+	if m.dumpFile != nil {
+		tables = m.dumpFile.GetTables(options.SkipUseTableList, options.SkipTableList, options.UseTableList, options.TableList)
+		fileRowCounts = make(map[string]int64, len(tables))
+		if err := m.dumpFile.ScanRows(func(table string, columns []string, values []interface{}) error {
+			fileRowCounts[table]++
+			return nil
+		}); err != nil {
+			return fmt.Errorf("读取导出文件数据失败: %w", err)
+		}
+	} else {
+		var err error
+		tables, err = m.mysqlConn.GetTables(ctx, options.SkipUseTableList, options.SkipTableList, options.UseTableList, options.TableList)
+		if err != nil {
+			return fmt.Errorf("获取表信息失败: %w", err)
+		}
+	}
+
+	m.totalTasks = len(tables)
+	m.completedTasks = 0
+	startTime := time.Now()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, m.config.Conversion.Limits.Concurrency)
+	errorChan := make(chan error, 1)
+	for _, table := range tables {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(table mysql.TableInfo) {
+			defer func() {
+				<-semaphore
+				m.updateProgress()
+				wg.Done()
+			}()
+			if err := m.validateTable(ctx, table, fileRowCounts); err != nil {
+				m.logError(fmt.Sprintf("校验表 %s 数据失败: %v", table.Name, err))
+				select {
+				case errorChan <- fmt.Errorf("校验表 %s 失败: %w", table.Name, err):
+				default:
+				}
+			}
+		}(table)
+	}
+	wg.Wait()
+
+	m.conversionStats = append(m.conversionStats, ConversionStageStat{
+		StageName:   "校验表数据",
+		StartTime:   startTime,
+		EndTime:     time.Now(),
+		ObjectCount: len(tables),
+	})
+
+	m.displayInconsistentTables()
+	m.generateSummaryTable()
+
+	select {
+	case err := <-errorChan:
+		return err
+	default:
+	}
+	m.Log("校验完成，%d 个表中有 %d 个表数据不一致", len(tables), len(m.inconsistentTables))
+	return nil
+}
+
+// validateTable 校验一个表的数据，不一致时记录到不一致表统计中
+// fileRowCounts 不为nil时MySQL一侧的行数取自导出文件，不比较校验和
+func (m *Manager) validateTable(ctx context.Context, table mysql.TableInfo, fileRowCounts map[string]int64) error {
+	pgTableName := targetTableName(m.config, table.Name)
+	tableConfig := m.config.Conversion.FindTable(table.Name)
+	where := tableConfig.Filter()
+
+	var mysqlRowCount int64
+	if fileRowCounts != nil {
+		mysqlRowCount = fileRowCounts[table.Name]
+	} else {
+		var err error
+		mysqlRowCount, err = m.mysqlConn.GetTableRowCountWithFilter(ctx, table.Name, where)
+		if err != nil {
+			return fmt.Errorf("获取MySQL行数失败: %w", err)
+		}
+	}
+	pgRowCount, err := m.postgresConn.GetTableRowCount(ctx, pgTableName)
+	if err != nil {
+		return fmt.Errorf("获取PostgreSQL行数失败: %w", err)
+	}
+	consistent := mysqlRowCount == pgRowCount
+
+	var checksum *ChecksumResult
+	if m.config.Conversion.Options.IsChecksumValidation() && fileRowCounts == nil {
+		columns, columnTypes, err := m.mysqlConn.GetTableColumnsWithTypes(ctx, table.Name)
+		if err != nil {
+			return fmt.Errorf("获取表列信息失败: %w", err)
+		}
+		columns = projectColumns(tableConfig, columns)
+		if primaryKeys, err := m.mysqlConn.GetTablePrimaryKeys(ctx, table.Name); err == nil {
+			if err := checkProjectedPrimaryKey(tableConfig, table.Name, primaryKeys); err != nil {
+				return err
+			}
+		}
+		checksum, err = ValidateTableChecksum(ctx, m.mysqlConn, m.postgresConn, table.Name, pgTableName, unmaskedColumns(m.config, table.Name, columns), columnTypes, uuidColumns(m.config, table.Name, columnTypes), jsonbColumns(m.config, table.Name, columnTypes), where, m.config.Conversion.Limits.ChecksumChunkSize)
+		if err != nil {
+			return fmt.Errorf("比较数据校验和失败: %w", err)
+		}
+		consistent = consistent && !checksum.Mismatched
+	}
+
+	validationResult := "数据一致"
+	if !consistent {
+		validationResult = "数据不一致"
+		m.mutex.Lock()
+		m.inconsistentTables = append(m.inconsistentTables, TableDataInconsistency{
+			TableName:        table.Name,
+			MySQLRowCount:    mysqlRowCount,
+			PostgresRowCount: pgRowCount,
+			Checksum:         checksum,
+		})
+		m.mutex.Unlock()
+	}
+
+	if m.config.Run.ShowConsoleLogs {
+		m.mutex.Lock()
+		progress := float64(m.completedTasks+1) / float64(m.totalTasks) * 100
+		fmt.Printf("进度: %.2f%% (%d/%d) : 校验表 %s，MySQL %d 行，PostgreSQL %d 行，%s\n", progress, m.completedTasks+1, m.totalTasks, table.Name, mysqlRowCount, pgRowCount, validationResult)
+		m.mutex.Unlock()
+	}
+	m.Log("校验表 %s 完成，MySQL %d 行，PostgreSQL %d 行，%s", table.Name, mysqlRowCount, pgRowCount, validationResult)
+	return nil
+}
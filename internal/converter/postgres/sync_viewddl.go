@@ -146,10 +146,38 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return "", fmt.Errorf("empty view definition for view '%s'", viewName)
 	}
 
+	processed, err := translateMySQLSQL(viewDefinition, dbName, fmt.Sprintf("view definition for view '%s'", viewName))
+	if err != nil {
+		return "", err
+	}
+
+	// 包装成CREATE OR REPLACE VIEW语句
+	quotedViewName := quoteIdentifier(viewName)
+	if quotedViewName == "" {
+		return "", fmt.Errorf("failed to quote view name '%s'", viewName)
+	}
+	// Use DROP VIEW IF EXISTS ... CASCADE to allow type changes in columns
+	createStmt := fmt.Sprintf("DROP VIEW IF EXISTS %s CASCADE; CREATE OR REPLACE VIEW %s AS %s;", quotedViewName, quotedViewName, processed)
+	if createStmt == "" {
+		return "", fmt.Errorf("failed to generate CREATE VIEW statement for view '%s'", viewName)
+	}
+
+	// 将整个语句转换为小写，确保符合要求
+	createStmt = strings.ToLower(createStmt)
+	if createStmt == "" {
+		return "", fmt.Errorf("failed to convert CREATE VIEW statement to lowercase for view '%s'", viewName)
+	}
+
+	return createStmt, nil
+}
+
+// translateMySQLSQL 将MySQL的SQL文本转换为PostgreSQL语法：反引号、数据库名前缀、函数、LIMIT、JSON函数、时间函数等
+// 视图定义和 translate 命令共用，object 用于错误信息中说明转换的对象
+func translateMySQLSQL(sql string, dbName string, object string) (string, error) {
 	//  首先将反引号替换为双引号（标识符引用），确保所有后续正则表达式处理正确
-	processed := strings.ReplaceAll(viewDefinition, "`", `"`)
+	processed := strings.ReplaceAll(sql, "`", `"`)
 	if processed == "" {
-		return "", fmt.Errorf("failed to process backticks in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to process backticks in %s", object)
 	}
 
 	processed, literals := maskStringLiterals(processed)
@@ -164,13 +192,13 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 	}
 
 	if processed == "" {
-		return "", fmt.Errorf("failed to remove database prefix in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to remove database prefix in %s", object)
 	}
 
 	// 将IFNULL/ifnull替换为COALESCE
 	processed = reIfnull.ReplaceAllString(processed, "COALESCE(")
 	if processed == "" {
-		return "", fmt.Errorf("failed to replace IFNULL with COALESCE in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to replace IFNULL with COALESCE in %s", object)
 	}
 
 	// GROUP_CONCAT -> string_agg 的简单转换，保留 SEPARATOR 和 ORDER BY 的常见用法
@@ -192,13 +220,13 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return fmt.Sprintf("string_agg(CAST(%s AS text), '%s')", strings.TrimSpace(innerClean), sep)
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert GROUP_CONCAT to string_agg in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert GROUP_CONCAT to string_agg in %s", object)
 	}
 
 	//  将IF(expr, then, else)转换为CASE WHEN ... THEN ... ELSE ... END（简单版，不处理嵌套逗号）
 	processed = reIf.ReplaceAllString(processed, "CASE WHEN $1 THEN $2 ELSE $3 END")
 	if processed == "" {
-		return "", fmt.Errorf("failed to replace IF with CASE WHEN in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to replace IF with CASE WHEN in %s", object)
 	}
 
 	processed = processUsingClause(processed)
@@ -216,7 +244,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 	// 将LIMIT a,b转换为LIMIT b OFFSET a
 	processed = reLimitOffset.ReplaceAllString(processed, "LIMIT $2 OFFSET $1")
 	if processed == "" {
-		return "", fmt.Errorf("failed to adjust LIMIT syntax in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to adjust LIMIT syntax in %s", object)
 	}
 
 	processed = processFunctionCall(processed, "length", func(args []string) string {
@@ -352,7 +380,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 	// 9) 将简单的CONCAT(a,b,...)转换为 a || b || ... （保留原始行为，对于复杂表达式会尽量处理）
 	processed = replaceConcatExpressions(processed)
 	if processed == "" {
-		return "", fmt.Errorf("failed to replace CONCAT with || in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to replace CONCAT with || in %s", object)
 	}
 
 	// 9.1) 为SUM函数添加类型转换，解决sum(character varying)不存在的问题
@@ -369,7 +397,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return sb.String()
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to add type conversion for SUM function in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to add type conversion for SUM function in %s", object)
 	}
 
 	// 9.2) 处理COALESCE函数的参数类型不匹配问题
@@ -382,7 +410,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return fmt.Sprintf("coalesce(%s)", strings.Join(castedArgs, ","))
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to fix COALESCE parameter types in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to fix COALESCE parameter types in %s", object)
 	}
 
 	// 修正常见MySQL函数差异/关键字，JSON函数转换
@@ -502,7 +530,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 	})
 
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert JSON functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert JSON functions in %s", object)
 	}
 
 	// 加密函数转换
@@ -522,7 +550,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return fmt.Sprintf("sha2(%s)", params)
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert encryption functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert encryption functions in %s", object)
 	}
 
 	// UUID函数转换
@@ -533,7 +561,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return "(extract(epoch from now()) * 1000000)::bigint"
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert UUID functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert UUID functions in %s", object)
 	}
 
 	// 网络函数转换
@@ -564,7 +592,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return sb.String()
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert network functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert network functions in %s", object)
 	}
 
 	// 时间函数转换
@@ -699,7 +727,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 	})
 
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert basic time functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert basic time functions in %s", object)
 	}
 
 	// 时间函数转换 - DATE_ADD/DATE_SUB
@@ -760,7 +788,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return sb.String()
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to process DATE_ADD/DATE_SUB functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to process DATE_ADD/DATE_SUB functions in %s", object)
 	}
 
 	// ADDDATE/SUBDATE -> + / -
@@ -795,14 +823,14 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return sb.String()
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to process ADDDATE/SUBDATE functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to process ADDDATE/SUBDATE functions in %s", object)
 	}
 
 	// 使用更精确的方式处理ADDTIME和SUBTIME函数，避免影响其他表达式
 	processed = reADDTIME.ReplaceAllString(processed, "($1 + $2)")
 	processed = reSUBTIME.ReplaceAllString(processed, "($1 - $2)")
 	if processed == "" {
-		return "", fmt.Errorf("failed to process ADDTIME/SUBTIME functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to process ADDTIME/SUBTIME functions in %s", object)
 	}
 
 	// 系统函数转换
@@ -838,7 +866,7 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 	// PostgreSQL的random()不支持种子参数，所以直接替换整个函数调用
 	processed = reRAND.ReplaceAllString(processed, "random()")
 	if processed == "" {
-		return "", fmt.Errorf("failed to convert system functions in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to convert system functions in %s", object)
 	}
 
 	// 处理 interval 语法 (如 now() + interval 1 day → now() + interval '1 day')
@@ -879,12 +907,12 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		return sb.String()
 	})
 	if processed == "" {
-		return "", fmt.Errorf("failed to process interval syntax in view definition for view '%s'", viewName)
+		return "", fmt.Errorf("failed to process interval syntax in %s", object)
 	}
 
 	processed = strings.TrimSpace(processed)
 	if processed == "" {
-		return "", fmt.Errorf("processed view definition is empty after trimming for view '%s'", viewName)
+		return "", fmt.Errorf("processed %s is empty after trimming", object)
 	}
 
 	// 如果定义末尾有分号，去掉它（我们将在CREATE VIEW语句后追加分号）
@@ -892,31 +920,14 @@ func ConvertViewDDL(viewName string, viewDefinition string, dbName string) (stri
 		processed = strings.TrimSuffix(processed, ";")
 		processed = strings.TrimSpace(processed)
 		if processed == "" {
-			return "", fmt.Errorf("view definition became empty after removing trailing semicolon for view '%s'", viewName)
+			return "", fmt.Errorf("%s became empty after removing trailing semicolon", object)
 		}
 	}
 
 	// Unmask string literals
 	processed = unmaskStringLiterals(processed, literals)
 
-	// 包装成CREATE OR REPLACE VIEW语句
-	quotedViewName := quoteIdentifier(viewName)
-	if quotedViewName == "" {
-		return "", fmt.Errorf("failed to quote view name '%s'", viewName)
-	}
-	// Use DROP VIEW IF EXISTS ... CASCADE to allow type changes in columns
-	createStmt := fmt.Sprintf("DROP VIEW IF EXISTS %s CASCADE; CREATE OR REPLACE VIEW %s AS %s;", quotedViewName, quotedViewName, processed)
-	if createStmt == "" {
-		return "", fmt.Errorf("failed to generate CREATE VIEW statement for view '%s'", viewName)
-	}
-
-	// 将整个语句转换为小写，确保符合要求
-	createStmt = strings.ToLower(createStmt)
-	if createStmt == "" {
-		return "", fmt.Errorf("failed to convert CREATE VIEW statement to lowercase for view '%s'", viewName)
-	}
-
-	return createStmt, nil
+	return processed, nil
 }
 
 // quoteIdentifier 始终用双引号引用标识符，且对内部双引号做转义
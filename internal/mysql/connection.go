@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -63,8 +64,8 @@ func (c *Connection) GetDB() *sql.DB {
 }
 
 // GetTableColumns 获取表的列信息
-func (c *Connection) GetTableColumns(tableName string) ([]string, error) {
-	rows, err := c.db.Query(fmt.Sprintf("SHOW COLUMNS FROM `%s`", tableName))
+func (c *Connection) GetTableColumns(ctx context.Context, tableName string) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf("SHOW COLUMNS FROM `%s`", tableName))
 	if err != nil {
 		return nil, fmt.Errorf("获取表列信息失败: %w", err)
 	}
@@ -86,8 +87,8 @@ func (c *Connection) GetTableColumns(tableName string) ([]string, error) {
 }
 
 // GetTableColumnsWithTypes 获取表的列名和类型信息
-func (c *Connection) GetTableColumnsWithTypes(tableName string) ([]string, map[string]string, error) {
-	rows, err := c.db.Query(fmt.Sprintf("SHOW COLUMNS FROM `%s`", tableName))
+func (c *Connection) GetTableColumnsWithTypes(ctx context.Context, tableName string) ([]string, map[string]string, error) {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf("SHOW COLUMNS FROM `%s`", tableName))
 	if err != nil {
 		return nil, nil, fmt.Errorf("获取表列信息失败: %w", err)
 	}
@@ -112,7 +113,8 @@ func (c *Connection) GetTableColumnsWithTypes(tableName string) ([]string, map[s
 }
 
 // GetTableData 获取表数据
-func (c *Connection) GetTableData(tableName string, columns []string, offset, limit int, orderBy string) (*sql.Rows, error) {
+// filter 为可选的附加WHERE条件（不含WHERE关键字），filterArgs 为其占位符参数
+func (c *Connection) GetTableData(ctx context.Context, tableName string, columns []string, offset, limit int, orderBy string, filter string, filterArgs ...interface{}) (*sql.Rows, error) {
 	// 使用反引号包围表名和列名，以处理包含特殊字符的名称
 	var quotedColumns []string
 	for _, col := range columns {
@@ -123,12 +125,18 @@ func (c *Connection) GetTableData(tableName string, columns []string, offset, li
 	// 对于大表，使用LIMIT和OFFSET可能会导致性能问题
 	// 但在没有主键的情况下，这是唯一的选择
 	query := fmt.Sprintf("SELECT %s FROM `%s`", columnsStr, tableName)
+	if filter != "" {
+		query += fmt.Sprintf(" WHERE %s", filter)
+	}
 	if orderBy != "" {
 		query += fmt.Sprintf(" ORDER BY %s", orderBy)
 	}
-	query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	// limit 小于等于0时不分页，一次查询流式读取全部数据
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	}
 
-	rows, err := c.db.Query(query)
+	rows, err := c.db.QueryContext(ctx, query, filterArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("获取表数据失败: %w", err)
 	}
@@ -137,7 +145,8 @@ func (c *Connection) GetTableData(tableName string, columns []string, offset, li
 }
 
 // GetTableDataWithPagination 使用基于主键的分页获取表数据
-func (c *Connection) GetTableDataWithPagination(tableName string, columns []string, primaryKey string, lastValue interface{}, limit int) (*sql.Rows, error) {
+// filter 为可选的附加WHERE条件（不含WHERE关键字），例如增量同步的水位下界，filterArgs 为其占位符参数
+func (c *Connection) GetTableDataWithPagination(ctx context.Context, tableName string, columns []string, primaryKey string, lastValue interface{}, limit int, filter string, filterArgs ...interface{}) (*sql.Rows, error) {
 	// 使用反引号包围表名、列名和主键，以处理包含特殊字符的名称
 	var quotedColumns []string
 	for _, col := range columns {
@@ -145,19 +154,25 @@ func (c *Connection) GetTableDataWithPagination(tableName string, columns []stri
 	}
 	columnsStr := strings.Join(quotedColumns, ", ")
 
-	var query string
+	var conditions []string
 	var args []interface{}
 
+	if filter != "" {
+		conditions = append(conditions, fmt.Sprintf("(%s)", filter))
+		args = append(args, filterArgs...)
+	}
 	if lastValue != nil {
-		query = fmt.Sprintf("SELECT %s FROM `%s` WHERE `%s` > ? ORDER BY `%s` LIMIT %d",
-			columnsStr, tableName, primaryKey, primaryKey, limit)
-		args = []interface{}{lastValue}
-	} else {
-		query = fmt.Sprintf("SELECT %s FROM `%s` ORDER BY `%s` LIMIT %d",
-			columnsStr, tableName, primaryKey, limit)
+		conditions = append(conditions, fmt.Sprintf("`%s` > ?", primaryKey))
+		args = append(args, lastValue)
 	}
 
-	rows, err := c.db.Query(query, args...)
+	query := fmt.Sprintf("SELECT %s FROM `%s`", columnsStr, tableName)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY `%s` LIMIT %d", primaryKey, limit)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("获取表数据失败: %w", err)
 	}
@@ -166,12 +181,12 @@ func (c *Connection) GetTableDataWithPagination(tableName string, columns []stri
 }
 
 // GetTablePrimaryKeys 获取表的主键列名列表
-func (c *Connection) GetTablePrimaryKeys(tableName string) ([]string, error) {
+func (c *Connection) GetTablePrimaryKeys(ctx context.Context, tableName string) ([]string, error) {
 	// 使用SHOW KEYS FROM语句获取主键信息，避免查询information_schema导致的权限问题
 	// 这样可以同时兼容MySQL 5.7和MySQL 8.0
 	query := fmt.Sprintf("SHOW KEYS FROM `%s` WHERE Key_name = 'PRIMARY'", tableName)
 
-	rows, err := c.db.Query(query)
+	rows, err := c.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("获取表主键失败: %w", err)
 	}
@@ -215,8 +230,8 @@ func (c *Connection) GetTablePrimaryKeys(tableName string) ([]string, error) {
 }
 
 // GetTablePrimaryKey 获取表的主键列名
-func (c *Connection) GetTablePrimaryKey(tableName string) (string, error) {
-	primaryKeys, err := c.GetTablePrimaryKeys(tableName)
+func (c *Connection) GetTablePrimaryKey(ctx context.Context, tableName string) (string, error) {
+	primaryKeys, err := c.GetTablePrimaryKeys(ctx, tableName)
 	if err != nil {
 		return "", err
 	}
@@ -229,25 +244,44 @@ func (c *Connection) GetTablePrimaryKey(tableName string) (string, error) {
 }
 
 // EstimateRowSize 估算单行数据大小
-func (c *Connection) EstimateRowSize(tableName string) (int64, error) {
+// 优先使用 information_schema.TABLES 中的平均行大小，没有统计信息或没有权限查询时按每列20字节估算
+func (c *Connection) EstimateRowSize(ctx context.Context, tableName string) (int64, error) {
+	var avgRowLength int64
+	query := "SELECT COALESCE(avg_row_length, 0) FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_name = ?"
+	if err := c.db.QueryRowContext(ctx, query, c.config.Database, tableName).Scan(&avgRowLength); err == nil && avgRowLength > 0 {
+		return avgRowLength, nil
+	}
+
 	// 获取表的列信息
-	columns, err := c.GetTableColumns(tableName)
+	columns, err := c.GetTableColumns(ctx, tableName)
 	if err != nil {
 		return 0, err
 	}
 
-	// 直接使用简单估算，避免查询information_schema.TABLES导致的权限问题
-	// 这样可以同时兼容MySQL 5.7和MySQL 8.0
 	// 简单估算：假设每列平均占用20字节
-	avgRowLength := int64(len(columns) * 20)
-
-	return avgRowLength, nil
+	return int64(len(columns) * 20), nil
 }
 
 // GetTableRowCount 获取表的行数
-func (c *Connection) GetTableRowCount(tableName string) (int64, error) {
+func (c *Connection) GetTableRowCount(ctx context.Context, tableName string) (int64, error) {
+	var count int64
+	err := c.db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM `%s`", tableName)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("获取表行数失败: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetTableRowCountWithFilter 获取满足附加WHERE条件的行数
+func (c *Connection) GetTableRowCountWithFilter(ctx context.Context, tableName string, filter string, filterArgs ...interface{}) (int64, error) {
+	if filter == "" {
+		return c.GetTableRowCount(ctx, tableName)
+	}
+
 	var count int64
-	err := c.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM `%s`", tableName)).Scan(&count)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE %s", tableName, filter)
+	err := c.db.QueryRowContext(ctx, query, filterArgs...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("获取表行数失败: %w", err)
 	}
@@ -255,10 +289,99 @@ func (c *Connection) GetTableRowCount(tableName string) (int64, error) {
 	return count, nil
 }
 
+// GetColumnMaxValue 获取列的最大值，用于增量同步确定本次水位上界
+// 返回值以字符串形式表示，表为空时 valid 为 false
+func (c *Connection) GetColumnMaxValue(ctx context.Context, tableName, columnName string) (value string, valid bool, err error) {
+	var maxValue sql.NullString
+	query := fmt.Sprintf("SELECT MAX(`%s`) FROM `%s`", columnName, tableName)
+	if err := c.db.QueryRowContext(ctx, query).Scan(&maxValue); err != nil {
+		return "", false, fmt.Errorf("获取列 %s 最大值失败: %w", columnName, err)
+	}
+
+	return maxValue.String, maxValue.Valid, nil
+}
+
+// GetColumnMinMax 获取整数列的最小值和最大值，表为空时 Valid 为 false
+// filter 为可选的附加WHERE条件（不含WHERE关键字）
+func (c *Connection) GetColumnMinMax(ctx context.Context, tableName, columnName, filter string) (minValue, maxValue sql.NullString, err error) {
+	query := fmt.Sprintf("SELECT CAST(MIN(`%s`) AS CHAR), CAST(MAX(`%s`) AS CHAR) FROM `%s`", columnName, columnName, tableName)
+	if filter != "" {
+		query += fmt.Sprintf(" WHERE %s", filter)
+	}
+	if err := c.db.QueryRowContext(ctx, query).Scan(&minValue, &maxValue); err != nil {
+		return minValue, maxValue, fmt.Errorf("获取列 %s 最小值和最大值失败: %w", columnName, err)
+	}
+	return minValue, maxValue, nil
+}
+
+// GetChunkBoundary 获取从 startValue（含）开始的第 chunkSize 个主键值，作为分块的上界
+// 剩余行数不足 chunkSize 时 ok 为 false，filter 为可选的附加WHERE条件（不含WHERE关键字）
+func (c *Connection) GetChunkBoundary(ctx context.Context, tableName, keyColumn string, startValue interface{}, chunkSize int, filter string) (value string, ok bool, err error) {
+	condition := fmt.Sprintf("`%s` >= ?", keyColumn)
+	if filter != "" {
+		condition = fmt.Sprintf("(%s) AND %s", filter, condition)
+	}
+	query := fmt.Sprintf("SELECT CAST(`%s` AS CHAR) FROM `%s` WHERE %s ORDER BY `%s` LIMIT 1 OFFSET %d",
+		keyColumn, tableName, condition, keyColumn, chunkSize-1)
+	if err := c.db.QueryRowContext(ctx, query, startValue).Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("获取表 %s 分块边界失败: %w", tableName, err)
+	}
+	return value, true, nil
+}
+
+// GetChecksum 计算满足条件的行数和与行顺序无关的校验和
+// rowExpr 为行内容的规范化文本表达式，每行取MD5前15位十六进制转为整数后求和
+func (c *Connection) GetChecksum(ctx context.Context, tableName, rowExpr, filter string, filterArgs ...interface{}) (count int64, checksum string, err error) {
+	query := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(CAST(CONV(SUBSTRING(MD5(%s), 1, 15), 16, 10) AS UNSIGNED)), 0) FROM `%s`",
+		rowExpr, tableName)
+	if filter != "" {
+		query += fmt.Sprintf(" WHERE %s", filter)
+	}
+	if err := c.db.QueryRowContext(ctx, query, filterArgs...).Scan(&count, &checksum); err != nil {
+		return 0, "", fmt.Errorf("计算表 %s 校验和失败: %w", tableName, err)
+	}
+	return count, checksum, nil
+}
+
+// GetRowHashes 获取满足条件的每行主键值及其内容MD5，limit 为0时不限制行数
+func (c *Connection) GetRowHashes(ctx context.Context, tableName, keyColumn, rowExpr string, limit int, filter string, filterArgs ...interface{}) (map[string]string, error) {
+	query := fmt.Sprintf("SELECT CAST(`%s` AS CHAR), MD5(%s) FROM `%s`", keyColumn, rowExpr, tableName)
+	if filter != "" {
+		query += fmt.Sprintf(" WHERE %s", filter)
+	}
+	query += fmt.Sprintf(" ORDER BY `%s`", keyColumn)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("获取表 %s 行校验值失败: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var key, hash string
+		if err := rows.Scan(&key, &hash); err != nil {
+			return nil, fmt.Errorf("扫描行校验值失败: %w", err)
+		}
+		hashes[key] = hash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("获取表 %s 行校验值失败: %w", tableName, err)
+	}
+
+	return hashes, nil
+}
+
 // GetVersion 获取MySQL版本信息
-func (c *Connection) GetVersion() (string, error) {
+func (c *Connection) GetVersion(ctx context.Context) (string, error) {
 	var version string
-	err := c.db.QueryRow("SELECT VERSION()").Scan(&version)
+	err := c.db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
 	if err != nil {
 		return "", fmt.Errorf("获取MySQL版本失败: %w", err)
 	}
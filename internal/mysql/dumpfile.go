@@ -0,0 +1,729 @@
+package mysql
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DumpFile mysqldump 导出的SQL文件，作为不连接MySQL时的转换数据源
+// 打开时读取一遍文件解析表结构、视图、函数、存储过程和触发器，ScanRows 再读取一遍文件解析 INSERT 语句中的数据
+type DumpFile struct {
+	path       string
+	Database   string // 文件中 USE 语句指定的数据库，没有时为空
+	Tables     []TableInfo
+	Views      []ViewInfo
+	Functions  []FunctionInfo
+	Procedures []string // 存储过程名，没有对应的转换，只记录
+	Triggers   []string // 触发器名，没有对应的转换，只记录
+}
+
+var (
+	// 语句类型的识别，版本注释已去除
+	reDumpUse       = regexp.MustCompile("(?is)^USE\\s+`([^`]+)`")
+	reDumpTable     = regexp.MustCompile("(?is)^CREATE\\s+(?:TEMPORARY\\s+)?TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?`([^`]+)`")
+	reDumpView      = regexp.MustCompile("(?is)^CREATE\\s+(?:OR\\s+REPLACE\\s+)?(?:ALGORITHM\\s*=\\s*\\w+\\s+)?(?:DEFINER\\s*=\\s*\\S+\\s+)?(?:SQL\\s+SECURITY\\s+\\w+\\s+)?VIEW\\s+`([^`]+)`\\s*(?:\\([^)]*\\)\\s*)?AS\\s+(.*)$")
+	reDumpRoutine   = regexp.MustCompile("(?is)^CREATE\\s+(?:DEFINER\\s*=\\s*\\S+\\s+)?(FUNCTION|PROCEDURE|TRIGGER)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?`([^`]+)`")
+	reDumpInsert    = regexp.MustCompile("(?is)^(?:INSERT|REPLACE)\\s+(?:IGNORE\\s+)?INTO\\s+`([^`]+)`\\s*")
+	reVersionNote   = regexp.MustCompile(`(?s)/\*!\d*\s?(.*?)\*/`)
+	reCheckOption   = regexp.MustCompile(`(?is)\s+WITH\s+(?:CASCADED\s+|LOCAL\s+)?CHECK\s+OPTION\s*$`)
+	reColumnDefault = regexp.MustCompile(`(?i)\sDEFAULT\s+('(?:[^'\\]|\\.|'')*'|\([^)]*\)|\S+)`)
+	reColumnComment = regexp.MustCompile(`(?i)\sCOMMENT\s+'((?:[^'\\]|\\.|'')*)'`)
+	reIndexLine     = regexp.MustCompile("(?i)^(PRIMARY\\s+KEY|UNIQUE\\s+(?:KEY|INDEX)|(?:FULLTEXT\\s+|SPATIAL\\s+)?(?:KEY|INDEX))\\s*(?:`([^`]+)`\\s*)?\\((.*)\\)")
+)
+
+// OpenDumpFile 打开mysqldump导出文件并解析其中的对象定义，文件名以 .gz 结尾时按gzip压缩文件读取
+func OpenDumpFile(path string) (*DumpFile, error) {
+	d := &DumpFile{path: path}
+
+	reader, err := openDumpReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	tableIndex := make(map[string]int)
+	views := make(map[string]int)
+	statements := newStatementReader(reader)
+	for {
+		stmt, err := statements.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取导出文件 %s 失败: %w", path, err)
+		}
+
+		// 数据语句只统计大小，用于按数据量调度和显示进度
+		if m := reDumpInsert.FindStringSubmatch(stmt); m != nil {
+			if i, ok := tableIndex[m[1]]; ok {
+				d.Tables[i].Size.DataLength += int64(len(stmt))
+			}
+			continue
+		}
+
+		stmt = strings.TrimSpace(reVersionNote.ReplaceAllString(stmt, "$1"))
+		switch {
+		case reDumpUse.MatchString(stmt):
+			if d.Database == "" {
+				d.Database = reDumpUse.FindStringSubmatch(stmt)[1]
+			}
+		case reDumpTable.MatchString(stmt):
+			table := parseDumpTable(reDumpTable.FindStringSubmatch(stmt)[1], stmt)
+			if i, ok := tableIndex[table.Name]; ok {
+				d.Tables[i] = table
+			} else {
+				tableIndex[table.Name] = len(d.Tables)
+				d.Tables = append(d.Tables, table)
+			}
+		case reDumpView.MatchString(stmt):
+			// 导出文件先为视图创建占位定义，之后再写入真正的定义，以最后一次定义为准
+			m := reDumpView.FindStringSubmatch(stmt)
+			view := ViewInfo{ViewName: m[1], ViewDefinition: reCheckOption.ReplaceAllString(strings.TrimSpace(m[2]), "")}
+			if i, ok := views[view.ViewName]; ok {
+				d.Views[i] = view
+			} else {
+				views[view.ViewName] = len(d.Views)
+				d.Views = append(d.Views, view)
+			}
+		case reDumpRoutine.MatchString(stmt):
+			m := reDumpRoutine.FindStringSubmatch(stmt)
+			switch strings.ToUpper(m[1]) {
+			case "FUNCTION":
+				d.Functions = append(d.Functions, FunctionInfo{Name: m[2], DDL: stmt, Parameters: functionParameters(stmt)})
+			case "PROCEDURE":
+				d.Procedures = append(d.Procedures, m[2])
+			case "TRIGGER":
+				d.Triggers = append(d.Triggers, m[2])
+			}
+		}
+	}
+
+	// MySQL 5.7 的导出文件先用同名的表作为视图的占位，这些表不是真正的表
+	if len(views) > 0 {
+		tables := d.Tables[:0]
+		for _, table := range d.Tables {
+			if _, isView := views[table.Name]; !isView {
+				tables = append(tables, table)
+			}
+		}
+		d.Tables = tables
+	}
+
+	return d, nil
+}
+
+// GetTables 获取导出文件中的表，按 use_table_list 和 skip_table_list 过滤，参数与 Connection.GetTables 相同
+func (d *DumpFile) GetTables(skipUseTableList bool, skipTableList []string, useTableList bool, tableList []string) []TableInfo {
+	names := make([]string, len(d.Tables))
+	tables := make(map[string]TableInfo, len(d.Tables))
+	for i, table := range d.Tables {
+		names[i] = table.Name
+		tables[table.Name] = table
+	}
+
+	var filtered []TableInfo
+	for _, name := range filterTableNames(names, skipUseTableList, skipTableList, useTableList, tableList) {
+		filtered = append(filtered, tables[name])
+	}
+	return filtered
+}
+
+// PrimaryKeys 获取表的主键列，没有主键时返回nil
+func (d *DumpFile) PrimaryKeys(tableName string) []string {
+	for _, table := range d.Tables {
+		if table.Name != tableName {
+			continue
+		}
+		for _, index := range table.Indexes {
+			if index.Name == "PRIMARY" {
+				return index.Columns
+			}
+		}
+	}
+	return nil
+}
+
+// ScanRows 按文件顺序读取 INSERT 语句中的每一行数据
+// columns 为 INSERT 语句的列清单，语句没有列清单时为表结构中的全部列；values 中的值与MySQL文本协议一致，为 []byte 或 nil
+// fn 返回错误时停止读取并返回该错误
+func (d *DumpFile) ScanRows(fn func(table string, columns []string, values []interface{}) error) error {
+	reader, err := openDumpReader(d.path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	tableColumns := make(map[string][]string, len(d.Tables))
+	for _, table := range d.Tables {
+		columns := make([]string, len(table.Columns))
+		for i, col := range table.Columns {
+			columns[i] = col.Name
+		}
+		tableColumns[table.Name] = columns
+	}
+
+	statements := newStatementReader(reader)
+	for {
+		stmt, err := statements.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取导出文件 %s 失败: %w", d.path, err)
+		}
+
+		m := reDumpInsert.FindStringSubmatchIndex(stmt)
+		if m == nil {
+			continue
+		}
+		table := stmt[m[2]:m[3]]
+		columns, ok := tableColumns[table]
+		if !ok {
+			// 视图的占位表等不在表清单中的表
+			continue
+		}
+		if err := parseInsertValues(stmt[m[1]:], columns, func(columns []string, values []interface{}) error {
+			return fn(table, columns, values)
+		}); err != nil {
+			return fmt.Errorf("解析表 %s 的 INSERT 语句失败: %w", table, err)
+		}
+	}
+}
+
+// dumpReader 导出文件的读取器，关闭时同时关闭gzip读取器和文件
+type dumpReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *dumpReader) Close() error {
+	var err error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if closeErr := r.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// openDumpReader 打开导出文件或查询日志，.gz 文件自动解压
+func openDumpReader(path string) (*dumpReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".gz") {
+		return &dumpReader{Reader: file, closers: []io.Closer{file}}, nil
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("读取gzip压缩的文件失败: %w", err)
+	}
+	return &dumpReader{Reader: gz, closers: []io.Closer{file, gz}}, nil
+}
+
+// statementReader 将导出文件拆分为SQL语句
+// 支持 DELIMITER 命令、字符串和标识符中的分隔符、-- 和 # 单行注释以及 /* */ 块注释；
+// /*! */ 版本注释中的内容是有效的SQL，按普通文本保留
+type statementReader struct {
+	reader    *bufio.Reader
+	delimiter string
+	line      string // 当前行未处理的部分
+	buf       []byte
+	quote     byte // 当前所在的字符串或标识符的引号，不在其中时为0
+	comment   bool // 是否在块注释中
+}
+
+func newStatementReader(r io.Reader) *statementReader {
+	return &statementReader{reader: bufio.NewReaderSize(r, 1<<20), delimiter: ";"}
+}
+
+// next 读取下一条语句，不含分隔符，文件结束时返回 io.EOF
+func (s *statementReader) next() (string, error) {
+	for {
+		if s.line == "" {
+			line, err := s.reader.ReadString('\n')
+			if line == "" && err != nil {
+				if err == io.EOF && len(bytes.TrimSpace(s.buf)) > 0 {
+					// 最后一条语句没有分隔符
+					stmt := strings.TrimSpace(string(s.buf))
+					s.buf = s.buf[:0]
+					return stmt, nil
+				}
+				return "", err
+			}
+			if err != nil && !errors.Is(err, io.EOF) {
+				return "", err
+			}
+
+			// DELIMITER 是客户端命令，只在语句开始处出现
+			if s.quote == 0 && !s.comment && len(bytes.TrimSpace(s.buf)) == 0 {
+				trimmed := strings.TrimSpace(line)
+				if len(trimmed) > 10 && strings.EqualFold(trimmed[:10], "DELIMITER ") {
+					s.delimiter = strings.TrimSpace(trimmed[10:])
+					continue
+				}
+			}
+			s.line = line
+		}
+
+		stmt, ok := s.scanLine()
+		if ok {
+			if stmt = strings.TrimSpace(stmt); stmt != "" {
+				return stmt, nil
+			}
+		}
+	}
+}
+
+// scanLine 处理当前行，遇到分隔符时返回完整的语句，当前行的剩余部分留待下次处理
+func (s *statementReader) scanLine() (string, bool) {
+	line := s.line
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case s.comment:
+			if c == '*' && i+1 < len(line) && line[i+1] == '/' {
+				s.comment = false
+				i++
+			}
+		case s.quote != 0:
+			s.buf = append(s.buf, c)
+			if c == '\\' && s.quote != '`' && i+1 < len(line) {
+				// 转义字符
+				i++
+				s.buf = append(s.buf, line[i])
+			} else if c == s.quote {
+				s.quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			s.quote = c
+			s.buf = append(s.buf, c)
+		case c == '#' || (c == '-' && strings.HasPrefix(line[i:], "--") && (i+2 == len(line) || line[i+2] == ' ' || line[i+2] == '\t' || line[i+2] == '\n' || line[i+2] == '\r')):
+			// 单行注释，保留换行
+			s.buf = append(s.buf, '\n')
+			s.line = ""
+			return "", false
+		case c == '/' && strings.HasPrefix(line[i:], "/*") && !strings.HasPrefix(line[i:], "/*!"):
+			s.comment = true
+			s.buf = append(s.buf, ' ')
+			i++
+		case strings.HasPrefix(line[i:], s.delimiter):
+			stmt := string(s.buf)
+			s.buf = s.buf[:0]
+			s.line = line[i+len(s.delimiter):]
+			return stmt, true
+		default:
+			s.buf = append(s.buf, c)
+		}
+	}
+	s.line = ""
+	return "", false
+}
+
+// SplitStatements 将SQL文本拆分为语句，不含分隔符和注释，规则与读取导出文件相同
+func SplitStatements(r io.Reader) ([]string, error) {
+	reader := newStatementReader(r)
+	var statements []string
+	for {
+		stmt, err := reader.next()
+		if err == io.EOF {
+			return statements, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取SQL语句失败: %w", err)
+		}
+		statements = append(statements, stmt)
+	}
+}
+
+// parseDumpTable 从 CREATE TABLE 语句（与 SHOW CREATE TABLE 的输出格式相同）解析列和索引
+func parseDumpTable(name, ddl string) TableInfo {
+	table := TableInfo{Name: name, DDL: ddl}
+	for _, line := range strings.Split(ddl, "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if strings.HasPrefix(line, "`") {
+			if col, ok := parseDumpColumn(line); ok {
+				table.Columns = append(table.Columns, col)
+			}
+			continue
+		}
+
+		m := reIndexLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		index := IndexInfo{Name: m[2], Table: name, Columns: parseIndexColumns(m[3])}
+		kind := strings.ToUpper(strings.Fields(m[1])[0])
+		if kind == "PRIMARY" {
+			index.Name = "PRIMARY"
+		}
+		index.IsUnique = kind == "PRIMARY" || kind == "UNIQUE"
+		table.Indexes = append(table.Indexes, index)
+	}
+	return table
+}
+
+// parseDumpColumn 解析列定义行，如 `name` varchar(64) NOT NULL DEFAULT 'x' COMMENT '名称'
+func parseDumpColumn(line string) (ColumnInfo, bool) {
+	end := strings.Index(line[1:], "`")
+	if end < 0 {
+		return ColumnInfo{}, false
+	}
+	col := ColumnInfo{Name: line[1 : end+1], Nullable: "YES"}
+	rest := strings.TrimSpace(line[end+2:])
+
+	// 类型到第一个不在括号和引号中的空白为止，unsigned 和 zerofill 属于类型的一部分
+	depth := 0
+	var quote byte
+	i := 0
+	for ; i < len(rest); i++ {
+		c := rest[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '\'' {
+			quote = c
+		} else if c == '(' {
+			depth++
+		} else if c == ')' {
+			depth--
+		} else if c == ' ' && depth == 0 {
+			break
+		}
+	}
+	col.Type = rest[:i]
+	options := rest[i:]
+	for _, modifier := range []string{" unsigned", " zerofill"} {
+		if strings.HasPrefix(strings.ToLower(options), modifier) {
+			col.Type += modifier
+			options = options[len(modifier):]
+		}
+	}
+
+	// 去除注释后再判断NOT NULL和默认值，避免注释内容的影响
+	var comment string
+	if m := reColumnComment.FindStringSubmatchIndex(options); m != nil {
+		comment = options[m[2]:m[3]]
+		options = options[:m[0]] + options[m[1]:]
+	}
+	col.Comment = string(unescapeDumpString(comment))
+	if strings.Contains(strings.ToUpper(options), "NOT NULL") {
+		col.Nullable = "NO"
+	}
+	if m := reColumnDefault.FindStringSubmatch(options); m != nil && !strings.EqualFold(m[1], "NULL") {
+		value := m[1]
+		if strings.HasPrefix(value, "'") {
+			value = string(unescapeDumpString(value[1 : len(value)-1]))
+		}
+		col.Default = &value
+	}
+	return col, true
+}
+
+// parseIndexColumns 解析索引列清单，去除前缀长度和排序方向，跳过基于表达式的索引列
+func parseIndexColumns(list string) []string {
+	var columns []string
+	for _, part := range splitTopLevel(list) {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(part, "`") {
+			continue
+		}
+		if end := strings.Index(part[1:], "`"); end >= 0 {
+			columns = append(columns, part[1:end+1])
+		}
+	}
+	return columns
+}
+
+// splitTopLevel 按不在括号和引号中的逗号拆分
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseInsertValues 解析 INSERT 语句表名之后的部分：可选的列清单和 VALUES 中的每一行
+func parseInsertValues(s string, columns []string, fn func(columns []string, values []interface{}) error) error {
+	p := &valueParser{s: s}
+	p.skipSpace()
+	if p.peek() == '(' {
+		columns = nil
+		p.pos++
+		end := strings.IndexByte(p.s[p.pos:], ')')
+		if end < 0 {
+			return fmt.Errorf("列清单没有结束")
+		}
+		for _, part := range strings.Split(p.s[p.pos:p.pos+end], ",") {
+			columns = append(columns, strings.Trim(strings.TrimSpace(part), "`"))
+		}
+		p.pos += end + 1
+		p.skipSpace()
+	}
+	if !p.keyword("VALUES") && !p.keyword("VALUE") {
+		return fmt.Errorf("不支持的 INSERT 语句格式，位置 %d", p.pos)
+	}
+
+	for {
+		p.skipSpace()
+		if p.peek() != '(' {
+			return fmt.Errorf("位置 %d 处应为 (", p.pos)
+		}
+		p.pos++
+		values := make([]interface{}, 0, len(columns))
+		for {
+			value, err := p.value()
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+			p.skipSpace()
+			c := p.peek()
+			p.pos++
+			if c == ')' {
+				break
+			}
+			if c != ',' {
+				return fmt.Errorf("位置 %d 处应为 , 或 )", p.pos-1)
+			}
+		}
+		if len(values) != len(columns) {
+			return fmt.Errorf("行的值个数 %d 与列数 %d 不一致", len(values), len(columns))
+		}
+		if err := fn(columns, values); err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		if p.peek() != ',' {
+			// ON DUPLICATE KEY UPDATE 等后续子句不影响导入的数据
+			return nil
+		}
+		p.pos++
+	}
+}
+
+// valueParser INSERT 语句中值的解析器
+type valueParser struct {
+	s   string
+	pos int
+}
+
+func (p *valueParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *valueParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\n' || p.s[p.pos] == '\r' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// keyword 当前位置是指定的关键字（不区分大小写）时跳过并返回 true
+func (p *valueParser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.s) || !strings.EqualFold(p.s[p.pos:end], word) {
+		return false
+	}
+	if end < len(p.s) && isIdentifierByte(p.s[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// value 解析一个值：字符串、NULL、数字、0x 和 X'...' 十六进制、b'...' 位串，字符串可带 _binary 等字符集前缀
+func (p *valueParser) value() (interface{}, error) {
+	p.skipSpace()
+	if p.peek() == '_' {
+		// 字符集前缀，如 _binary、_utf8mb4
+		for p.pos < len(p.s) && isIdentifierByte(p.s[p.pos]) {
+			p.pos++
+		}
+		p.skipSpace()
+	}
+
+	rest := p.s[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, "'"):
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.s) {
+			c := p.s[p.pos]
+			if c == '\\' {
+				p.pos += 2
+				continue
+			}
+			if c == '\'' {
+				if p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'' {
+					p.pos += 2
+					continue
+				}
+				value := unescapeDumpString(p.s[start:p.pos])
+				p.pos++
+				return value, nil
+			}
+			p.pos++
+		}
+		return nil, fmt.Errorf("位置 %d 处的字符串没有结束", start-1)
+	case len(rest) > 1 && (rest[0] == 'x' || rest[0] == 'X') && rest[1] == '\'':
+		end := strings.IndexByte(rest[2:], '\'')
+		if end < 0 {
+			return nil, fmt.Errorf("位置 %d 处的十六进制值没有结束", p.pos)
+		}
+		p.pos += end + 3
+		return decodeHex(rest[2 : end+2])
+	case len(rest) > 1 && (rest[0] == 'b' || rest[0] == 'B') && rest[1] == '\'':
+		end := strings.IndexByte(rest[2:], '\'')
+		if end < 0 {
+			return nil, fmt.Errorf("位置 %d 处的位串没有结束", p.pos)
+		}
+		p.pos += end + 3
+		return decodeBits(rest[2 : end+2])
+	case strings.HasPrefix(rest, "0x") || strings.HasPrefix(rest, "0X"):
+		end := 2
+		for end < len(rest) && isHexByte(rest[end]) {
+			end++
+		}
+		p.pos += end
+		return decodeHex(rest[2:end])
+	}
+
+	// NULL 或数字等不带引号的值
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != ',' && p.s[p.pos] != ')' {
+		p.pos++
+	}
+	token := strings.TrimSpace(p.s[start:p.pos])
+	if token == "" {
+		return nil, fmt.Errorf("位置 %d 处缺少值", start)
+	}
+	if strings.EqualFold(token, "NULL") {
+		return nil, nil
+	}
+	return []byte(token), nil
+}
+
+// unescapeDumpString 还原MySQL字符串中的转义字符
+func unescapeDumpString(s string) []byte {
+	value := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+			value = append(value, '\'')
+			i++
+			continue
+		}
+		if c != '\\' || i+1 == len(s) {
+			value = append(value, c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case '0':
+			value = append(value, 0)
+		case 'b':
+			value = append(value, '\b')
+		case 'n':
+			value = append(value, '\n')
+		case 'r':
+			value = append(value, '\r')
+		case 't':
+			value = append(value, '\t')
+		case 'Z':
+			value = append(value, 0x1a)
+		case '%', '_':
+			// LIKE 的通配符转义保留反斜杠
+			value = append(value, '\\', s[i])
+		default:
+			value = append(value, s[i])
+		}
+	}
+	return value
+}
+
+// decodeHex 解码十六进制文本，奇数位时在前面补0
+func decodeHex(s string) ([]byte, error) {
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	value := make([]byte, len(s)/2)
+	for i := range value {
+		hi, lo := hexValue(s[2*i]), hexValue(s[2*i+1])
+		if hi < 0 || lo < 0 {
+			return nil, fmt.Errorf("无效的十六进制值: %s", s)
+		}
+		value[i] = byte(hi<<4 | lo)
+	}
+	return value, nil
+}
+
+// decodeBits 将位串文本按大端序转换为字节，与MySQL返回的BIT列值一致
+func decodeBits(s string) ([]byte, error) {
+	value := make([]byte, (len(s)+7)/8)
+	for i := 0; i < len(s); i++ {
+		// 第 i 位在值中从最低位算起的位置
+		pos := len(s) - 1 - i
+		switch s[i] {
+		case '1':
+			value[len(value)-1-pos/8] |= 1 << (pos % 8)
+		case '0':
+		default:
+			return nil, fmt.Errorf("无效的位串: %s", s)
+		}
+	}
+	return value, nil
+}
+
+func hexValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
+
+func isHexByte(c byte) bool {
+	return hexValue(c) >= 0
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
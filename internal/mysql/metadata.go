@@ -15,6 +15,14 @@ type TableInfo struct {
 	DDL     string
 	Columns []ColumnInfo
 	Indexes []IndexInfo
+	Size    TableSize
+}
+
+// TableSize 表大小的估计值，来自 information_schema.TABLES 的统计信息
+type TableSize struct {
+	Rows         int64 // 估计行数（TABLE_ROWS）
+	DataLength   int64 // 数据大小（DATA_LENGTH），单位字节
+	AvgRowLength int64 // 平均行大小（AVG_ROW_LENGTH），单位字节
 }
 
 // ColumnInfo 列信息
@@ -55,10 +63,10 @@ type ViewInfo struct {
 }
 
 // GetTables 获取所有表信息
-func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, useTableList bool, tableList []string) ([]TableInfo, error) {
+func (c *Connection) GetTables(ctx context.Context, skipUseTableList bool, skipTableList []string, useTableList bool, tableList []string) ([]TableInfo, error) {
 	// 获取当前连接的用户名，以便更好地诊断权限问题
 	var currentUser string
-	if err := c.db.QueryRow("SELECT USER()").Scan(&currentUser); err != nil {
+	if err := c.db.QueryRowContext(ctx, "SELECT USER()").Scan(&currentUser); err != nil {
 		return nil, fmt.Errorf("获取当前用户名失败: %w", err)
 	}
 
@@ -66,9 +74,9 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 	var rows *sql.Rows
 	var err error
 
-	// 使用INFORMATION_SCHEMA.TABLES查询，只获取TABLE类型的对象，过滤掉视图
-	query := "SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_type = 'BASE TABLE'"
-	rows, err = c.db.Query(query, c.config.Database)
+	// 使用INFORMATION_SCHEMA.TABLES查询，只获取TABLE类型的对象，过滤掉视图，同时获取表大小的统计信息
+	query := "SELECT table_name, COALESCE(table_rows, 0), COALESCE(data_length, 0), COALESCE(avg_row_length, 0) FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_type = 'BASE TABLE'"
+	rows, err = c.db.QueryContext(ctx, query, c.config.Database)
 
 	if err != nil {
 		// 如果失败，返回包含当前用户名的详细错误信息
@@ -77,49 +85,18 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 	defer rows.Close()
 
 	var tableNames []string
+	tableSizes := make(map[string]TableSize)
 	for rows.Next() {
 		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var size TableSize
+		if err := rows.Scan(&tableName, &size.Rows, &size.DataLength, &size.AvgRowLength); err != nil {
 			return nil, fmt.Errorf("扫描表名失败: %w", err)
 		}
 		tableNames = append(tableNames, tableName)
+		tableSizes[tableName] = size
 	}
 
-	// 在应用层面过滤只同步的表
-	if useTableList && len(tableList) > 0 {
-		// 创建一个map用于快速查找需要同步的表
-		useMap := make(map[string]bool)
-		for _, table := range tableList {
-			useMap[table] = true
-		}
-
-		// 过滤表名列表
-		filteredTableNames := make([]string, 0, len(tableNames))
-		for _, tableName := range tableNames {
-			if useMap[tableName] {
-				filteredTableNames = append(filteredTableNames, tableName)
-			}
-		}
-		tableNames = filteredTableNames
-	}
-
-	// 在应用层面过滤掉需要跳过的表
-	if skipUseTableList && len(skipTableList) > 0 {
-		// 创建一个map用于快速查找需要跳过的表
-		skipMap := make(map[string]bool)
-		for _, table := range skipTableList {
-			skipMap[table] = true
-		}
-
-		// 过滤表名列表
-		filteredTableNames := make([]string, 0, len(tableNames))
-		for _, tableName := range tableNames {
-			if !skipMap[tableName] {
-				filteredTableNames = append(filteredTableNames, tableName)
-			}
-		}
-		tableNames = filteredTableNames
-	}
+	tableNames = filterTableNames(tableNames, skipUseTableList, skipTableList, useTableList, tableList)
 
 	// 使用并发获取表信息
 	type tableResult struct {
@@ -144,7 +121,7 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 			defer func() { <-semaphore }()
 
 			// 创建一个带超时的上下文
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			// 使用带超时的查询获取表DDL
@@ -152,7 +129,7 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 			query := fmt.Sprintf("SHOW CREATE TABLE `%s`", name)
 
 			// 使用Rows而不是Row，以便动态获取列数
-			rows, err := c.db.QueryContext(ctx, query)
+			rows, err := c.db.QueryContext(queryCtx, query)
 			if err != nil {
 				// 检查错误是否是因为权限不足导致的SHOW VIEW命令被拒绝
 				if strings.Contains(err.Error(), "SHOW VIEW command denied") || strings.Contains(err.Error(), "1142") {
@@ -210,14 +187,14 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 			}
 
 			// 获取表的列信息
-			tableColumns, err := c.getTableColumns(name)
+			tableColumns, err := c.getTableColumns(ctx, name)
 			if err != nil {
 				resultChan <- tableResult{err: fmt.Errorf("获取表列信息失败: %w", err)}
 				return
 			}
 
 			// 获取表的索引信息
-			indexes, err := c.getTableIndexes(name)
+			indexes, err := c.getTableIndexes(ctx, name)
 			if err != nil {
 				resultChan <- tableResult{err: fmt.Errorf("获取表索引信息失败: %w", err)}
 				return
@@ -229,6 +206,7 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 					DDL:     ddl,
 					Columns: tableColumns,
 					Indexes: indexes,
+					Size:    tableSizes[name],
 				},
 			}
 		}(tableName)
@@ -256,10 +234,10 @@ func (c *Connection) GetTables(skipUseTableList bool, skipTableList []string, us
 }
 
 // getTableColumns 获取表的列信息
-func (c *Connection) getTableColumns(tableName string) ([]ColumnInfo, error) {
+func (c *Connection) getTableColumns(ctx context.Context, tableName string) ([]ColumnInfo, error) {
 	// 使用反引号包围表名，以处理包含特殊字符的表名
 	query := fmt.Sprintf("SHOW FULL COLUMNS FROM `%s`", tableName)
-	rows, err := c.db.Query(query)
+	rows, err := c.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -292,7 +270,7 @@ func (c *Connection) getTableColumns(tableName string) ([]ColumnInfo, error) {
 }
 
 // getTableIndexes 获取表的索引信息
-func (c *Connection) getTableIndexes(tableName string) ([]IndexInfo, error) {
+func (c *Connection) getTableIndexes(ctx context.Context, tableName string) ([]IndexInfo, error) {
 	// 使用information_schema.statistics查询索引信息，兼容MySQL 5.7和MySQL 8.0
 	// 只查询需要的字段：table_name, index_name, non_unique, column_name, seq_in_index
 	query := `
@@ -301,7 +279,7 @@ func (c *Connection) getTableIndexes(tableName string) ([]IndexInfo, error) {
 		WHERE table_schema = ? AND table_name = ? 
 		ORDER BY index_name, seq_in_index
 	`
-	rows, err := c.db.Query(query, c.config.Database, tableName)
+	rows, err := c.db.QueryContext(ctx, query, c.config.Database, tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -344,14 +322,14 @@ func (c *Connection) getTableIndexes(tableName string) ([]IndexInfo, error) {
 }
 
 // GetViews 获取所有视图信息
-func (c *Connection) GetViews(database string) ([]ViewInfo, error) {
+func (c *Connection) GetViews(ctx context.Context, database string) ([]ViewInfo, error) {
 	// 查询视图定义
 	query := `
 		SELECT table_name, view_definition 
 		FROM INFORMATION_SCHEMA.VIEWS 
 		WHERE table_schema = ?
 	`
-	rows, err := c.db.Query(query, database)
+	rows, err := c.db.QueryContext(ctx, query, database)
 	if err != nil {
 		return nil, fmt.Errorf("查询视图定义失败: %w", err)
 	}
@@ -374,12 +352,12 @@ func (c *Connection) GetViews(database string) ([]ViewInfo, error) {
 }
 
 // GetFunctions 获取所有函数信息
-func (c *Connection) GetFunctions() ([]FunctionInfo, error) {
+func (c *Connection) GetFunctions(ctx context.Context) ([]FunctionInfo, error) {
 	// 使用SHOW FUNCTION STATUS获取函数列表，避免查询information_schema导致的权限问题
 	// 这样可以同时兼容MySQL 5.7和MySQL 8.0
 	query := fmt.Sprintf("SHOW FUNCTION STATUS WHERE Db = '%s'", c.config.Database)
 
-	rows, err := c.db.Query(query)
+	rows, err := c.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("获取函数列表失败: %w", err)
 	}
@@ -433,7 +411,7 @@ func (c *Connection) GetFunctions() ([]FunctionInfo, error) {
 	for _, funcName := range functionNames {
 		// 使用SHOW CREATE FUNCTION获取函数定义
 		funcQuery := fmt.Sprintf("SHOW CREATE FUNCTION `%s`", funcName)
-		funcRows, err := c.db.Query(funcQuery)
+		funcRows, err := c.db.QueryContext(ctx, funcQuery)
 		if err != nil {
 			// 如果获取某个函数的定义失败，跳过该函数，继续处理其他函数
 			continue
@@ -497,26 +475,7 @@ func (c *Connection) GetFunctions() ([]FunctionInfo, error) {
 			returnType := ""
 
 			// 从函数体中解析参数
-			parameters := ""
-			if idx := strings.Index(definition, "("); idx != -1 {
-				// 寻找匹配的右括号
-				count := 1
-				endIdx := idx + 1
-				for endIdx < len(definition) {
-					if definition[endIdx] == '(' {
-						count++
-					} else if definition[endIdx] == ')' {
-						count--
-						if count == 0 {
-							break
-						}
-					}
-					endIdx++
-				}
-				if endIdx < len(definition) {
-					parameters = definition[idx+1 : endIdx]
-				}
-			}
+			parameters := functionParameters(definition)
 
 			functions = append(functions, FunctionInfo{
 				Name:       name,
@@ -530,10 +489,65 @@ func (c *Connection) GetFunctions() ([]FunctionInfo, error) {
 	return functions, nil
 }
 
+// GetProcedureNames 获取存储过程名，存储过程没有对应的转换，只用于评估报告
+func (c *Connection) GetProcedureNames(ctx context.Context) ([]string, error) {
+	names, err := c.showNames(ctx, fmt.Sprintf("SHOW PROCEDURE STATUS WHERE Db = '%s'", c.config.Database), "name")
+	if err != nil {
+		return nil, fmt.Errorf("获取存储过程列表失败: %w", err)
+	}
+	return names, nil
+}
+
+// GetTriggerNames 获取触发器名，触发器没有对应的转换，只用于评估报告
+func (c *Connection) GetTriggerNames(ctx context.Context) ([]string, error) {
+	names, err := c.showNames(ctx, fmt.Sprintf("SHOW TRIGGERS FROM `%s`", c.config.Database), "trigger")
+	if err != nil {
+		return nil, fmt.Errorf("获取触发器列表失败: %w", err)
+	}
+	return names, nil
+}
+
+// showNames 执行SHOW语句并返回指定列的值，按列名读取以兼容不同MySQL版本返回的字段数
+func (c *Connection) showNames(ctx context.Context, query, column string) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, col := range columns {
+		if strings.ToLower(col) == column {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("结果中没有 %s 列", column)
+	}
+
+	var names []string
+	values := make([]sql.RawBytes, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+		names = append(names, string(values[index]))
+	}
+	return names, rows.Err()
+}
+
 // GetUsers 获取所有用户信息
-func (c *Connection) GetUsers() ([]UserInfo, error) {
+func (c *Connection) GetUsers(ctx context.Context) ([]UserInfo, error) {
 	// MySQL中获取用户权限
-	rows, err := c.db.Query(`
+	rows, err := c.db.QueryContext(ctx, `
 		SELECT user, host 
 		FROM mysql.user 
 		WHERE user != 'root' AND user != 'mysql.sys' AND user != 'mysql.session' AND user != 'mysql.infoschema'
@@ -551,7 +565,7 @@ func (c *Connection) GetUsers() ([]UserInfo, error) {
 		}
 
 		// 获取用户权限
-		grants, err := c.getUserGrants(userName, host)
+		grants, err := c.getUserGrants(ctx, userName, host)
 		if err != nil {
 			return nil, fmt.Errorf("获取用户权限失败: %w", err)
 		}
@@ -566,11 +580,11 @@ func (c *Connection) GetUsers() ([]UserInfo, error) {
 }
 
 // getUserGrants 获取用户的权限信息
-func (c *Connection) getUserGrants(userName, host string) ([]string, error) {
+func (c *Connection) getUserGrants(ctx context.Context, userName, host string) ([]string, error) {
 	var grantsStr string
 	// 直接使用字符串拼接构建查询语句
 	grantQuery := fmt.Sprintf("SHOW GRANTS FOR '%s'@'%s'", userName, host)
-	err := c.db.QueryRow(grantQuery).Scan(&grantsStr)
+	err := c.db.QueryRowContext(ctx, grantQuery).Scan(&grantsStr)
 	if err != nil {
 		return nil, err
 	}
@@ -598,14 +612,14 @@ type TablePrivInfo struct {
 }
 
 // GetTablePrivileges 获取表权限信息
-func (c *Connection) GetTablePrivileges() ([]TablePrivInfo, error) {
+func (c *Connection) GetTablePrivileges(ctx context.Context) ([]TablePrivInfo, error) {
 	query := `
 		SELECT Host, Db, User, Table_name, Table_priv 
 		FROM mysql.tables_priv 
 		WHERE Table_priv != ''
 	`
 
-	rows, err := c.db.Query(query)
+	rows, err := c.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("获取表权限失败: %w", err)
 	}
@@ -623,3 +637,68 @@ func (c *Connection) GetTablePrivileges() ([]TablePrivInfo, error) {
 
 	return privileges, nil
 }
+
+// filterTableNames 按 use_table_list 和 skip_table_list 过滤表名
+func filterTableNames(tableNames []string, skipUseTableList bool, skipTableList []string, useTableList bool, tableList []string) []string {
+	// 在应用层面过滤只同步的表
+	if useTableList && len(tableList) > 0 {
+		// 创建一个map用于快速查找需要同步的表
+		useMap := make(map[string]bool)
+		for _, table := range tableList {
+			useMap[table] = true
+		}
+
+		// 过滤表名列表
+		filteredTableNames := make([]string, 0, len(tableNames))
+		for _, tableName := range tableNames {
+			if useMap[tableName] {
+				filteredTableNames = append(filteredTableNames, tableName)
+			}
+		}
+		tableNames = filteredTableNames
+	}
+
+	// 在应用层面过滤掉需要跳过的表
+	if skipUseTableList && len(skipTableList) > 0 {
+		// 创建一个map用于快速查找需要跳过的表
+		skipMap := make(map[string]bool)
+		for _, table := range skipTableList {
+			skipMap[table] = true
+		}
+
+		// 过滤表名列表
+		filteredTableNames := make([]string, 0, len(tableNames))
+		for _, tableName := range tableNames {
+			if !skipMap[tableName] {
+				filteredTableNames = append(filteredTableNames, tableName)
+			}
+		}
+		tableNames = filteredTableNames
+	}
+	return tableNames
+}
+
+// functionParameters 从函数定义中解析参数列表（第一对括号中的内容）
+func functionParameters(definition string) string {
+	parameters := ""
+	if idx := strings.Index(definition, "("); idx != -1 {
+		// 寻找匹配的右括号
+		count := 1
+		endIdx := idx + 1
+		for endIdx < len(definition) {
+			if definition[endIdx] == '(' {
+				count++
+			} else if definition[endIdx] == ')' {
+				count--
+				if count == 0 {
+					break
+				}
+			}
+			endIdx++
+		}
+		if endIdx < len(definition) {
+			parameters = definition[idx+1 : endIdx]
+		}
+	}
+	return parameters
+}
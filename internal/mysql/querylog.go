@@ -0,0 +1,129 @@
+package mysql
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reGeneralLogEntry 通用查询日志的记录行，如 "2024-01-01T10:00:00.123456Z\t   12 Query\tSELECT 1"，
+	// 5.x 的格式为 "240101 10:00:00\t   12 Query\tSELECT 1"，同一秒内的后续记录没有时间
+	reGeneralLogEntry = regexp.MustCompile(`^(?:\d{4}-\d{2}-\d{2}T\S+|\d{6}\s+\d{1,2}:\d{2}:\d{2})?\s*\d+\s+([A-Z][a-z]*(?: [A-Za-z]+)?)\t(.*)$`)
+	// reLogBanner 服务启动时写入日志的文件头
+	reLogBanner = regexp.MustCompile(`^(\S+, Version: .* started with:|Tcp port: \d+.*|Time\s+Id\s+Command\s+Argument)\s*$`)
+	// 查询指纹的规范化
+	reFingerprintNumber = regexp.MustCompile(`\b(0x[0-9a-fA-F]+|\d+(\.\d+)?([eE][+-]?\d+)?)\b`)
+	reFingerprintSpace  = regexp.MustCompile(`\s+`)
+	reFingerprintIn     = regexp.MustCompile(`(?i)\bin\s*\(\s*\?(\s*,\s*\?)*\s*\)`)
+	reFingerprintValues = regexp.MustCompile(`(\(\s*\?(?:\s*,\s*\?)*\s*\))(?:\s*,\s*\(\s*\?(?:\s*,\s*\?)*\s*\))+`)
+)
+
+// ScanQueryLog 读取MySQL通用查询日志或慢查询日志，对其中的每条SQL语句调用 fn
+// 通用查询日志只读取 Query 和 Execute 记录；慢查询日志以 # 开头的行为记录头，其后为语句；两种格式自动识别
+func ScanQueryLog(r io.Reader, fn func(query string) error) error {
+	reader := bufio.NewReaderSize(r, 1<<20)
+	var block strings.Builder
+	skip := false // 当前记录不是SQL语句，如 Connect、Quit
+
+	flush := func() error {
+		text := block.String()
+		block.Reset()
+		if skip || strings.TrimSpace(text) == "" {
+			return nil
+		}
+		statements, err := SplitStatements(strings.NewReader(text))
+		if err != nil {
+			return err
+		}
+		for _, stmt := range statements {
+			if err := fn(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line == "" && err != nil {
+			if !errors.Is(err, io.EOF) {
+				return fmt.Errorf("读取查询日志失败: %w", err)
+			}
+			return flush()
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("读取查询日志失败: %w", err)
+		}
+
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case reLogBanner.MatchString(trimmed):
+			continue
+		case strings.HasPrefix(trimmed, "# "):
+			// 慢查询日志的记录头
+			if err := flush(); err != nil {
+				return err
+			}
+			skip = false
+			continue
+		}
+		if m := reGeneralLogEntry.FindStringSubmatch(trimmed); m != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+			skip = m[1] != "Query" && m[1] != "Execute"
+			block.WriteString(m[2])
+			block.WriteString("\n")
+			continue
+		}
+		block.WriteString(line)
+	}
+}
+
+// ScanQueryLogFile 读取查询日志文件，.gz 文件自动解压
+func ScanQueryLogFile(path string, fn func(query string) error) error {
+	reader, err := openDumpReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return ScanQueryLog(reader, fn)
+}
+
+// FingerprintQuery 计算查询指纹：字符串和数字常量替换为 ?，IN 列表和多行 VALUES 合并，空白压缩并转为小写
+// 只有常量不同的语句指纹相同
+func FingerprintQuery(query string) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c != '\'' && c != '"' {
+			b.WriteByte(c)
+			continue
+		}
+		// 跳过字符串常量，支持反斜杠转义和重复引号
+		for i++; i < len(query); i++ {
+			if query[i] == '\\' {
+				i++
+				continue
+			}
+			if query[i] == c {
+				if i+1 < len(query) && query[i+1] == c {
+					i++
+					continue
+				}
+				break
+			}
+		}
+		b.WriteByte('?')
+	}
+
+	fingerprint := reFingerprintNumber.ReplaceAllString(b.String(), "?")
+	fingerprint = strings.ToLower(strings.TrimSpace(reFingerprintSpace.ReplaceAllString(fingerprint, " ")))
+	fingerprint = reFingerprintIn.ReplaceAllString(fingerprint, "in (?+)")
+	fingerprint = reFingerprintValues.ReplaceAllString(fingerprint, "$1")
+	return fingerprint
+}
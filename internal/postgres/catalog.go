@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CatalogColumn 系统目录中的列定义
+type CatalogColumn struct {
+	Name    string
+	Type    string // format_type 格式的类型，如 character varying(255)
+	NotNull bool
+	Default string // pg_get_expr 格式的默认值表达式
+}
+
+// CatalogIndex 系统目录中的索引定义
+type CatalogIndex struct {
+	Name      string
+	Columns   []string // 表达式索引的表达式列为空字符串
+	IsUnique  bool
+	IsPrimary bool
+}
+
+// CatalogTable 系统目录中的表定义
+type CatalogTable struct {
+	Name    string
+	Columns []CatalogColumn
+	Indexes []CatalogIndex
+}
+
+// reCreateTable 建表语句的开头，用于改为创建临时表
+var reCreateTable = regexp.MustCompile(`(?i)^\s*CREATE\s+(TEMPORARY\s+)?TABLE\s+`)
+
+// catalogColumnsQuery 查询指定schema中所有表的列定义，%s 为schema的oid表达式
+const catalogColumnsQuery = `
+	SELECT c.relname::text, a.attname::text, format_type(a.atttypid, a.atttypmod), a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE c.relnamespace = %s AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY c.relname, a.attnum`
+
+// catalogIndexesQuery 查询当前schema中所有表的索引及索引列
+const catalogIndexesQuery = `
+	SELECT t.relname::text, i.relname::text, x.indisunique, x.indisprimary,
+		ARRAY(SELECT COALESCE(a.attname::text, '') FROM unnest(x.indkey) WITH ORDINALITY AS k(attnum, n)
+			LEFT JOIN pg_attribute a ON a.attrelid = x.indrelid AND a.attnum = k.attnum ORDER BY k.n)
+	FROM pg_index x
+	JOIN pg_class t ON t.oid = x.indrelid
+	JOIN pg_class i ON i.oid = x.indexrelid
+	WHERE t.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())
+	ORDER BY t.relname, i.relname`
+
+// GetCatalogTables 获取当前schema中所有表的列和索引定义，键为表名
+func (c *Connection) GetCatalogTables(ctx context.Context) (map[string]*CatalogTable, error) {
+	tables := make(map[string]*CatalogTable)
+
+	rows, err := c.pool.Query(ctx, fmt.Sprintf(catalogColumnsQuery, "(SELECT oid FROM pg_namespace WHERE nspname = current_schema())"))
+	if err != nil {
+		return nil, fmt.Errorf("查询表的列定义失败: %w", err)
+	}
+	for rows.Next() {
+		var tableName string
+		var column CatalogColumn
+		if err := rows.Scan(&tableName, &column.Name, &column.Type, &column.NotNull, &column.Default); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描表的列定义失败: %w", err)
+		}
+		if tables[tableName] == nil {
+			tables[tableName] = &CatalogTable{Name: tableName}
+		}
+		tables[tableName].Columns = append(tables[tableName].Columns, column)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询表的列定义失败: %w", err)
+	}
+
+	rows, err = c.pool.Query(ctx, catalogIndexesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("查询索引定义失败: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tableName string
+		var index CatalogIndex
+		if err := rows.Scan(&tableName, &index.Name, &index.IsUnique, &index.IsPrimary, &index.Columns); err != nil {
+			return nil, fmt.Errorf("扫描索引定义失败: %w", err)
+		}
+		if table := tables[tableName]; table != nil {
+			table.Indexes = append(table.Indexes, index)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询索引定义失败: %w", err)
+	}
+	return tables, nil
+}
+
+// ExpectedColumns 按建表DDL在临时表中创建表 tableName 并读取列定义，事务结束时回滚，不影响目标schema
+// 用于按PostgreSQL自身的规则规范化期望的列类型和默认值，与 GetCatalogTables 的结果直接比较
+func (c *Connection) ExpectedColumns(ctx context.Context, tableName, ddl string) ([]CatalogColumn, error) {
+	// 与 ExecuteDDL 一致，将char(0)转换为char(10)
+	ddl = strings.ReplaceAll(ddl, "char(0)", "char(10)")
+	ddl = reCreateTable.ReplaceAllString(ddl, "CREATE TEMPORARY TABLE ")
+
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(ctx, ddl); err != nil {
+		return nil, fmt.Errorf("创建临时表失败: %w", err)
+	}
+	rows, err := tx.Query(ctx, fmt.Sprintf(catalogColumnsQuery, "pg_my_temp_schema()"))
+	if err != nil {
+		return nil, fmt.Errorf("查询临时表的列定义失败: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []CatalogColumn
+	for rows.Next() {
+		var relName string
+		var column CatalogColumn
+		if err := rows.Scan(&relName, &column.Name, &column.Type, &column.NotNull, &column.Default); err != nil {
+			return nil, fmt.Errorf("扫描临时表的列定义失败: %w", err)
+		}
+		// 连接中可能还有其他临时表
+		if relName == tableName {
+			columns = append(columns, column)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询临时表的列定义失败: %w", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("建表DDL没有创建表 %s", tableName)
+	}
+	return columns, nil
+}
+
+// GetRoutineNames 获取当前schema中的函数和存储过程名
+func (c *Connection) GetRoutineNames(ctx context.Context) (map[string]bool, error) {
+	return c.queryNames(ctx, `SELECT p.proname FROM pg_proc p WHERE p.pronamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())`)
+}
+
+// GetViewNames 获取当前schema中的视图名
+func (c *Connection) GetViewNames(ctx context.Context) (map[string]bool, error) {
+	return c.queryNames(ctx, `SELECT c.relname FROM pg_class c WHERE c.relkind IN ('v', 'm') AND c.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())`)
+}
+
+// queryNames 执行返回名称列的查询
+func (c *Connection) queryNames(ctx context.Context, query string) (map[string]bool, error) {
+	rows, err := c.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("查询系统目录失败: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("扫描系统目录失败: %w", err)
+		}
+		names[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询系统目录失败: %w", err)
+	}
+	return names, nil
+}
+
+// CurrentSchema 获取连接的当前schema
+func (c *Connection) CurrentSchema(ctx context.Context) (string, error) {
+	var schema string
+	if err := c.pool.QueryRow(ctx, "SELECT current_schema()").Scan(&schema); err != nil {
+		return "", fmt.Errorf("获取当前schema失败: %w", err)
+	}
+	return schema, nil
+}
+
+// CheckQuery 检查语句能否在PostgreSQL中执行：explain 为true时执行 EXPLAIN（不执行语句本身），否则只准备语句
+// 在回滚的事务中进行，带参数占位符 $n 的语句只能准备
+func (c *Connection) CheckQuery(ctx context.Context, query string, explain bool) error {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	// 复杂查询的计划也可能很慢，避免长时间阻塞
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = '10s'"); err != nil {
+		return fmt.Errorf("设置语句超时失败: %w", err)
+	}
+	if explain {
+		_, err = tx.Exec(ctx, "EXPLAIN "+query)
+		return err
+	}
+	// 协议级的准备语句，只解析和分析语句，不执行
+	const name = "mysql2pg_check"
+	if _, err := tx.Conn().Prepare(ctx, name, query); err != nil {
+		return err
+	}
+	return tx.Conn().Deallocate(ctx, name)
+}
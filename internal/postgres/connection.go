@@ -8,17 +8,28 @@ import (
 	"math"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yourusername/mysql2pg/internal/config"
+	"github.com/yourusername/mysql2pg/internal/throttle"
 )
 
 // Connection PostgreSQL连接管理器
 type Connection struct {
 	pool   *pgxpool.Pool
 	config *config.PostgreSQLConfig
+	// 服务端版本号（server_version_num），创建连接时查询一次并缓存
+	versionNum int
+	versionErr error
+	// 数据写入事务是否以 replica 角色执行，由 EnableReplicaRole 在数据同步开始前设置
+	replicaRole bool
+}
+
+// UpsertOptions upsert写入选项
+type UpsertOptions struct {
+	ConflictColumns []string // 冲突判定列（主键）
+	UseMerge        bool     // 使用MERGE语句合并（PostgreSQL 15+），否则使用INSERT ... ON CONFLICT
 }
 
 // NewConnection 创建新的PostgreSQL连接
@@ -53,10 +64,13 @@ func NewConnection(config *config.PostgreSQLConfig) (*Connection, error) {
 		return nil, fmt.Errorf("PostgreSQL连接测试失败: %w", err)
 	}
 
-	return &Connection{
+	conn := &Connection{
 		pool:   pool,
 		config: config,
-	}, nil
+	}
+	// 查询失败时不影响连接，只是不使用依赖版本的特性（如MERGE）
+	conn.versionNum, conn.versionErr = conn.queryServerVersionNum(ctx)
+	return conn, nil
 }
 
 // Close 关闭连接池
@@ -75,9 +89,39 @@ func (c *Connection) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
 	return c.pool.Begin(ctx)
 }
 
+// EnableReplicaRole 启用以 replica 角色写入数据，之后由 BeginLoadTransaction 开始的事务不触发触发器和外键检查
+// 设置 session_replication_role 需要超级用户权限，无权限时返回错误且不启用
+func (c *Connection) EnableReplicaRole(ctx context.Context) error {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(ctx, "SET LOCAL session_replication_role = replica"); err != nil {
+		return fmt.Errorf("设置 session_replication_role 失败: %w", err)
+	}
+	c.replicaRole = true
+	return nil
+}
+
+// BeginLoadTransaction 开始数据写入事务，启用 replica 角色时在事务内设置 session_replication_role
+func (c *Connection) BeginLoadTransaction(ctx context.Context) (pgx.Tx, error) {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.replicaRole {
+		if _, err := tx.Exec(ctx, "SET LOCAL session_replication_role = replica"); err != nil {
+			tx.Rollback(context.Background())
+			return nil, fmt.Errorf("设置 session_replication_role 失败: %w", err)
+		}
+	}
+	return tx, nil
+}
+
 // ExecuteDDL 执行DDL语句
-func (c *Connection) ExecuteDDL(ddl string) error {
-	ctx := context.Background()
+func (c *Connection) ExecuteDDL(ctx context.Context, ddl string) error {
 	// 接受原始DDL，不转换为小写
 	lowercaseDDL := ddl
 	// 将char(0)转换为char(10)，因为PostgreSQL不允许char(0)类型
@@ -91,20 +135,19 @@ func (c *Connection) ExecuteDDL(ddl string) error {
 }
 
 // ExecuteDDLWithTransaction 在事务中执行DDL语句
-func (c *Connection) ExecuteDDLWithTransaction(tx pgx.Tx, ddl string) error {
+func (c *Connection) ExecuteDDLWithTransaction(ctx context.Context, tx pgx.Tx, ddl string) error {
 	// 将DDL转换为小写
 	lowercaseDDL := strings.ToLower(ddl)
 
 	// 将char(0)转换为char(10)，因为PostgreSQL不允许char(0)类型
 	lowercaseDDL = strings.ReplaceAll(lowercaseDDL, "char(0)", "char(10)")
 
-	_, err := tx.Exec(context.Background(), lowercaseDDL)
+	_, err := tx.Exec(ctx, lowercaseDDL)
 	return err
 }
 
 // InsertData 插入数据
-func (c *Connection) InsertData(tableName string, columns []string, rows *sql.Rows) error {
-	ctx := context.Background()
+func (c *Connection) InsertData(ctx context.Context, tableName string, columns []string, rows *sql.Rows) error {
 
 	// 构建占位符模板
 	placeholders := make([]string, len(columns))
@@ -149,8 +192,7 @@ func (c *Connection) InsertData(tableName string, columns []string, rows *sql.Ro
 }
 
 // InsertDataWithTransaction 在事务中插入数据
-func (c *Connection) InsertDataWithTransaction(tx pgx.Tx, tableName string, columns []string, rows *sql.Rows) error {
-	ctx := context.Background()
+func (c *Connection) InsertDataWithTransaction(ctx context.Context, tx pgx.Tx, tableName string, columns []string, rows *sql.Rows) error {
 
 	// 构建占位符模板
 	placeholders := make([]string, len(columns))
@@ -195,8 +237,7 @@ func (c *Connection) InsertDataWithTransaction(tx pgx.Tx, tableName string, colu
 }
 
 // BatchInsertDataWithTransaction 在事务中批量插入数据
-func (c *Connection) BatchInsertDataWithTransaction(tx pgx.Tx, tableName string, columns []string, batchSize int, rows *sql.Rows) error {
-	ctx := context.Background()
+func (c *Connection) BatchInsertDataWithTransaction(ctx context.Context, tx pgx.Tx, tableName string, columns []string, batchSize int, rows *sql.Rows) error {
 
 	// 构建列名字符串
 	var quotedColumns []string
@@ -331,8 +372,7 @@ func (c *Connection) executeBatchInsert(tx pgx.Tx, ctx context.Context, tableNam
 }
 
 // GetVersion 获取PostgreSQL版本信息
-func (c *Connection) GetVersion() (string, error) {
-	ctx := context.Background()
+func (c *Connection) GetVersion(ctx context.Context) (string, error) {
 	var version string
 	err := c.pool.QueryRow(ctx, "SELECT version()").Scan(&version)
 	if err != nil {
@@ -341,6 +381,30 @@ func (c *Connection) GetVersion() (string, error) {
 	return version, nil
 }
 
+// GetServerVersionNum 获取PostgreSQL服务端版本号，如 150004 表示 15.4，返回创建连接时缓存的值
+func (c *Connection) GetServerVersionNum() (int, error) {
+	return c.versionNum, c.versionErr
+}
+
+// queryServerVersionNum 查询PostgreSQL服务端版本号
+func (c *Connection) queryServerVersionNum(ctx context.Context) (int, error) {
+	var versionStr string
+	if err := c.pool.QueryRow(ctx, "SHOW server_version_num").Scan(&versionStr); err != nil {
+		return 0, fmt.Errorf("获取PostgreSQL版本号失败: %w", err)
+	}
+	versionNum, err := strconv.Atoi(strings.TrimSpace(versionStr))
+	if err != nil {
+		return 0, fmt.Errorf("解析PostgreSQL版本号失败: %w", err)
+	}
+	return versionNum, nil
+}
+
+// SupportsMerge 判断PostgreSQL是否支持MERGE语句（15及以上版本）
+func (c *Connection) SupportsMerge() bool {
+	versionNum, err := c.GetServerVersionNum()
+	return err == nil && versionNum >= 150000
+}
+
 // TestConnection 测试PostgreSQL连接
 func TestConnection(config *config.PostgreSQLConfig) error {
 	// 测试连接时不使用压缩
@@ -354,8 +418,7 @@ func TestConnection(config *config.PostgreSQLConfig) error {
 }
 
 // TableExists 检查表是否存在
-func (c *Connection) TableExists(tableName string) (bool, error) {
-	ctx := context.Background()
+func (c *Connection) TableExists(ctx context.Context, tableName string) (bool, error) {
 	query := `
 		SELECT EXISTS (
 			SELECT 1 
@@ -373,8 +436,7 @@ func (c *Connection) TableExists(tableName string) (bool, error) {
 }
 
 // GrantTablePrivileges 授予表权限
-func (c *Connection) GrantTablePrivileges(user, tableName string, privileges []string) error {
-	ctx := context.Background()
+func (c *Connection) GrantTablePrivileges(ctx context.Context, user, tableName string, privileges []string) error {
 
 	// 构建权限字符串
 	privilegesStr := strings.Join(privileges, ", ")
@@ -391,8 +453,7 @@ func (c *Connection) GrantTablePrivileges(user, tableName string, privileges []s
 }
 
 // GetTablePrivileges 获取表的权限信息
-func (c *Connection) GetTablePrivileges(tableName string) ([]map[string]string, error) {
-	ctx := context.Background()
+func (c *Connection) GetTablePrivileges(ctx context.Context, tableName string) ([]map[string]string, error) {
 
 	query := `
 		SELECT 
@@ -430,8 +491,7 @@ func (c *Connection) GetTablePrivileges(tableName string) ([]map[string]string,
 }
 
 // GetTableRowCount 获取表的行数
-func (c *Connection) GetTableRowCount(tableName string) (int64, error) {
-	ctx := context.Background()
+func (c *Connection) GetTableRowCount(ctx context.Context, tableName string) (int64, error) {
 	query := fmt.Sprintf("SELECT COUNT(*) FROM \"%s\"", tableName)
 
 	var count int64
@@ -443,9 +503,66 @@ func (c *Connection) GetTableRowCount(tableName string) (int64, error) {
 	return count, nil
 }
 
+// GetColumnMinMax 获取整数列的最小值和最大值，表为空时 Valid 为 false
+func (c *Connection) GetColumnMinMax(ctx context.Context, tableName, columnName string) (minValue, maxValue sql.NullString, err error) {
+	query := fmt.Sprintf("SELECT MIN(\"%s\")::TEXT, MAX(\"%s\")::TEXT FROM \"%s\"", columnName, columnName, tableName)
+	if err := c.pool.QueryRow(ctx, query).Scan(&minValue, &maxValue); err != nil {
+		return minValue, maxValue, fmt.Errorf("获取表 %s 列 %s 最小值和最大值失败: %w", tableName, columnName, err)
+	}
+	return minValue, maxValue, nil
+}
+
+// GetChecksum 计算满足条件的行数和与行顺序无关的校验和，算法与MySQL端一致
+// rowExpr 为行内容的规范化文本表达式，每行取MD5前15位十六进制转为整数后求和
+func (c *Connection) GetChecksum(ctx context.Context, tableName, rowExpr, filter string, filterArgs ...interface{}) (count int64, checksum string, err error) {
+	query := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(('x' || SUBSTR(MD5(%s), 1, 15))::BIT(60)::BIGINT), 0)::TEXT FROM \"%s\"",
+		rowExpr, tableName)
+	if filter != "" {
+		query += fmt.Sprintf(" WHERE %s", filter)
+	}
+	if err := c.pool.QueryRow(ctx, query, filterArgs...).Scan(&count, &checksum); err != nil {
+		return 0, "", fmt.Errorf("计算表 %s 校验和失败: %w", tableName, err)
+	}
+	return count, checksum, nil
+}
+
+// GetRowHashes 获取满足条件的每行主键值及其内容MD5，limit 为0时不限制行数
+func (c *Connection) GetRowHashes(ctx context.Context, tableName, keyColumn, rowExpr string, limit int, filter string, filterArgs ...interface{}) (map[string]string, error) {
+	query := fmt.Sprintf("SELECT \"%s\"::TEXT, MD5(%s) FROM \"%s\"", keyColumn, rowExpr, tableName)
+	if filter != "" {
+		query += fmt.Sprintf(" WHERE %s", filter)
+	}
+	query += fmt.Sprintf(" ORDER BY \"%s\"", keyColumn)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := c.pool.Query(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("获取表 %s 行校验值失败: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var key, hash string
+		if err := rows.Scan(&key, &hash); err != nil {
+			return nil, fmt.Errorf("扫描行校验值失败: %w", err)
+		}
+		hashes[key] = hash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("获取表 %s 行校验值失败: %w", tableName, err)
+	}
+
+	return hashes, nil
+}
+
 // BatchInsertDataWithTransactionAndGetLastValue 在事务中批量插入数据并获取最后一个主键值
-func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, tableName string, columns []string, columnTypes map[string]string, batchSize int, primaryKey string, rows *sql.Rows) (int, interface{}, error) {
-	ctx := context.Background()
+// upsert 不为空时以 upsert 方式写入：先COPY到临时中转表，再按冲突列合并到目标表
+// converter 为按目标列类型创建的行转换器，为nil时所有值按字符串写入
+// masker 不为空时在类型转换前对需要脱敏的列进行处理，limiter 不为空时按读取的数据量限速
+func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(ctx context.Context, tx pgx.Tx, tableName string, columns []string, converter *RowConverter, batchSize int, primaryKey string, upsert *UpsertOptions, masker *ColumnMasker, limiter *throttle.Limiter, rows *sql.Rows) (int, interface{}, error) {
 
 	// 准备批量插入
 	var rowCount int
@@ -479,6 +596,10 @@ func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, ta
 		}
 	}
 
+	if converter == nil {
+		converter = NewRowConverter(columns, nil, nil, nil, nil)
+	}
+
 	// 重用values和valuePtrs切片，减少内存分配
 	values := make([]interface{}, len(columns))
 	valuePtrs := make([]interface{}, len(columns))
@@ -486,6 +607,31 @@ func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, ta
 		valuePtrs[i] = &values[i]
 	}
 
+	// upsert模式下COPY的目标为临时中转表，每批写入后合并到目标表
+	copyTarget := pgx.Identifier{tableName}
+	var stagingTable string
+	if upsert != nil && len(upsert.ConflictColumns) > 0 {
+		var err error
+		stagingTable, err = c.createStagingTable(ctx, tx, tableName, lowercaseColumns)
+		if err != nil {
+			return 0, nil, err
+		}
+		copyTarget = pgx.Identifier{stagingTable}
+	}
+
+	// flushRows 将一批数据写入目标表（或中转表并合并）
+	flushRows := func(copyRows [][]interface{}) error {
+		// 执行CopyFrom，使用转换后的小写列名
+		_, err := tx.CopyFrom(ctx, copyTarget, lowercaseColumns, pgx.CopyFromRows(copyRows))
+		if err != nil {
+			return fmt.Errorf("CopyFrom执行失败: %w", err)
+		}
+		if stagingTable != "" {
+			return c.mergeStagingTable(ctx, tx, stagingTable, tableName, lowercaseColumns, upsert)
+		}
+		return nil
+	}
+
 	// 使用pgx的CopyFrom函数进行高效批量插入
 	copyRows := make([][]interface{}, 0, effectiveBatchSize)
 
@@ -501,50 +647,17 @@ func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, ta
 			lastValue = values[primaryKeyIndex]
 		}
 
-		// 复制当前行的值到新的切片并进行类型转换
-		rowValues := make([]interface{}, len(values))
-		for i, v := range values {
-			// 进行数据类型转换，处理MySQL和PostgreSQL之间的类型差异
-			switch val := v.(type) {
-			case []byte:
-				sVal := string(val)
-				// 检查是否为Point类型并尝试转换
-				if columnTypes != nil {
-					colType, ok := columnTypes[columns[i]]
-					if ok {
-						colTypeLower := strings.ToLower(colType)
-						if strings.Contains(colTypeLower, "point") || strings.Contains(colTypeLower, "geometry") {
-							if pointStr, err := parseMySQLPoint(val); err == nil {
-								rowValues[i] = pointStr
-								continue
-							}
-						}
-					}
-				}
+		// 按从MySQL读取的数据量限速
+		if err := limiter.Wait(ctx, RowByteSize(values), 1); err != nil {
+			return 0, nil, err
+		}
 
-				// 处理MySQL零值时间
-				if sVal == "0000-00-00 00:00:00" || sVal == "0000-00-00" {
-					rowValues[i] = nil
-				} else {
-					// 将[]byte转换为字符串，pgx会自动处理后续的类型转换
-					rowValues[i] = sVal
-				}
-			case string:
-				if val == "0000-00-00 00:00:00" || val == "0000-00-00" {
-					rowValues[i] = nil
-				} else {
-					rowValues[i] = val
-				}
-			case time.Time:
-				if val.IsZero() {
-					rowValues[i] = nil
-				} else {
-					rowValues[i] = val
-				}
-			default:
-				// 其他类型保持不变
-				rowValues[i] = val
-			}
+		// 列数据脱敏，在类型转换前处理原始值
+		masker.Apply(values)
+		// 复制当前行的值到新的切片并按目标列类型进行转换
+		rowValues := make([]interface{}, len(values))
+		if err := converter.Convert(values, rowValues); err != nil {
+			return 0, nil, fmt.Errorf("转换表 %s 的行数据失败: %w", tableName, err)
 		}
 		copyRows = append(copyRows, rowValues)
 
@@ -553,10 +666,8 @@ func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, ta
 
 		// 当达到批量大小时执行CopyFrom
 		if rowCount == effectiveBatchSize {
-			// 执行CopyFrom，使用转换后的小写列名
-			_, err := tx.CopyFrom(ctx, pgx.Identifier{tableName}, lowercaseColumns, pgx.CopyFromRows(copyRows))
-			if err != nil {
-				return 0, nil, fmt.Errorf("CopyFrom执行失败: %w", err)
+			if err := flushRows(copyRows); err != nil {
+				return 0, nil, err
 			}
 
 			// 重置切片和计数器
@@ -567,10 +678,8 @@ func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, ta
 
 	// 执行剩余的数据
 	if rowCount > 0 {
-		// 执行CopyFrom，使用转换后的小写列名
-		_, err := tx.CopyFrom(ctx, pgx.Identifier{tableName}, lowercaseColumns, pgx.CopyFromRows(copyRows))
-		if err != nil {
-			return 0, nil, fmt.Errorf("CopyFrom执行失败: %w", err)
+		if err := flushRows(copyRows); err != nil {
+			return 0, nil, err
 		}
 	}
 
@@ -592,6 +701,121 @@ func (c *Connection) BatchInsertDataWithTransactionAndGetLastValue(tx pgx.Tx, ta
 	return totalRows, lastValue, nil
 }
 
+// createStagingTable 在当前事务中创建只包含写入列的临时中转表，事务提交后自动删除
+// 列类型取自目标表，不复制NOT NULL等约束，未同步的列（如 exclude_columns 排除的列）不在中转表中
+func (c *Connection) createStagingTable(ctx context.Context, tx pgx.Tx, tableName string, columns []string) (string, error) {
+	// PostgreSQL标识符长度限制为63字节，截断表名部分并保留后缀，避免与目标表重名
+	const stagingSuffix = "_mysql2pg_stage"
+	stagingTable := truncateIdentifier(tableName, 63-len(stagingSuffix)) + stagingSuffix
+
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = fmt.Sprintf(`"%s"`, col)
+	}
+	query := fmt.Sprintf("CREATE TEMP TABLE IF NOT EXISTS \"%s\" ON COMMIT DROP AS SELECT %s FROM \"%s\" WITH NO DATA",
+		stagingTable, strings.Join(quotedColumns, ", "), tableName)
+	if _, err := tx.Exec(ctx, query); err != nil {
+		return "", fmt.Errorf("创建临时中转表 %s 失败: %w", stagingTable, err)
+	}
+
+	return stagingTable, nil
+}
+
+// truncateIdentifier 将标识符截断到不超过 maxBytes 字节，不拆分多字节的UTF-8字符
+func truncateIdentifier(name string, maxBytes int) string {
+	if len(name) <= maxBytes {
+		return name
+	}
+	// 在不超过 maxBytes 的最后一个字符起始位置截断
+	end := 0
+	for i := range name {
+		if i > maxBytes {
+			break
+		}
+		end = i
+	}
+	return name[:end]
+}
+
+// mergeStagingTable 将中转表中的数据按冲突列合并到目标表，并清空中转表
+func (c *Connection) mergeStagingTable(ctx context.Context, tx pgx.Tx, stagingTable, tableName string, columns []string, upsert *UpsertOptions) error {
+	conflictSet := make(map[string]bool, len(upsert.ConflictColumns))
+	quotedConflict := make([]string, 0, len(upsert.ConflictColumns))
+	var matchConditions []string
+	for _, col := range upsert.ConflictColumns {
+		col = strings.ToLower(col)
+		conflictSet[col] = true
+		quotedConflict = append(quotedConflict, fmt.Sprintf(`"%s"`, col))
+		matchConditions = append(matchConditions, fmt.Sprintf(`tgt."%s" = src."%s"`, col, col))
+	}
+
+	quotedColumns := make([]string, 0, len(columns))
+	sourceColumns := make([]string, 0, len(columns))
+	var updateColumns []string
+	for _, col := range columns {
+		quotedColumns = append(quotedColumns, fmt.Sprintf(`"%s"`, col))
+		sourceColumns = append(sourceColumns, fmt.Sprintf(`src."%s"`, col))
+		if !conflictSet[col] {
+			updateColumns = append(updateColumns, col)
+		}
+	}
+	columnsStr := strings.Join(quotedColumns, ", ")
+
+	var query string
+	if upsert.UseMerge {
+		// MERGE按主键列关联，不要求目标表上存在唯一约束
+		var updateSet []string
+		for _, col := range updateColumns {
+			updateSet = append(updateSet, fmt.Sprintf(`"%s" = src."%s"`, col, col))
+		}
+		query = fmt.Sprintf("MERGE INTO \"%s\" AS tgt USING \"%s\" AS src ON %s",
+			tableName, stagingTable, strings.Join(matchConditions, " AND "))
+		if len(updateSet) > 0 {
+			query += " WHEN MATCHED THEN UPDATE SET " + strings.Join(updateSet, ", ")
+		}
+		query += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", columnsStr, strings.Join(sourceColumns, ", "))
+	} else {
+		// 所有列都是冲突列时没有需要更新的列，冲突时直接忽略
+		conflictAction := "DO NOTHING"
+		if len(updateColumns) > 0 {
+			var updateSet []string
+			for _, col := range updateColumns {
+				updateSet = append(updateSet, fmt.Sprintf(`"%s" = EXCLUDED."%s"`, col, col))
+			}
+			conflictAction = "DO UPDATE SET " + strings.Join(updateSet, ", ")
+		}
+		query = fmt.Sprintf("INSERT INTO \"%s\" (%s) SELECT %s FROM \"%s\" ON CONFLICT (%s) %s",
+			tableName, columnsStr, columnsStr, stagingTable, strings.Join(quotedConflict, ", "), conflictAction)
+	}
+
+	if _, err := tx.Exec(ctx, query); err != nil {
+		return fmt.Errorf("合并中转表数据到表 %s 失败: %w", tableName, err)
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("TRUNCATE \"%s\"", stagingTable)); err != nil {
+		return fmt.Errorf("清空临时中转表 %s 失败: %w", stagingTable, err)
+	}
+
+	return nil
+}
+
+// RowByteSize 估算一行数据从MySQL读取的字节数
+func RowByteSize(values []interface{}) int {
+	size := 0
+	for _, v := range values {
+		switch val := v.(type) {
+		case nil:
+		case []byte:
+			size += len(val)
+		case string:
+			size += len(val)
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
 // parseMySQLPoint 解析MySQL的WKB格式Point数据
 func parseMySQLPoint(data []byte) (string, error) {
 	// MySQL Geometry Header (4 bytes SRID) + WKB (1 byte order + 4 bytes type + 16 bytes coords)
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CopyTextEncoder 将行转换器输出的值编码为 COPY ... FROM stdin 文本格式的一行
+// 每列按PostgreSQL目标列类型使用pgx的文本编码，与直接写入时的值转换一致；目标列类型未知时按文本编码
+type CopyTextEncoder struct {
+	typeMap *pgtype.Map
+	oids    []uint32
+	buf     []byte
+}
+
+// NewCopyTextEncoder 创建COPY文本格式编码器，pgColumnTypes 的键为小写列名，值为类型名（如 int4、numeric、timestamp）
+func NewCopyTextEncoder(columns []string, pgColumnTypes map[string]string) *CopyTextEncoder {
+	e := &CopyTextEncoder{typeMap: pgtype.NewMap(), oids: make([]uint32, len(columns)), buf: make([]byte, 0, 256)}
+	for i, col := range columns {
+		e.oids[i] = pgtype.TextOID
+		if t, ok := e.typeMap.TypeForName(pgColumnTypes[strings.ToLower(col)]); ok {
+			e.oids[i] = t.OID
+		}
+	}
+	return e
+}
+
+// EncodeRow 编码一行数据，追加到 dst 并返回，行以换行符结束
+// NULL 写为 \N，值中的反斜杠、换行、回车和制表符按COPY文本格式转义
+func (e *CopyTextEncoder) EncodeRow(dst []byte, values []interface{}) ([]byte, error) {
+	for i, v := range values {
+		if i > 0 {
+			dst = append(dst, '\t')
+		}
+		encoded, err := e.typeMap.Encode(e.oids[i], pgtype.TextFormatCode, v, e.buf[:0])
+		if err != nil {
+			return dst, fmt.Errorf("编码第 %d 列失败: %w", i+1, err)
+		}
+		if encoded == nil {
+			dst = append(dst, '\\', 'N')
+			continue
+		}
+		dst = appendCopyText(dst, encoded)
+		e.buf = encoded
+	}
+	return append(dst, '\n'), nil
+}
+
+// appendCopyText 按COPY文本格式转义特殊字符
+func appendCopyText(dst, value []byte) []byte {
+	for _, b := range value {
+		switch b {
+		case '\\':
+			dst = append(dst, '\\', '\\')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(dst, b)
+		}
+	}
+	return dst
+}
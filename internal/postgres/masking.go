@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/yourusername/mysql2pg/internal/config"
+)
+
+// 字符类型的长度限制，如 varchar(64)、char(11)
+var reMaskTypeLength = regexp.MustCompile(`(?i)^(?:var)?char\((\d+)\)`)
+
+// 二进制类型，如 binary(16)、varbinary(255)、blob
+var reMaskBinaryType = regexp.MustCompile(`(?i)^(?:(?:var)?binary|(?:tiny|medium|long)?blob)\b(?:\((\d+)\))?`)
+
+// maskColumn 单列的脱敏处理
+type maskColumn struct {
+	index     int
+	name      string
+	rule      config.MaskingRule
+	intBits   uint  // 整数列的位数，0 表示非整数列
+	binary    bool  // 二进制列，哈希结果按字节写入
+	maxLength int   // 字符列的最大长度或二进制列的最大字节数，0 表示不限制
+	count     int64 // 已脱敏的值数量
+}
+
+// ColumnMasker 单表的列数据脱敏处理器，相同盐值下同一个值的脱敏结果始终相同，跨表关联的列仍可匹配
+type ColumnMasker struct {
+	salt    []byte
+	columns []*maskColumn
+}
+
+// MaskedColumn 脱敏审计信息
+type MaskedColumn struct {
+	Column string // 列名
+	Action string // 脱敏方式
+	Values int64  // 已脱敏的非NULL值数量
+}
+
+// NewColumnMasker 根据脱敏配置创建表的脱敏处理器，表中没有需要脱敏的列时返回nil
+// 规则不适用于列类型时返回错误，此时还没有读取任何数据
+func NewColumnMasker(cfg *config.MaskingConfig, tableName string, columns []string, columnTypes map[string]string) (*ColumnMasker, error) {
+	if cfg == nil || len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+
+	masker := &ColumnMasker{salt: []byte(cfg.Salt)}
+	for i, col := range columns {
+		rule := cfg.FindRule(tableName, col)
+		if rule == nil {
+			continue
+		}
+
+		if err := rule.CheckColumnType(columnTypes[col]); err != nil {
+			return nil, err
+		}
+
+		mc := &maskColumn{index: i, name: col, rule: *rule}
+		colType := strings.ToLower(strings.TrimSpace(columnTypes[col]))
+		switch {
+		case strings.HasPrefix(colType, "tinyint(1)"):
+			// tinyint(1) 转换为 BOOLEAN，不按整数处理
+		case strings.HasPrefix(colType, "tinyint"):
+			mc.intBits = 8
+		case strings.HasPrefix(colType, "smallint"):
+			mc.intBits = 16
+		case strings.HasPrefix(colType, "mediumint"):
+			mc.intBits = 24
+		case strings.HasPrefix(colType, "int"):
+			mc.intBits = 32
+		case strings.HasPrefix(colType, "bigint"):
+			mc.intBits = 64
+		}
+		if match := reMaskTypeLength.FindStringSubmatch(colType); match != nil {
+			mc.maxLength, _ = strconv.Atoi(match[1])
+		}
+		if match := reMaskBinaryType.FindStringSubmatch(colType); match != nil {
+			mc.binary = true
+			mc.maxLength, _ = strconv.Atoi(match[1])
+		}
+
+		masker.columns = append(masker.columns, mc)
+	}
+
+	if len(masker.columns) == 0 {
+		return nil, nil
+	}
+	return masker, nil
+}
+
+// Apply 对一行从MySQL读取的原始数据中需要脱敏的列进行处理，NULL值保持不变
+// 应在类型转换前调用，脱敏结果再按列类型转换，与未脱敏的值以相同格式写入
+func (m *ColumnMasker) Apply(values []interface{}) {
+	if m == nil {
+		return
+	}
+	for _, mc := range m.columns {
+		value := values[mc.index]
+		if value == nil {
+			continue
+		}
+		values[mc.index] = m.maskValue(mc, value)
+		atomic.AddInt64(&mc.count, 1)
+	}
+}
+
+// MaskedColumns 获取脱敏审计信息
+func (m *ColumnMasker) MaskedColumns() []MaskedColumn {
+	if m == nil {
+		return nil
+	}
+	var result []MaskedColumn
+	for _, mc := range m.columns {
+		result = append(result, MaskedColumn{
+			Column: mc.name,
+			Action: mc.rule.Action,
+			Values: atomic.LoadInt64(&mc.count),
+		})
+	}
+	return result
+}
+
+// maskValue 按规则处理单个值
+func (m *ColumnMasker) maskValue(mc *maskColumn, value interface{}) interface{} {
+	text := maskValueString(value)
+
+	switch mc.rule.Action {
+	case config.MaskActionNull:
+		return nil
+	case config.MaskActionConstant:
+		return mc.rule.Value
+	case config.MaskActionTruncate:
+		runes := []rune(text)
+		if len(runes) > mc.rule.Length {
+			return string(runes[:mc.rule.Length])
+		}
+		return text
+	case config.MaskActionHash:
+		if mc.intBits > 0 {
+			// 整数列使用加密置换，结果仍在同类型范围内且不会重复，主键和唯一键脱敏后保持唯一
+			if n, ok := parseMaskInt(text); ok {
+				return m.permuteInt(n, mc.intBits)
+			}
+		}
+		if mc.binary {
+			// 二进制列写入摘要的字节，binary(16) 等定长列截断到列长度，转换为uuid的列仍为16字节
+			hashed := m.digest(text)
+			if mc.maxLength > 0 && len(hashed) > mc.maxLength {
+				hashed = hashed[:mc.maxLength]
+			}
+			return hashed
+		}
+		hashed := hex.EncodeToString(m.digest(text))
+		if mc.maxLength > 0 && len(hashed) > mc.maxLength {
+			hashed = hashed[:mc.maxLength]
+		}
+		return hashed
+	case config.MaskActionFormat:
+		return m.randomizeFormat(text)
+	}
+	return value
+}
+
+// digest 计算加盐的HMAC-SHA256
+func (m *ColumnMasker) digest(text string) []byte {
+	mac := hmac.New(sha256.New, m.salt)
+	mac.Write([]byte(text))
+	return mac.Sum(nil)
+}
+
+// permuteInt 以盐值为密钥的Feistel置换，将 bits 位整数一一映射到同位数的整数
+func (m *ColumnMasker) permuteInt(value int64, bits uint) int64 {
+	half := bits / 2
+	halfMask := uint64(1)<<half - 1
+
+	x := uint64(value)
+	if bits < 64 {
+		x &= uint64(1)<<bits - 1
+	}
+	left, right := x>>half, x&halfMask
+	for round := 0; round < 4; round++ {
+		f := binary.BigEndian.Uint64(m.digest(fmt.Sprintf("%d:%d", round, right))[:8]) & halfMask
+		left, right = right, left^f
+	}
+
+	// 按位数进行符号扩展，结果落在有符号整数范围内
+	shift := 64 - bits
+	return int64((left<<half|right)<<shift) >> shift
+}
+
+// parseMaskInt 解析整数值，超出int64范围的无符号整数按位重新解释
+func parseMaskInt(text string) (int64, bool) {
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n, true
+	}
+	if n, err := strconv.ParseUint(text, 10, 64); err == nil {
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// randomizeFormat 保留格式的随机化：数字替换为数字，字母替换为同大小写的字母，其他字符保持不变
+// 邮箱保留最后一级域名，如 alice@example.com 变为 xkfpq@qmzrwsd.com
+func (m *ColumnMasker) randomizeFormat(text string) string {
+	runes := []rune(text)
+	keepFrom := len(runes)
+	if at := strings.LastIndex(text, "@"); at != -1 {
+		if dot := strings.LastIndex(text, "."); dot > at {
+			keepFrom = len([]rune(text[:dot]))
+		}
+	}
+
+	stream := m.digest(text)
+	next := 0
+	randomByte := func() byte {
+		if next == len(stream) {
+			// 摘要用完后以上一段摘要继续派生
+			stream = m.digest(hex.EncodeToString(stream))
+			next = 0
+		}
+		b := stream[next]
+		next++
+		return b
+	}
+
+	for i := 0; i < keepFrom; i++ {
+		r := runes[i]
+		switch {
+		case r >= '0' && r <= '9':
+			runes[i] = rune('0' + randomByte()%10)
+		case r >= 'a' && r <= 'z':
+			runes[i] = rune('a' + randomByte()%26)
+		case r >= 'A' && r <= 'Z':
+			runes[i] = rune('A' + randomByte()%26)
+		case unicode.IsLetter(r):
+			// 非ASCII字母（如中文姓名）替换为小写字母
+			runes[i] = rune('a' + randomByte()%26)
+		}
+	}
+	return string(runes)
+}
+
+// maskValueString 将值转换为用于脱敏计算的字符串
+func maskValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999")
+	case bool:
+		// 与MySQL中 tinyint(1) 的取值一致
+		if v {
+			return "1"
+		}
+		return "0"
+	case driver.Valuer:
+		// pgtype.Numeric 等类型使用其文本形式
+		if dv, err := v.Value(); err == nil && dv != nil {
+			return fmt.Sprint(dv)
+		}
+		return fmt.Sprint(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
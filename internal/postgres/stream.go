@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// StreamRow 数据流中的一行数据
+type StreamRow struct {
+	Values     []interface{} // 已转换的列值
+	PrimaryKey string        // MySQL中的主键值，多列主键以逗号分隔，用于定位被隔离的行
+	Rejected   string        // 读取端转换时发现的问题，不为空时该行不写入而直接隔离
+}
+
+// RejectedRow 写入失败被隔离的行
+type RejectedRow struct {
+	StreamRow
+	Error string // PostgreSQL返回的错误
+}
+
+// RowStream 读取端和写入端之间的有界行数据通道
+// 读取端逐行发送已转换的数据，结束时调用 Close；写入端出错放弃读取时调用 Stop，使读取端尽快退出
+type RowStream struct {
+	rows    chan StreamRow
+	stopped chan struct{}
+	err     error // 读取端的错误，通道关闭后可读
+}
+
+// NewRowStream 创建行数据通道，capacity 为通道中最多缓存的行数
+func NewRowStream(capacity int) *RowStream {
+	if capacity <= 0 {
+		capacity = 10000 // 默认值
+	}
+	return &RowStream{
+		rows:    make(chan StreamRow, capacity),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Send 发送一行数据，写入端已停止时返回 false
+func (s *RowStream) Send(row StreamRow) bool {
+	select {
+	case s.rows <- row:
+		return true
+	case <-s.stopped:
+		return false
+	}
+}
+
+// Close 读取端结束发送，err 不为nil时写入端中止当前批次并返回该错误
+func (s *RowStream) Close(err error) {
+	s.err = err
+	close(s.rows)
+}
+
+// Next 读取下一行，读取端结束发送后返回 false，此时可通过 Err 获取读取端的错误
+func (s *RowStream) Next() (StreamRow, bool) {
+	row, ok := <-s.rows
+	return row, ok
+}
+
+// Err 读取端的错误，Next 返回 false 后调用
+func (s *RowStream) Err() error {
+	return s.err
+}
+
+// Stop 写入端停止接收数据，只能调用一次
+func (s *RowStream) Stop() {
+	close(s.stopped)
+}
+
+// streamSource 从行数据通道读取最多 limit 行，实现 pgx.CopyFromSource
+type streamSource struct {
+	stream  *RowStream
+	limit   int
+	count   int
+	current StreamRow
+	eof     bool
+}
+
+// Next 读取下一行，达到行数限制或通道关闭时返回 false
+func (s *streamSource) Next() bool {
+	if s.count >= s.limit {
+		return false
+	}
+	row, ok := <-s.stream.rows
+	if !ok {
+		s.eof = true
+		return false
+	}
+	s.current = row
+	s.count++
+	return true
+}
+
+// Values 返回当前行
+func (s *streamSource) Values() ([]interface{}, error) {
+	return s.current.Values, nil
+}
+
+// Err 读取端出错时中止COPY
+func (s *streamSource) Err() error {
+	if s.eof {
+		return s.stream.err
+	}
+	return nil
+}
+
+// CopyFromStream 在事务中从行数据流读取最多 limit 行写入表，每 batchSize 行执行一次COPY
+// 数据边从MySQL读取边写入，不在内存中缓存整批数据；upsert 不为空时先COPY到临时中转表再合并到目标表
+// reject 不为空时启用坏行隔离：每批数据缓存在内存中，写入失败时二分定位出错的行交给 reject 处理，其余行正常写入
+// 返回从数据流读取的行数（含被隔离的行），数据流已结束时 eof 为 true
+func (c *Connection) CopyFromStream(ctx context.Context, tx pgx.Tx, tableName string, columns []string, batchSize int, upsert *UpsertOptions, stream *RowStream, limit int, reject func(row RejectedRow) error) (int, bool, error) {
+
+	if batchSize <= 0 {
+		batchSize = 10000 // 默认值
+	}
+
+	// 将所有列名转换为小写，以匹配PostgreSQL的默认行为
+	lowercaseColumns := make([]string, len(columns))
+	for i, col := range columns {
+		lowercaseColumns[i] = strings.ToLower(col)
+	}
+
+	// upsert模式下COPY的目标为临时中转表，每批写入后合并到目标表
+	copyTarget := pgx.Identifier{tableName}
+	var stagingTable string
+	if upsert != nil && len(upsert.ConflictColumns) > 0 {
+		var err error
+		stagingTable, err = c.createStagingTable(ctx, tx, tableName, lowercaseColumns)
+		if err != nil {
+			return 0, false, err
+		}
+		copyTarget = pgx.Identifier{stagingTable}
+	}
+
+	// load 写入一批数据，upsert模式下同时合并到目标表
+	load := func(db pgx.Tx, source pgx.CopyFromSource) error {
+		copied, err := db.CopyFrom(ctx, copyTarget, lowercaseColumns, source)
+		if err != nil {
+			return fmt.Errorf("CopyFrom执行失败: %w", err)
+		}
+		if stagingTable != "" && copied > 0 {
+			return c.mergeStagingTable(ctx, db, stagingTable, tableName, lowercaseColumns, upsert)
+		}
+		return nil
+	}
+
+	var totalRows int
+	for totalRows < limit {
+		chunk := batchSize
+		if remaining := limit - totalRows; remaining < chunk {
+			chunk = remaining
+		}
+
+		source := &streamSource{stream: stream, limit: chunk}
+		if reject == nil {
+			if err := load(tx, source); err != nil {
+				return 0, false, err
+			}
+		} else {
+			// 读取整批数据，写入失败时需要重试；转换时已发现问题的行直接隔离
+			var rows []StreamRow
+			for source.Next() {
+				if source.current.Rejected != "" {
+					if err := reject(RejectedRow{StreamRow: source.current, Error: source.current.Rejected}); err != nil {
+						return 0, false, err
+					}
+					continue
+				}
+				rows = append(rows, source.current)
+			}
+			if err := source.Err(); err != nil {
+				return 0, false, err
+			}
+			if len(rows) > 0 {
+				if err := loadWithQuarantine(ctx, tx, rows, load, reject); err != nil {
+					return 0, false, err
+				}
+			}
+		}
+		totalRows += source.count
+
+		if source.eof {
+			return totalRows, true, nil
+		}
+	}
+
+	return totalRows, false, nil
+}
+
+// loadWithQuarantine 在保存点中写入一批数据，因数据问题失败时回滚到保存点，将数据分成两半分别重试，直到定位到单行
+func loadWithQuarantine(ctx context.Context, tx pgx.Tx, rows []StreamRow, load func(db pgx.Tx, source pgx.CopyFromSource) error, reject func(row RejectedRow) error) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("创建保存点失败: %w", err)
+	}
+
+	loadErr := load(savepoint, pgx.CopyFromSlice(len(rows), func(i int) ([]interface{}, error) {
+		return rows[i].Values, nil
+	}))
+	if loadErr == nil {
+		if err := savepoint.Commit(ctx); err != nil {
+			return fmt.Errorf("释放保存点失败: %w", err)
+		}
+		return nil
+	}
+
+	if err := savepoint.Rollback(ctx); err != nil {
+		return fmt.Errorf("回滚到保存点失败: %w（写入错误: %v）", err, loadErr)
+	}
+	if !isRowDataError(loadErr) {
+		return loadErr
+	}
+
+	if len(rows) == 1 {
+		return reject(RejectedRow{StreamRow: rows[0], Error: rejectErrorMessage(loadErr)})
+	}
+
+	mid := len(rows) / 2
+	if err := loadWithQuarantine(ctx, tx, rows[:mid], load, reject); err != nil {
+		return err
+	}
+	return loadWithQuarantine(ctx, tx, rows[mid:], load, reject)
+}
+
+// isRowDataError 判断写入错误是否由行数据本身引起：数据异常（22类）、完整性约束冲突（23类），
+// 或pgx在客户端编码值时的错误；连接和语法等其他错误不进行隔离
+func isRowDataError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")
+	}
+	return !pgconn.SafeToRetry(err) && !pgconn.Timeout(err)
+}
+
+// rejectErrorMessage 提取PostgreSQL错误的关键信息
+func rejectErrorMessage(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		msg := fmt.Sprintf("%s (SQLSTATE %s)", pgErr.Message, pgErr.Code)
+		if pgErr.Where != "" {
+			msg += ": " + pgErr.Where
+		}
+		return msg
+	}
+	return err.Error()
+}
@@ -0,0 +1,527 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yourusername/mysql2pg/internal/config"
+)
+
+// MySQL文本协议返回的时间格式
+const (
+	mysqlDateTimeLayout = "2006-01-02 15:04:05.999999999"
+	mysqlDateLayout     = "2006-01-02"
+)
+
+var (
+	// 日期部分，如 2023-02-30 或 2023-02-30 10:00:00
+	reMySQLDate = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})(.*)$`)
+	// TIME值，如 -12:00:00 或 838:59:59.000000
+	reMySQLTime = regexp.MustCompile(`^(-?)(\d+):(\d{2}):(\d{2})(\.\d+)?$`)
+	// BIT列的位数，如 bit(10)
+	reMySQLBitWidth = regexp.MustCompile(`^bit\((\d+)\)`)
+)
+
+// 无效数据的问题类型
+const (
+	problemNulBytes = iota
+	problemInvalidUTF8
+	problemZeroDate
+	problemInvalidDate
+	problemTimeOverflow
+	problemInvalidJSON
+	problemCount
+)
+
+// 问题类型在配置和审计中的名称
+var problemNames = [problemCount]string{"nul_bytes", "invalid_utf8", "zero_date", "invalid_date", "time_overflow", "invalid_json"}
+
+// valueConverter 将MySQL返回的原始值转换为PostgreSQL目标列对应的Go类型
+type valueConverter func(raw []byte) interface{}
+
+// rejectedValue 转换时已确定PostgreSQL不会接受的值，所在行不写入目标表
+type rejectedValue struct {
+	value  string // 原始值，记录在隔离行中
+	reason string
+}
+
+// RowConverter 单表的行数据类型转换器，每列的转换函数在创建时按MySQL列类型和PostgreSQL目标列类型确定
+// 转换后的整数、浮点数、numeric、时间、bytea、布尔值、位串和uuid可由pgx直接以二进制格式写入COPY，
+// PostgreSQL不必再逐个解析文本，无法转换的值保持为字符串，由pgx按文本解析
+// 转换时按无效数据处理策略修正PostgreSQL不接受的值，并统计各类问题的数量
+type RowConverter struct {
+	columns    []string
+	converters []valueConverter
+	policies   [problemCount]string
+	fixes      [problemCount]int64
+}
+
+// DataFix 一类无效数据的处理统计
+type DataFix struct {
+	Problem string // 问题类型，如 zero_date
+	Action  string // 处理策略
+	Values  int64  // 发现的值数量
+}
+
+// GetColumnTypes 从系统目录获取表中各列的类型名（如 int4、numeric、timestamp）
+func (c *Connection) GetColumnTypes(ctx context.Context, tableName string) (map[string]string, error) {
+	query := "SELECT column_name, udt_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1"
+	rows, err := c.pool.Query(ctx, query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("获取表 %s 列类型失败: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	columnTypes := make(map[string]string)
+	for rows.Next() {
+		var column, udtName string
+		if err := rows.Scan(&column, &udtName); err != nil {
+			return nil, fmt.Errorf("扫描表 %s 列类型失败: %w", tableName, err)
+		}
+		columnTypes[column] = udtName
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("获取表 %s 列类型失败: %w", tableName, err)
+	}
+
+	return columnTypes, nil
+}
+
+// NewRowConverter 根据MySQL列类型和PostgreSQL表的列类型创建行数据类型转换器
+func (c *Connection) NewRowConverter(ctx context.Context, tableName string, columns []string, mysqlColumnTypes map[string]string, uuidColumns map[string]bool, invalidData *config.InvalidDataConfig) (*RowConverter, error) {
+	pgColumnTypes, err := c.GetColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return NewRowConverter(columns, mysqlColumnTypes, pgColumnTypes, uuidColumns, invalidData), nil
+}
+
+// NewRowConverter 创建行数据类型转换器，pgColumnTypes 和 uuidColumns 的键为小写列名
+// uuidColumns 的值表示该UUID列是否以 UUID_TO_BIN(uuid, 1) 的字节顺序存储
+// 目标列类型未知时按字符串传递，与之前的文本写入方式一致；invalidData 为nil时零值日期转换为NULL，其他问题原样写入
+func NewRowConverter(columns []string, mysqlColumnTypes, pgColumnTypes map[string]string, uuidColumns map[string]bool, invalidData *config.InvalidDataConfig) *RowConverter {
+	rc := &RowConverter{columns: columns, converters: make([]valueConverter, len(columns))}
+	for i := range rc.policies {
+		rc.policies[i] = config.InvalidDataError
+	}
+	rc.policies[problemZeroDate] = config.InvalidDataNull
+	if invalidData != nil {
+		rc.policies = [problemCount]string{invalidData.NulBytes, invalidData.InvalidUTF8, invalidData.ZeroDate, invalidData.InvalidDate, invalidData.TimeOverflow, invalidData.InvalidJSON}
+	}
+
+	for i, col := range columns {
+		mysqlType := strings.ToLower(mysqlColumnTypes[col])
+		pgType := pgColumnTypes[strings.ToLower(col)]
+		rc.converters[i] = rc.columnConverter(mysqlType, pgType, uuidColumns[strings.ToLower(col)])
+	}
+	return rc
+}
+
+// columnConverter 按列类型选择转换函数，uuidSwapFlag 为UUID列是否交换了时间字段的字节顺序
+func (rc *RowConverter) columnConverter(mysqlType, pgType string, uuidSwapFlag bool) valueConverter {
+	if strings.Contains(mysqlType, "point") || strings.Contains(mysqlType, "geometry") {
+		return rc.convertPoint
+	}
+
+	switch pgType {
+	case "int2":
+		return rc.intConverter(16)
+	case "int4":
+		return rc.intConverter(32)
+	case "int8":
+		return rc.intConverter(64)
+	case "float4", "float8":
+		return rc.convertFloat
+	case "numeric":
+		return rc.convertNumeric
+	case "bool":
+		return rc.convertBool
+	case "bytea":
+		return convertBytes
+	case "varbit", "bit":
+		width := 0
+		if match := reMySQLBitWidth.FindStringSubmatch(mysqlType); match != nil {
+			width, _ = strconv.Atoi(match[1])
+		}
+		return rc.bitConverter(width)
+	case "uuid":
+		return rc.uuidConverter(uuidSwapFlag)
+	case "json":
+		return rc.jsonConverter(false)
+	case "jsonb":
+		return rc.jsonConverter(true)
+	case "timestamp":
+		return rc.dateConverter(mysqlDateTimeLayout, true)
+	case "date":
+		return rc.dateConverter(mysqlDateLayout, true)
+	case "timestamptz":
+		// timestamptz 依赖会话时区解释MySQL的无时区时间，仍按文本写入
+		return rc.dateConverter(mysqlDateTimeLayout, false)
+	case "time":
+		return rc.convertTime
+	}
+	return rc.convertText
+}
+
+// Convert 转换一行数据，结果写入 out，out 与 values 长度相同
+// 行中有PostgreSQL不会接受、按策略不应写入的值（如无效的JSON文档）时返回错误，调用方应隔离该行或使同步失败
+func (rc *RowConverter) Convert(values, out []interface{}) error {
+	var rejectErr error
+	for i, v := range values {
+		switch val := v.(type) {
+		case []byte:
+			out[i] = rc.converters[i](val)
+		case string:
+			out[i] = rc.converters[i]([]byte(val))
+		case time.Time:
+			if val.IsZero() {
+				out[i] = nil
+			} else {
+				out[i] = val
+			}
+		default:
+			// 其他类型保持不变
+			out[i] = val
+		}
+
+		if rejected, ok := out[i].(rejectedValue); ok {
+			out[i] = rejected.value
+			if rejectErr == nil {
+				rejectErr = fmt.Errorf("列 %s: %s", rc.columns[i], rejected.reason)
+			}
+		}
+	}
+	return rejectErr
+}
+
+// DataFixes 获取各类无效数据的处理统计，只包含发现过问题的类型
+func (rc *RowConverter) DataFixes() []DataFix {
+	if rc == nil {
+		return nil
+	}
+	var result []DataFix
+	for problem := range rc.fixes {
+		if count := atomic.LoadInt64(&rc.fixes[problem]); count > 0 {
+			result = append(result, DataFix{Problem: problemNames[problem], Action: rc.policies[problem], Values: count})
+		}
+	}
+	return result
+}
+
+// record 记录发现的一个无效值，返回该类问题的处理策略
+func (rc *RowConverter) record(problem int) string {
+	atomic.AddInt64(&rc.fixes[problem], 1)
+	return rc.policies[problem]
+}
+
+// isMySQLZeroTime 是否为MySQL零值时间，如 0000-00-00 或 0000-00-00 00:00:00.000000
+func isMySQLZeroTime(s string) bool {
+	if !strings.HasPrefix(s, "0000-00-00") {
+		return false
+	}
+	return strings.Trim(s[len("0000-00-00"):], " 0:.") == ""
+}
+
+// convertText 按字符串传递，pgx会自动处理后续的类型转换
+// 文本中的 \x00 和无效的UTF-8字节序列按策略处理
+func (rc *RowConverter) convertText(raw []byte) interface{} {
+	s := string(raw)
+	if isMySQLZeroTime(s) {
+		return rc.zeroDate(s, mysqlDateTimeLayout, false)
+	}
+
+	if strings.IndexByte(s, 0) != -1 {
+		switch rc.record(problemNulBytes) {
+		case config.InvalidDataNull:
+			return nil
+		case config.InvalidDataReplace:
+			s = strings.ReplaceAll(s, "\x00", "\uFFFD")
+		case config.InvalidDataStrip:
+			s = strings.ReplaceAll(s, "\x00", "")
+		}
+	}
+
+	if !utf8.ValidString(s) {
+		switch rc.record(problemInvalidUTF8) {
+		case config.InvalidDataNull:
+			return nil
+		case config.InvalidDataReplace:
+			s = strings.ToValidUTF8(s, "\uFFFD")
+		case config.InvalidDataStrip:
+			s = strings.ToValidUTF8(s, "")
+		}
+	}
+
+	return s
+}
+
+// convertPoint 将MySQL的WKB格式Point转换为PostgreSQL的Point文本
+func (rc *RowConverter) convertPoint(raw []byte) interface{} {
+	if pointStr, err := parseMySQLPoint(raw); err == nil {
+		return pointStr
+	}
+	return rc.convertText(raw)
+}
+
+// convertBytes 二进制数据原样写入bytea
+func convertBytes(raw []byte) interface{} {
+	return raw
+}
+
+// intConverter 按目标列位数解析整数，超出范围的值按文本写入，由PostgreSQL报告错误
+func (rc *RowConverter) intConverter(bits int) valueConverter {
+	return func(raw []byte) interface{} {
+		n, err := strconv.ParseInt(string(raw), 10, bits)
+		if err != nil {
+			return rc.convertText(raw)
+		}
+		return n
+	}
+}
+
+// convertFloat 解析浮点数
+func (rc *RowConverter) convertFloat(raw []byte) interface{} {
+	f, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return rc.convertText(raw)
+	}
+	return f
+}
+
+// convertNumeric 将MySQL的DECIMAL文本（如 -123.4500）转换为 pgtype.Numeric，不经过浮点数，精度不变
+func (rc *RowConverter) convertNumeric(raw []byte) interface{} {
+	s := string(raw)
+	digits := s
+	var exp int32
+	if dot := strings.IndexByte(s, '.'); dot != -1 {
+		digits = s[:dot] + s[dot+1:]
+		exp = -int32(len(s) - dot - 1)
+	}
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		// 科学计数法等其他格式按文本写入
+		return rc.convertText(raw)
+	}
+	return pgtype.Numeric{Int: n, Exp: exp, Valid: true}
+}
+
+// convertBool 将 tinyint(1) 的数字文本或 bit(1) 的单字节值转换为布尔值
+func (rc *RowConverter) convertBool(raw []byte) interface{} {
+	if len(raw) == 1 && raw[0] <= 1 {
+		return raw[0] == 1
+	}
+	n, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return rc.convertText(raw)
+	}
+	return n != 0
+}
+
+// bitConverter 将MySQL BIT(width) 列的值转换为 pgtype.Bits
+// MySQL按大端序返回 (width+7)/8 个字节，有效位在低位；PostgreSQL的位串从第一个字节的最高位开始
+func (rc *RowConverter) bitConverter(width int) valueConverter {
+	return func(raw []byte) interface{} {
+		n := width
+		if n <= 0 {
+			n = len(raw) * 8
+		}
+		if len(raw) != (n+7)/8 {
+			return rc.convertText(raw)
+		}
+
+		bits := make([]byte, len(raw))
+		for i := 0; i < n; i++ {
+			// 第 i 位在MySQL值中从最低位算起的位置
+			pos := n - 1 - i
+			if raw[len(raw)-1-pos/8]>>(pos%8)&1 == 1 {
+				bits[i/8] |= 0x80 >> (i % 8)
+			}
+		}
+		return pgtype.Bits{Bytes: bits, Len: int32(n), Valid: true}
+	}
+}
+
+// uuidConverter 将 BINARY(16) 中的UUID转换为 pgtype.UUID，长度不是16字节的值按文本写入
+// swapFlag 为 true 时按 UUID_TO_BIN(uuid, 1) 的存储顺序（time_high、time_mid、time_low）还原为标准顺序
+func (rc *RowConverter) uuidConverter(swapFlag bool) valueConverter {
+	return func(raw []byte) interface{} {
+		if len(raw) != 16 {
+			return rc.convertText(raw)
+		}
+		var uuid [16]byte
+		if swapFlag {
+			copy(uuid[0:4], raw[4:8])
+			copy(uuid[4:6], raw[2:4])
+			copy(uuid[6:8], raw[0:2])
+			copy(uuid[8:], raw[8:])
+		} else {
+			copy(uuid[:], raw)
+		}
+		return pgtype.UUID{Bytes: uuid, Valid: true}
+	}
+}
+
+// jsonConverter 校验写入json/jsonb列的JSON文档，无效的文档按策略处理
+// 文本中的 \x00 和无效的UTF-8字节序列先按文本处理；jsonb 不支持 \u0000 转义，按 \x00 的策略处理
+func (rc *RowConverter) jsonConverter(binary bool) valueConverter {
+	return func(raw []byte) interface{} {
+		value := rc.convertText(raw)
+		s, ok := value.(string)
+		if !ok {
+			return value
+		}
+
+		if !json.Valid([]byte(s)) {
+			if rc.record(problemInvalidJSON) == config.InvalidDataNull {
+				return nil
+			}
+			return rejectedValue{value: s, reason: "无效的JSON文档"}
+		}
+
+		if binary && strings.Contains(s, `\u0000`) {
+			switch rc.record(problemNulBytes) {
+			case config.InvalidDataNull:
+				return nil
+			case config.InvalidDataReplace:
+				s = replaceJSONNulEscapes(s, `\ufffd`)
+			case config.InvalidDataStrip:
+				s = replaceJSONNulEscapes(s, "")
+			}
+		}
+		return s
+	}
+}
+
+// replaceJSONNulEscapes 替换JSON文档中的 \u0000 转义，不替换 \\u0000 这样转义后的反斜杠加文本
+func replaceJSONNulEscapes(s, replacement string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if strings.HasPrefix(s[i:], `\u0000`) {
+			b.WriteString(replacement)
+			i += len(`\u0000`) - 1
+			continue
+		}
+		// 其他转义序列原样保留，跳过被转义的字符
+		b.WriteByte(s[i])
+		if i+1 < len(s) {
+			i++
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// dateConverter 转换无时区的日期时间，零值日期和不存在的日期按策略处理
+// typed 为 true 时解析为 time.Time 以二进制格式写入，否则按文本写入
+func (rc *RowConverter) dateConverter(layout string, typed bool) valueConverter {
+	return func(raw []byte) interface{} {
+		s := string(raw)
+		if isMySQLZeroTime(s) {
+			return rc.zeroDate(s, layout, typed)
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			fixed, invalid := clampDate(s)
+			if !invalid {
+				// 其他无法解析的格式由PostgreSQL处理
+				return s
+			}
+			switch rc.record(problemInvalidDate) {
+			case config.InvalidDataNull:
+				return nil
+			case config.InvalidDataClamp:
+				s = fixed
+				if t, err = time.Parse(layout, s); err != nil || !typed {
+					return s
+				}
+				return t
+			default:
+				return s
+			}
+		}
+		if !typed {
+			return s
+		}
+		return t
+	}
+}
+
+// zeroDate 按策略处理零值日期，clamp 时调整为 0001-01-01
+func (rc *RowConverter) zeroDate(s, layout string, typed bool) interface{} {
+	switch rc.record(problemZeroDate) {
+	case config.InvalidDataClamp:
+		t := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+		if typed {
+			return t
+		}
+		return t.Format(layout)
+	case config.InvalidDataError:
+		return s
+	default:
+		return nil
+	}
+}
+
+// clampDate 检查日期部分是否存在，不存在时返回调整到最接近的有效日期后的值
+// 年份为0调整为1，月份调整到1~12，日期调整到当月的有效范围，如 2023-02-30 调整为 2023-02-28
+func clampDate(s string) (string, bool) {
+	match := reMySQLDate.FindStringSubmatch(s)
+	if match == nil {
+		return s, false
+	}
+	year, _ := strconv.Atoi(match[1])
+	month, _ := strconv.Atoi(match[2])
+	day, _ := strconv.Atoi(match[3])
+
+	fixedYear, fixedMonth, fixedDay := max(year, 1), min(max(month, 1), 12), max(day, 1)
+	// 下个月第0天即当月最后一天
+	lastDay := time.Date(fixedYear, time.Month(fixedMonth)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	fixedDay = min(fixedDay, lastDay)
+
+	if fixedYear == year && fixedMonth == month && fixedDay == day {
+		return s, false
+	}
+	return fmt.Sprintf("%04d-%02d-%02d%s", fixedYear, fixedMonth, fixedDay, match[4]), true
+}
+
+// convertTime 转换TIME值，PostgreSQL的time类型范围为 00:00:00~24:00:00，超出范围的值按策略处理
+func (rc *RowConverter) convertTime(raw []byte) interface{} {
+	s := string(raw)
+	match := reMySQLTime.FindStringSubmatch(s)
+	if match == nil {
+		return rc.convertText(raw)
+	}
+
+	hours, _ := strconv.Atoi(match[2])
+	negative := match[1] == "-" && strings.Trim(match[2]+match[3]+match[4]+match[5], "0.") != ""
+	overflow := hours > 24 || (hours == 24 && strings.Trim(match[3]+match[4]+match[5], "0.") != "")
+	if !negative && !overflow {
+		return s
+	}
+
+	switch rc.record(problemTimeOverflow) {
+	case config.InvalidDataNull:
+		return nil
+	case config.InvalidDataClamp:
+		if negative {
+			return "00:00:00"
+		}
+		return "24:00:00"
+	default:
+		return s
+	}
+}
@@ -0,0 +1,298 @@
+package postgres
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yourusername/mysql2pg/internal/config"
+)
+
+// defaultInvalidData 与 ValidateConfig 填充的默认值相同的无效数据处理策略
+func defaultInvalidData() config.InvalidDataConfig {
+	return config.InvalidDataConfig{
+		NulBytes:     config.InvalidDataError,
+		InvalidUTF8:  config.InvalidDataError,
+		ZeroDate:     config.InvalidDataNull,
+		InvalidDate:  config.InvalidDataError,
+		TimeOverflow: config.InvalidDataError,
+		InvalidJSON:  config.InvalidDataError,
+	}
+}
+
+func TestConvertNumeric(t *testing.T) {
+	tests := []struct {
+		raw    string
+		digits string
+		exp    int32
+		text   bool // 无法解析，按文本写入
+	}{
+		{raw: "123.4500", digits: "1234500", exp: -4},
+		{raw: "-0.01", digits: "-1", exp: -2},
+		{raw: "42", digits: "42", exp: 0},
+		{raw: "99999999999999999999999999999999.999999", digits: "99999999999999999999999999999999999999", exp: -6},
+		{raw: "1e5", text: true},
+	}
+
+	rc := NewRowConverter(nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got := rc.convertNumeric([]byte(tt.raw))
+			if tt.text {
+				if got != tt.raw {
+					t.Fatalf("convertNumeric(%q) = %#v，期望按文本写入", tt.raw, got)
+				}
+				return
+			}
+			n, ok := got.(pgtype.Numeric)
+			if !ok {
+				t.Fatalf("convertNumeric(%q) 返回 %T，期望 pgtype.Numeric", tt.raw, got)
+			}
+			if !n.Valid || n.Int.String() != tt.digits || n.Exp != tt.exp {
+				t.Fatalf("convertNumeric(%q) = %s×10^%d，期望 %s×10^%d", tt.raw, n.Int, n.Exp, tt.digits, tt.exp)
+			}
+		})
+	}
+}
+
+func TestBitConverter(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		raw   []byte
+		want  interface{}
+	}{
+		{name: "bit(8)", width: 8, raw: []byte{0xA5}, want: pgtype.Bits{Bytes: []byte{0xA5}, Len: 8, Valid: true}},
+		// b'1000000001'：MySQL有效位在低位，PostgreSQL从第一个字节的最高位开始
+		{name: "bit(10)", width: 10, raw: []byte{0x02, 0x01}, want: pgtype.Bits{Bytes: []byte{0x80, 0x40}, Len: 10, Valid: true}},
+		{name: "bit(3)", width: 3, raw: []byte{0x05}, want: pgtype.Bits{Bytes: []byte{0xA0}, Len: 3, Valid: true}},
+		{name: "未知位数", width: 0, raw: []byte{0x01, 0x00}, want: pgtype.Bits{Bytes: []byte{0x01, 0x00}, Len: 16, Valid: true}},
+		{name: "字节数不匹配", width: 10, raw: []byte{0x01}, want: "\x01"},
+	}
+
+	rc := NewRowConverter(nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rc.bitConverter(tt.width)(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("bitConverter(%d)(%x) = %#v，期望 %#v", tt.width, tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUUIDConverter(t *testing.T) {
+	raw := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	tests := []struct {
+		name     string
+		swapFlag bool
+		raw      []byte
+		want     interface{}
+	}{
+		{name: "标准顺序", raw: raw, want: pgtype.UUID{Bytes: [16]byte(raw), Valid: true}},
+		{
+			name:     "UUID_TO_BIN(uuid, 1)",
+			swapFlag: true,
+			raw:      raw,
+			want:     pgtype.UUID{Bytes: [16]byte{0x04, 0x05, 0x06, 0x07, 0x02, 0x03, 0x00, 0x01, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}, Valid: true},
+		},
+		{name: "长度不是16字节", raw: []byte("abc"), want: "abc"},
+	}
+
+	rc := NewRowConverter(nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rc.uuidConverter(tt.swapFlag)(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("uuidConverter(%v)(%x) = %#v，期望 %#v", tt.swapFlag, tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClampDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		invalid bool
+	}{
+		{in: "2023-02-30", want: "2023-02-28", invalid: true},
+		{in: "2023-02-29 10:00:00", want: "2023-02-28 10:00:00", invalid: true},
+		{in: "2024-02-29", want: "2024-02-29"},
+		{in: "2023-13-05 01:02:03.5", want: "2023-12-05 01:02:03.5", invalid: true},
+		{in: "2023-04-00", want: "2023-04-01", invalid: true},
+		{in: "0000-06-15", want: "0001-06-15", invalid: true},
+		{in: "2023-04-31", want: "2023-04-30", invalid: true},
+		{in: "not a date", want: "not a date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, invalid := clampDate(tt.in)
+			if got != tt.want || invalid != tt.invalid {
+				t.Fatalf("clampDate(%q) = (%q, %v)，期望 (%q, %v)", tt.in, got, invalid, tt.want, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestInvalidDataPolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		mysqlType string
+		pgType    string
+		policy    func(c *config.InvalidDataConfig) // 修改默认策略
+		raw       string
+		want      interface{}
+		rejected  bool   // Convert 返回错误，该行应隔离
+		problem   string // DataFixes 中记录的问题类型，为空表示没有发现问题
+	}{
+		{name: "nul_bytes error", mysqlType: "varchar(10)", pgType: "varchar", raw: "a\x00b", want: "a\x00b", problem: "nul_bytes"},
+		{name: "nul_bytes null", mysqlType: "varchar(10)", pgType: "varchar", policy: func(c *config.InvalidDataConfig) { c.NulBytes = config.InvalidDataNull }, raw: "a\x00b", want: nil, problem: "nul_bytes"},
+		{name: "nul_bytes replace", mysqlType: "varchar(10)", pgType: "varchar", policy: func(c *config.InvalidDataConfig) { c.NulBytes = config.InvalidDataReplace }, raw: "a\x00b", want: "a�b", problem: "nul_bytes"},
+		{name: "nul_bytes strip", mysqlType: "varchar(10)", pgType: "varchar", policy: func(c *config.InvalidDataConfig) { c.NulBytes = config.InvalidDataStrip }, raw: "a\x00b", want: "ab", problem: "nul_bytes"},
+
+		{name: "invalid_utf8 error", mysqlType: "text", pgType: "text", raw: "a\xffb", want: "a\xffb", problem: "invalid_utf8"},
+		{name: "invalid_utf8 null", mysqlType: "text", pgType: "text", policy: func(c *config.InvalidDataConfig) { c.InvalidUTF8 = config.InvalidDataNull }, raw: "a\xffb", want: nil, problem: "invalid_utf8"},
+		{name: "invalid_utf8 replace", mysqlType: "text", pgType: "text", policy: func(c *config.InvalidDataConfig) { c.InvalidUTF8 = config.InvalidDataReplace }, raw: "a\xffb", want: "a�b", problem: "invalid_utf8"},
+		{name: "invalid_utf8 strip", mysqlType: "text", pgType: "text", policy: func(c *config.InvalidDataConfig) { c.InvalidUTF8 = config.InvalidDataStrip }, raw: "a\xffb", want: "ab", problem: "invalid_utf8"},
+
+		{name: "zero_date null", mysqlType: "datetime", pgType: "timestamp", raw: "0000-00-00 00:00:00", want: nil, problem: "zero_date"},
+		{name: "zero_date clamp", mysqlType: "datetime", pgType: "timestamp", policy: func(c *config.InvalidDataConfig) { c.ZeroDate = config.InvalidDataClamp }, raw: "0000-00-00 00:00:00", want: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), problem: "zero_date"},
+		{name: "zero_date error", mysqlType: "date", pgType: "date", policy: func(c *config.InvalidDataConfig) { c.ZeroDate = config.InvalidDataError }, raw: "0000-00-00", want: "0000-00-00", problem: "zero_date"},
+
+		{name: "invalid_date error", mysqlType: "date", pgType: "date", raw: "2023-02-30", want: "2023-02-30", problem: "invalid_date"},
+		{name: "invalid_date null", mysqlType: "date", pgType: "date", policy: func(c *config.InvalidDataConfig) { c.InvalidDate = config.InvalidDataNull }, raw: "2023-02-30", want: nil, problem: "invalid_date"},
+		{name: "invalid_date clamp", mysqlType: "datetime", pgType: "timestamp", policy: func(c *config.InvalidDataConfig) { c.InvalidDate = config.InvalidDataClamp }, raw: "2023-02-30 12:30:00", want: time.Date(2023, 2, 28, 12, 30, 0, 0, time.UTC), problem: "invalid_date"},
+		{name: "invalid_date clamp timestamptz", mysqlType: "timestamp", pgType: "timestamptz", policy: func(c *config.InvalidDataConfig) { c.InvalidDate = config.InvalidDataClamp }, raw: "2023-02-30 12:30:00", want: "2023-02-28 12:30:00", problem: "invalid_date"},
+		{name: "有效日期", mysqlType: "datetime", pgType: "timestamp", raw: "2024-02-29 08:00:00.123456", want: time.Date(2024, 2, 29, 8, 0, 0, 123456000, time.UTC)},
+
+		{name: "time_overflow error", mysqlType: "time", pgType: "time", raw: "838:59:59", want: "838:59:59", problem: "time_overflow"},
+		{name: "time_overflow null", mysqlType: "time", pgType: "time", policy: func(c *config.InvalidDataConfig) { c.TimeOverflow = config.InvalidDataNull }, raw: "838:59:59", want: nil, problem: "time_overflow"},
+		{name: "time_overflow clamp", mysqlType: "time", pgType: "time", policy: func(c *config.InvalidDataConfig) { c.TimeOverflow = config.InvalidDataClamp }, raw: "838:59:59", want: "24:00:00", problem: "time_overflow"},
+		{name: "time_overflow clamp 负值", mysqlType: "time", pgType: "time", policy: func(c *config.InvalidDataConfig) { c.TimeOverflow = config.InvalidDataClamp }, raw: "-01:00:00", want: "00:00:00", problem: "time_overflow"},
+		{name: "24:00:00", mysqlType: "time", pgType: "time", raw: "24:00:00", want: "24:00:00"},
+
+		{name: "invalid_json error", mysqlType: "json", pgType: "jsonb", raw: `{"a":`, want: `{"a":`, rejected: true, problem: "invalid_json"},
+		{name: "invalid_json null", mysqlType: "json", pgType: "json", policy: func(c *config.InvalidDataConfig) { c.InvalidJSON = config.InvalidDataNull }, raw: `{"a":`, want: nil, problem: "invalid_json"},
+		{name: "jsonb \\u0000 strip", mysqlType: "json", pgType: "jsonb", policy: func(c *config.InvalidDataConfig) { c.NulBytes = config.InvalidDataStrip }, raw: `{"a":"x\u0000y"}`, want: `{"a":"xy"}`, problem: "nul_bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalidData := defaultInvalidData()
+			if tt.policy != nil {
+				tt.policy(&invalidData)
+			}
+			rc := NewRowConverter([]string{"c"}, map[string]string{"c": tt.mysqlType}, map[string]string{"c": tt.pgType}, nil, &invalidData)
+
+			out := make([]interface{}, 1)
+			err := rc.Convert([]interface{}{[]byte(tt.raw)}, out)
+			if (err != nil) != tt.rejected {
+				t.Fatalf("Convert(%q) 错误为 %v，期望隔离该行: %v", tt.raw, err, tt.rejected)
+			}
+			if !reflect.DeepEqual(out[0], tt.want) {
+				t.Fatalf("Convert(%q) = %#v，期望 %#v", tt.raw, out[0], tt.want)
+			}
+
+			fixes := rc.DataFixes()
+			if tt.problem == "" {
+				if len(fixes) != 0 {
+					t.Fatalf("不应记录无效数据，实际记录 %+v", fixes)
+				}
+				return
+			}
+			if len(fixes) != 1 || fixes[0].Problem != tt.problem || fixes[0].Values != 1 {
+				t.Fatalf("DataFixes() = %+v，期望记录1个 %s", fixes, tt.problem)
+			}
+		})
+	}
+}
+
+// wideNumericTable 宽数值表的列：int8、numeric、float8、int4 交替，共48列
+func wideNumericTable() (columns []string, mysqlTypes, pgTypes map[string]string, oids []uint32, row []interface{}) {
+	mysqlTypes = make(map[string]string)
+	pgTypes = make(map[string]string)
+	kinds := []struct {
+		mysqlType, pgType string
+		oid               uint32
+		value             func(i int) string
+	}{
+		{"bigint", "int8", pgtype.Int8OID, func(i int) string { return strconv.Itoa(9000000000 + i) }},
+		{"decimal(18,4)", "numeric", pgtype.NumericOID, func(i int) string { return fmt.Sprintf("%d.%04d", 123456+i, i) }},
+		{"double", "float8", pgtype.Float8OID, func(i int) string { return fmt.Sprintf("%d.25", i) }},
+		{"int", "int4", pgtype.Int4OID, func(i int) string { return strconv.Itoa(i * 1000) }},
+	}
+	for i := 0; i < 48; i++ {
+		kind := kinds[i%len(kinds)]
+		col := fmt.Sprintf("c%d", i)
+		columns = append(columns, col)
+		mysqlTypes[col] = kind.mysqlType
+		pgTypes[col] = kind.pgType
+		oids = append(oids, kind.oid)
+		row = append(row, []byte(kind.value(i)))
+	}
+	return columns, mysqlTypes, pgTypes, oids, row
+}
+
+// encodeCopyRow 按pgx CopyFrom的方式以二进制格式编码一行：先直接编码，
+// 字符串无法编码时按文本解析后再编码（即之前所有值都以字符串传递时的路径）
+func encodeCopyRow(m *pgtype.Map, oids []uint32, values []interface{}, buf []byte) ([]byte, error) {
+	for i, value := range values {
+		encoded, err := m.Encode(oids[i], pgtype.BinaryFormatCode, value, buf)
+		if err != nil {
+			s, ok := value.(string)
+			if !ok {
+				return nil, err
+			}
+			var parsed interface{}
+			if err := m.Scan(oids[i], pgtype.TextFormatCode, []byte(s), &parsed); err != nil {
+				return nil, err
+			}
+			if encoded, err = m.Encode(oids[i], pgtype.BinaryFormatCode, parsed, buf); err != nil {
+				return nil, err
+			}
+		}
+		buf = encoded
+	}
+	return buf, nil
+}
+
+// BenchmarkWideNumericRow 比较宽数值表一行数据写入COPY的编码开销：
+// 按目标列类型转换后直接编码，与之前将所有值转换为字符串后由pgx解析再编码
+func BenchmarkWideNumericRow(b *testing.B) {
+	columns, mysqlTypes, pgTypes, oids, raw := wideNumericTable()
+	m := pgtype.NewMap()
+
+	b.Run("RowConverter", func(b *testing.B) {
+		rc := NewRowConverter(columns, mysqlTypes, pgTypes, nil, nil)
+		out := make([]interface{}, len(raw))
+		buf := make([]byte, 0, 4096)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := rc.Convert(raw, out); err != nil {
+				b.Fatal(err)
+			}
+			var err error
+			if buf, err = encodeCopyRow(m, oids, out, buf[:0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("String", func(b *testing.B) {
+		out := make([]interface{}, len(raw))
+		buf := make([]byte, 0, 4096)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j, v := range raw {
+				out[j] = string(v.([]byte))
+			}
+			var err error
+			if buf, err = encodeCopyRow(m, oids, out, buf[:0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package throttle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limiter 令牌桶限速器，按字节数和行数两个维度限速，由所有数据同步协程共享
+// 限速值为0表示不限制，运行期间可通过 SetLimits 调整
+type Limiter struct {
+	mutex       sync.Mutex
+	bytesPerSec float64 // 每秒字节数上限
+	rowsPerSec  float64 // 每秒行数上限
+	byteTokens  float64
+	rowTokens   float64
+	last        time.Time
+
+	// 吞吐量统计
+	start      time.Time
+	totalBytes int64
+	totalRows  int64
+}
+
+// Stats 吞吐量统计信息
+type Stats struct {
+	Bytes          int64         `json:"bytes"`            // 读取的字节数
+	Rows           int64         `json:"rows"`             // 读取的行数
+	Elapsed        time.Duration `json:"elapsed"`          // 从第一次到最后一次读取数据的时长
+	BandwidthMbps  int           `json:"bandwidth_mbps"`   // 当前带宽限制
+	RowsPerSecond  int           `json:"rows_per_second"`  // 当前行数限制
+	EffectiveMbps  float64       `json:"effective_mbps"`   // 实际带宽
+	EffectiveRowsS float64       `json:"effective_rows_s"` // 实际每秒行数
+}
+
+// NewLimiter 创建限速器，bandwidthMbps 为带宽上限（Mbps），rowsPerSecond 为每秒行数上限
+func NewLimiter(bandwidthMbps, rowsPerSecond int) *Limiter {
+	l := &Limiter{}
+	l.SetLimits(bandwidthMbps, rowsPerSecond)
+	return l
+}
+
+// SetLimits 调整限速值，对正在运行的同步立即生效
+func (l *Limiter) SetLimits(bandwidthMbps, rowsPerSecond int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.bytesPerSec = 0
+	if bandwidthMbps > 0 {
+		l.bytesPerSec = float64(bandwidthMbps) * 1000 * 1000 / 8
+	}
+	l.rowsPerSec = 0
+	if rowsPerSecond > 0 {
+		l.rowsPerSec = float64(rowsPerSecond)
+	}
+
+	// 调整后桶内最多保留1秒的令牌，避免调低限速后出现突发
+	if l.byteTokens > l.bytesPerSec {
+		l.byteTokens = l.bytesPerSec
+	}
+	if l.rowTokens > l.rowsPerSec {
+		l.rowTokens = l.rowsPerSec
+	}
+}
+
+// Limits 获取当前限速值
+func (l *Limiter) Limits() (bandwidthMbps, rowsPerSecond int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return int(l.bytesPerSec * 8 / 1000 / 1000), int(l.rowsPerSec)
+}
+
+// Wait 读取 bytes 字节、rows 行数据后调用，超过限速时阻塞到令牌足够为止
+// ctx 被取消时立即返回 ctx 的错误，运行中断时不必等待限速
+func (l *Limiter) Wait(ctx context.Context, bytes, rows int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	if l.start.IsZero() {
+		l.start = now
+		l.last = now
+	}
+	l.totalBytes += int64(bytes)
+	l.totalRows += int64(rows)
+
+	// 按经过的时间补充令牌，桶容量为1秒的令牌数
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	var wait float64
+	if l.bytesPerSec > 0 {
+		l.byteTokens += elapsed * l.bytesPerSec
+		if l.byteTokens > l.bytesPerSec {
+			l.byteTokens = l.bytesPerSec
+		}
+		// 允许令牌为负，欠下的令牌由调用方等待偿还，多个协程按调用顺序排队
+		l.byteTokens -= float64(bytes)
+		if l.byteTokens < 0 {
+			wait = -l.byteTokens / l.bytesPerSec
+		}
+	}
+	if l.rowsPerSec > 0 {
+		l.rowTokens += elapsed * l.rowsPerSec
+		if l.rowTokens > l.rowsPerSec {
+			l.rowTokens = l.rowsPerSec
+		}
+		l.rowTokens -= float64(rows)
+		if l.rowTokens < 0 {
+			if rowWait := -l.rowTokens / l.rowsPerSec; rowWait > wait {
+				wait = rowWait
+			}
+		}
+	}
+	l.mutex.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(wait * float64(time.Second)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats 获取吞吐量统计信息
+func (l *Limiter) Stats() Stats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stats := Stats{
+		Bytes:         l.totalBytes,
+		Rows:          l.totalRows,
+		BandwidthMbps: int(l.bytesPerSec * 8 / 1000 / 1000),
+		RowsPerSecond: int(l.rowsPerSec),
+	}
+	if !l.start.IsZero() {
+		// 统计到最后一次读取数据的时间，不包含同步结束后的其他阶段
+		stats.Elapsed = l.last.Sub(l.start)
+	}
+	if seconds := stats.Elapsed.Seconds(); seconds > 0 {
+		stats.EffectiveMbps = float64(l.totalBytes) * 8 / 1000 / 1000 / seconds
+		stats.EffectiveRowsS = float64(l.totalRows) / seconds
+	}
+	return stats
+}
+
+// ServeHTTP 运行期间查看和调整限速值
+// GET 返回当前限速和吞吐量，只有 POST 携带 bandwidth_mbps、rows_per_second 参数时才调整限速
+func (l *Limiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if r.URL.Query().Has("bandwidth_mbps") || r.URL.Query().Has("rows_per_second") {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "调整限速需要使用 POST 请求", http.StatusMethodNotAllowed)
+			return
+		}
+	case http.MethodPost:
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "不支持的请求方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bandwidthMbps, rowsPerSecond := l.Limits()
+	changed := false
+	for name, target := range map[string]*int{"bandwidth_mbps": &bandwidthMbps, "rows_per_second": &rowsPerSecond} {
+		value := r.FormValue(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("无效的参数 %s: %s", name, value), http.StatusBadRequest)
+			return
+		}
+		*target = n
+		changed = true
+	}
+	if changed {
+		l.SetLimits(bandwidthMbps, rowsPerSecond)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(l.Stats())
+}
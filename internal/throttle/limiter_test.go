@@ -0,0 +1,36 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterWaitCancelled(t *testing.T) {
+	l := NewLimiter(0, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// 每秒1行的限速下读取100行需要等待约100秒，取消后应立即返回
+	start := time.Now()
+	err := l.Wait(ctx, 0, 100)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() 错误 = %v，期望 context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Wait() 取消后 %v 才返回", elapsed)
+	}
+}
+
+func TestLimiterWaitWithinLimit(t *testing.T) {
+	l := NewLimiter(0, 1000)
+	if err := l.Wait(context.Background(), 0, 1); err != nil {
+		t.Fatalf("Wait() 错误 = %v", err)
+	}
+
+	var nilLimiter *Limiter
+	if err := nilLimiter.Wait(context.Background(), 1<<20, 1000); err != nil {
+		t.Fatalf("nil 限速器 Wait() 错误 = %v", err)
+	}
+}